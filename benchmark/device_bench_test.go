@@ -29,17 +29,19 @@ func BenchmarkReserveDevice(b *testing.B) {
 
 func BenchmarkReleaseDevice(b *testing.B) {
 	pool := device.NewDevicePool("iphone", b.N)
+	tokens := make([]string, b.N)
 
 	for i := 0; i < b.N; i++ {
-		pool.Reserve(fmt.Sprintf("user%d", i), "iphone", 5*time.Minute)
+		dev, _ := pool.Reserve(fmt.Sprintf("user%d", i), "iphone", 5*time.Minute)
+		tokens[i] = dev.LeaseToken
 	}
 
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		ok := pool.Release(fmt.Sprintf("iphone-%d", i))
-		if !ok {
-			b.Fatalf("release failed at %d", i)
+		err := pool.Release(fmt.Sprintf("iphone-%d", i), "", tokens[i])
+		if err != nil {
+			b.Fatalf("release failed at %d: %v", i, err)
 		}
 	}
 }
@@ -81,9 +83,10 @@ func BenchmarkConcurrentReserveRelease(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		i := 0
 		for pb.Next() {
-			dev, ok := pool.Reserve(fmt.Sprintf("user%d", i), "iphone", 1*time.Second)
+			user := fmt.Sprintf("user%d", i)
+			dev, ok := pool.Reserve(user, "iphone", 1*time.Second)
 			if ok {
-				pool.Release(dev.ID)
+				pool.Release(dev.ID, user, "")
 			}
 			i++
 		}
@@ -103,9 +106,10 @@ func BenchmarkHighContention(b *testing.B) {
 		go func(workerID int) {
 			defer wg.Done()
 			for i := 0; i < opsPerWorker; i++ {
-				dev, ok := pool.Reserve(fmt.Sprintf("w%d-u%d", workerID, i), "iphone", 50*time.Millisecond)
+				user := fmt.Sprintf("w%d-u%d", workerID, i)
+				dev, ok := pool.Reserve(user, "iphone", 50*time.Millisecond)
 				if ok {
-					pool.Release(dev.ID)
+					pool.Release(dev.ID, user, "")
 				}
 			}
 		}(w)
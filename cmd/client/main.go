@@ -1,34 +1,86 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
 	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
 )
 
+const (
+	watchRetryDelay = 2 * time.Second
+	defaultServer   = "http://localhost:8080"
+)
+
+// creds are the saved login, loaded once in main.
+var creds credentials
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	baseURL := "http://localhost:8080"
-	client := protoconnect.NewDeviceServiceClient(http.DefaultClient, baseURL)
+	creds = loadCredentials()
+	authClient := httpClient(creds.Token)
+	client := protoconnect.NewDeviceServiceClient(authClient, creds.Server)
+	clientV2 := protov2connect.NewDeviceServiceClient(authClient, creds.Server)
 
 	switch os.Args[1] {
+	case "login":
+		handleLogin(creds, os.Args[2:])
+	case "logout":
+		handleLogout(creds)
+	case "whoami":
+		handleWhoAmI(clientV2)
 	case "reserve":
 		handleReserve(client, os.Args[2:])
 	case "release":
 		handleRelease(client, os.Args[2:])
+	case "extend":
+		handleExtend(client, os.Args[2:])
 	case "watch":
-		handleWatch(client)
+		handleWatch(client, os.Args[2:])
+	case "reserve-batch":
+		handleReserveBatch(clientV2, os.Args[2:])
+	case "release-batch":
+		handleReleaseBatch(clientV2, os.Args[2:])
+	case "extend-batch":
+		handleExtendBatch(clientV2, os.Args[2:])
+	case "list":
+		handleList(clientV2, os.Args[2:])
+	case "show":
+		handleShow(clientV2, os.Args[2:])
+	case "usage":
+		handleUsage(clientV2, os.Args[2:])
+	case "book":
+		handleBook(clientV2, os.Args[2:])
+	case "bookings":
+		handleBookings(clientV2, os.Args[2:])
+	case "cancel-booking":
+		handleCancelBooking(clientV2, os.Args[2:])
+	case "report-failure":
+		handleReportFailure(clientV2, os.Args[2:])
+	case "admin":
+		handleAdmin(protov2connect.NewAdminServiceClient(authClient, creds.Server), clientV2, os.Args[2:])
 	default:
 		printUsage()
 		os.Exit(1)
@@ -37,78 +89,965 @@ func main() {
 
 func printUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  go run cmd/client/main.go reserve --user USER --type TYPE")
-	fmt.Println("  go run cmd/client/main.go release --device-id ID")
-	fmt.Println("  go run cmd/client/main.go watch")
+	fmt.Println("  go run cmd/client/main.go login [--server URL] [--token TOKEN]")
+	fmt.Println("  go run cmd/client/main.go logout")
+	fmt.Println("  go run cmd/client/main.go whoami")
+	fmt.Println("  go run cmd/client/main.go reserve --user USER --type TYPE [--selector EXPR] [--priority N] [--ttl DURATION] [--wait [--timeout DURATION] [--preempt]]")
+	fmt.Println("  go run cmd/client/main.go release --device-id ID --lease TOKEN")
+	fmt.Println("  go run cmd/client/main.go extend --device-id ID --lease TOKEN --by DURATION")
+	fmt.Println("  go run cmd/client/main.go watch [--type TYPE]... [--device-id ID]... [--label KEY=VALUE]... [--user USER]")
+	fmt.Println("  go run cmd/client/main.go reserve-batch --user USER --device TYPE[:SELECTOR]... [--ttl DURATION]")
+	fmt.Println("  go run cmd/client/main.go release-batch --lease GROUP_LEASE")
+	fmt.Println("  go run cmd/client/main.go extend-batch --lease GROUP_LEASE --by DURATION")
+	fmt.Println("  go run cmd/client/main.go list [--type TYPE]... [--state STATE]... [--label KEY=VALUE]...")
+	fmt.Println("  go run cmd/client/main.go show --device-id ID")
+	fmt.Println("  go run cmd/client/main.go usage [--user USER]")
+	fmt.Println("  go run cmd/client/main.go book --device-id ID --user USER --start TIME (--end TIME | --for DURATION)")
+	fmt.Println("  go run cmd/client/main.go bookings [--device-id ID] [--user USER]")
+	fmt.Println("  go run cmd/client/main.go cancel-booking --id BOOKING_ID (--lease TOKEN | --user USER)")
+	fmt.Println("  go run cmd/client/main.go report-failure --device-id ID (--lease TOKEN | --user USER) --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin force-release --device-id ID --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin transfer --device-id ID --to USER --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin release-user --user USER --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin add-device --id ID --type TYPE [--label KEY=VALUE]... [--location LOCATION] --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin remove-device --device-id ID --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin set-health --device-id ID --state STATE --reason REASON")
+	fmt.Println("  go run cmd/client/main.go admin drain (--device-id ID | --type TYPE) --reason REASON [--wait [--timeout DURATION]]")
+	fmt.Println("  go run cmd/client/main.go admin undrain (--device-id ID | --type TYPE) --reason REASON")
+	fmt.Println("  Admin commands take --token TOKEN or $FLEETRPC_ADMIN_TOKEN, or use the login of an admin group member.")
+	fmt.Println("  After login, --user defaults to the logged-in user and the server ignores other values.")
+	fmt.Println("  $FLEETRPC_SERVER and $FLEETRPC_TOKEN override the saved login.")
+}
+
+// credentials are what login saves for later commands.
+type credentials struct {
+	Server string `json:"server,omitempty"`
+	Token  string `json:"token,omitempty"`
+	// User is who the server said Token belongs to, used as the default
+	// --user.
+	User string `json:"user,omitempty"`
+}
+
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fleetrpc", "credentials.json"), nil
+}
+
+// loadCredentials reads the saved credentials, letting $FLEETRPC_SERVER and
+// $FLEETRPC_TOKEN override them.
+func loadCredentials() credentials {
+	var c credentials
+	if path, err := credentialsPath(); err == nil {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &c)
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "warning: ignoring %s: %v\n", path, err)
+		}
+	}
+	if server := os.Getenv("FLEETRPC_SERVER"); server != "" {
+		c.Server = server
+	}
+	if token := os.Getenv("FLEETRPC_TOKEN"); token != "" {
+		c.Token, c.User = token, ""
+	}
+	if c.Server == "" {
+		c.Server = defaultServer
+	}
+	return c
+}
+
+func saveCredentials(c credentials) (string, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// bearerTransport sends token on every request that does not already carry
+// an Authorization header, such as admin calls with their own token.
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token == "" || req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// missingUser reports whether a command that acts as user cannot go ahead:
+// once logged in the server knows who the caller is.
+func missingUser(user string) bool {
+	return user == "" && creds.Token == ""
+}
+
+func httpClient(token string) *http.Client {
+	return &http.Client{Transport: &bearerTransport{token: token, base: http.DefaultTransport}}
+}
+
+func handleLogin(creds credentials, args []string) {
+	fs := flag.NewFlagSet("login", flag.ExitOnError)
+	server := fs.String("server", creds.Server, "server URL")
+	token := fs.String("token", "", "bearer token (read from stdin if unset)")
+	fs.Parse(args)
+
+	if *token == "" {
+		fmt.Fprint(os.Stderr, "Token: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Println("error: no token given")
+			os.Exit(1)
+		}
+		*token = strings.TrimSpace(line)
+	}
+
+	client := protov2connect.NewDeviceServiceClient(httpClient(*token), *server)
+	resp, err := client.WhoAmI(context.Background(), connect.NewRequest(&protov2.WhoAmIRequest{}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	saved := credentials{Server: *server, Token: *token, User: resp.Msg.User}
+	if !resp.Msg.Authenticated {
+		fmt.Println("note: the server does not authenticate callers; saving the server only")
+		saved.Token = ""
+	}
+	path, err := saveCredentials(saved)
+	if err != nil {
+		fmt.Printf("error: saving credentials: %v\n", err)
+		os.Exit(1)
+	}
+	if resp.Msg.Authenticated {
+		fmt.Printf("logged in to %s as %s\n", *server, resp.Msg.User)
+	}
+	fmt.Printf("saved: %s\n", path)
+}
+
+func handleLogout(creds credentials) {
+	creds.Token, creds.User = "", ""
+	path, err := saveCredentials(creds)
+	if err != nil {
+		fmt.Printf("error: saving credentials: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("logged out (%s)\n", path)
+}
+
+func handleWhoAmI(client protov2connect.DeviceServiceClient) {
+	resp, err := client.WhoAmI(context.Background(), connect.NewRequest(&protov2.WhoAmIRequest{}))
+	if err != nil {
+		exitWithError(err)
+	}
+	if !resp.Msg.Authenticated {
+		fmt.Println("the server does not authenticate callers")
+		return
+	}
+	fmt.Printf("user: %s\n", resp.Msg.User)
+	if len(resp.Msg.Groups) > 0 {
+		fmt.Printf("groups: %s\n", strings.Join(resp.Msg.Groups, ", "))
+	}
 }
 
 func handleReserve(client protoconnect.DeviceServiceClient, args []string) {
 	fs := flag.NewFlagSet("reserve", flag.ExitOnError)
-	user := fs.String("user", "", "user name")
+	user := fs.String("user", creds.User, "user name")
 	deviceType := fs.String("type", "iphone", "device type")
+	selector := fs.String("selector", "", `label selector, e.g. "os=17, sim in (esim, physical), api>=33"`)
+	priority := fs.Int("priority", 0, "higher priorities are served first")
+	preempt := fs.Bool("preempt", false, "with --wait, reclaim a device from a lower-priority holder after the server's grace period")
+	ttl := fs.Duration("ttl", 0, "requested reservation length (server default if unset)")
+	wait := fs.Bool("wait", false, "queue for the next free device instead of failing")
+	timeout := fs.Duration("timeout", 0, "give up waiting after this long (0 waits forever)")
 	fs.Parse(args)
 
-	if *user == "" {
+	if missingUser(*user) {
 		fmt.Println("error: --user is required")
 		os.Exit(1)
 	}
 
-	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+	req := &proto.ReserveRequest{
 		User:       *user,
 		DeviceType: *deviceType,
-	}))
+		Selector:   *selector,
+		Priority:   int32(*priority),
+		Preempt:    *preempt,
+	}
+	if *ttl > 0 {
+		req.Ttl = durationpb.New(*ttl)
+	}
+
+	if *wait {
+		waitForReservation(client, req, *timeout)
+		return
+	}
+
+	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(req))
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-		os.Exit(1)
+		exitWithError(err)
+	}
+
+	printReservation(resp.Msg)
+}
+
+func waitForReservation(client protoconnect.DeviceServiceClient, req *proto.ReserveRequest, timeout time.Duration) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	stream, err := client.ReserveAndWait(ctx, connect.NewRequest(req))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	for stream.Receive() {
+		update := stream.Msg()
+		if update.Reservation != nil {
+			printReservation(update.Reservation)
+			return
+		}
+		fmt.Printf("queued: position %d\n", update.QueuePosition)
 	}
 
-	if resp.Msg.DeviceId == "" {
-		fmt.Printf("failed: %s\n", resp.Msg.Status)
-	} else {
-		fmt.Printf("reserved: %s\n", resp.Msg.DeviceId)
+	if err := stream.Err(); err != nil {
+		exitWithError(err)
 	}
+	fmt.Println("error: stream ended without a reservation")
+	os.Exit(1)
+}
+
+func printReservation(resp *proto.ReserveResponse) {
+	fmt.Printf("reserved: %s until %s (lease: %s)\n", resp.DeviceId, resp.ExpiresAt.AsTime().Local().Format(time.RFC3339), resp.LeaseToken)
 }
 
 func handleRelease(client protoconnect.DeviceServiceClient, args []string) {
 	fs := flag.NewFlagSet("release", flag.ExitOnError)
 	deviceID := fs.String("device-id", "", "device ID to release")
+	lease := fs.String("lease", "", "lease token returned by reserve")
+	user := fs.String("user", creds.User, "user holding the reservation")
 	fs.Parse(args)
 
 	if *deviceID == "" {
 		fmt.Println("error: --device-id is required")
 		os.Exit(1)
 	}
+	if *lease == "" && missingUser(*user) {
+		fmt.Println("error: --lease or --user is required")
+		os.Exit(1)
+	}
 
 	resp, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
-		DeviceId: *deviceID,
+		DeviceId:   *deviceID,
+		LeaseToken: *lease,
+		User:       *user,
 	}))
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
-		os.Exit(1)
+		exitWithError(err)
 	}
 
 	fmt.Printf("released: %s (%s)\n", *deviceID, resp.Msg.Status)
 }
 
-func handleWatch(client protoconnect.DeviceServiceClient) {
+func handleExtend(client protoconnect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("extend", flag.ExitOnError)
+	deviceID := fs.String("device-id", "", "device ID to extend")
+	lease := fs.String("lease", "", "lease token returned by reserve")
+	user := fs.String("user", creds.User, "user holding the reservation")
+	by := fs.Duration("by", 2*time.Minute, "how long to extend the reservation")
+	fs.Parse(args)
+
+	if *deviceID == "" {
+		fmt.Println("error: --device-id is required")
+		os.Exit(1)
+	}
+	if *lease == "" && missingUser(*user) {
+		fmt.Println("error: --lease or --user is required")
+		os.Exit(1)
+	}
+
+	resp, err := client.ExtendReservation(context.Background(), connect.NewRequest(&proto.ExtendRequest{
+		DeviceId:   *deviceID,
+		LeaseToken: *lease,
+		User:       *user,
+		Extension:  durationpb.New(*by),
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	fmt.Printf("extended: %s until %s\n", *deviceID, resp.Msg.ExpiresAt.AsTime().Local().Format(time.RFC3339))
+}
+
+func handleWatch(client protoconnect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var types, deviceIDs stringList
+	labels := labelMap{}
+	fs.Var(&types, "type", "only watch this device type (repeatable)")
+	fs.Var(&deviceIDs, "device-id", "only watch this device (repeatable)")
+	fs.Var(labels, "label", "only watch devices with label KEY=VALUE (repeatable)")
+	user := fs.String("user", "", "only watch devices reserved by this user")
+	fs.Parse(args)
+
 	fmt.Println("watching devices (ctrl+c to stop)")
 
-	stream, err := client.WatchDevices(context.Background(), connect.NewRequest(&proto.WatchRequest{}))
+	// Every snapshot event carries the snapshot's revision, so resuming from
+	// it mid-snapshot would skip the devices not yet received. The revision
+	// only advances with the first event after the snapshot; until then a
+	// reconnect starts over. Revisions restart with the server, so the epoch
+	// they came with is sent back to detect a restart.
+	var revision uint64
+	var epoch string
+	for {
+		stream, err := client.WatchDevices(context.Background(), connect.NewRequest(&proto.WatchRequest{
+			SinceRevision: revision,
+			Epoch:         epoch,
+			Types:         types,
+			DeviceIds:     deviceIDs,
+			Labels:        labels,
+			ReservedBy:    *user,
+		}))
+		if err == nil {
+			for stream.Receive() {
+				dev := stream.Msg()
+				switch dev.Event {
+				case "resync":
+					revision = 0
+					fmt.Println("history lost or server restarted, resyncing from snapshot")
+					continue
+				case "snapshot":
+				default:
+					revision, epoch = dev.Revision, dev.Epoch
+				}
+				fmt.Printf("[%s] %s: %s\n", dev.Event, dev.DeviceId, describeStatus(dev))
+			}
+			err = stream.Err()
+		}
+
+		switch connect.CodeOf(err) {
+		case connect.CodeUnauthenticated, connect.CodePermissionDenied, connect.CodeInvalidArgument, connect.CodeUnimplemented:
+			exitWithError(err)
+		}
+		if err == nil {
+			err = errors.New("stream closed by server")
+		}
+		fmt.Printf("stream error: %v; reconnecting from revision %d\n", err, revision)
+		time.Sleep(watchRetryDelay)
+	}
+}
+
+func handleReserveBatch(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("reserve-batch", flag.ExitOnError)
+	user := fs.String("user", creds.User, "user name")
+	var devices stringList
+	fs.Var(&devices, "device", "device to reserve as TYPE or TYPE:SELECTOR (repeatable)")
+	ttl := fs.Duration("ttl", 0, "requested reservation length (server default if unset)")
+	fs.Parse(args)
+
+	if missingUser(*user) {
+		fmt.Println("error: --user is required")
+		os.Exit(1)
+	}
+	if len(devices) == 0 {
+		fmt.Println("error: at least one --device is required")
+		os.Exit(1)
+	}
+
+	req := &protov2.ReserveBatchRequest{User: *user}
+	for _, d := range devices {
+		deviceType, selector, _ := strings.Cut(d, ":")
+		req.Devices = append(req.Devices, &protov2.DeviceRequirement{DeviceType: deviceType, Selector: selector})
+	}
+	if *ttl > 0 {
+		req.Ttl = durationpb.New(*ttl)
+	}
+
+	resp, err := client.ReserveBatch(context.Background(), connect.NewRequest(req))
 	if err != nil {
-		fmt.Printf("error: %v\n", err)
+		exitWithError(err)
+	}
+
+	fmt.Printf("group lease: %s\n", resp.Msg.GroupLease)
+	for _, r := range resp.Msg.Reservations {
+		fmt.Printf("reserved: %s until %s\n", r.DeviceId, formatTimestamp(r.ExpiresAt))
+	}
+}
+
+func handleReleaseBatch(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("release-batch", flag.ExitOnError)
+	lease := fs.String("lease", "", "group lease returned by reserve-batch")
+	fs.Parse(args)
+
+	if *lease == "" {
+		fmt.Println("error: --lease is required")
 		os.Exit(1)
 	}
 
-	for stream.Receive() {
-		dev := stream.Msg()
-		status := "available"
-		if !dev.Available {
-			status = fmt.Sprintf("reserved by %s", dev.ReservedBy)
+	resp, err := client.ReleaseBatch(context.Background(), connect.NewRequest(&protov2.ReleaseBatchRequest{GroupLease: *lease}))
+	if err != nil {
+		exitWithError(err)
+	}
+	for _, r := range resp.Msg.Reservations {
+		fmt.Printf("released: %s\n", r.DeviceId)
+	}
+}
+
+func handleExtendBatch(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("extend-batch", flag.ExitOnError)
+	lease := fs.String("lease", "", "group lease returned by reserve-batch")
+	by := fs.Duration("by", 2*time.Minute, "how long to extend the reservations")
+	fs.Parse(args)
+
+	if *lease == "" {
+		fmt.Println("error: --lease is required")
+		os.Exit(1)
+	}
+
+	resp, err := client.ExtendBatch(context.Background(), connect.NewRequest(&protov2.ExtendBatchRequest{
+		GroupLease: *lease,
+		Extension:  durationpb.New(*by),
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+	for _, r := range resp.Msg.Reservations {
+		fmt.Printf("extended: %s until %s\n", r.DeviceId, formatTimestamp(r.ExpiresAt))
+	}
+}
+
+func handleList(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var types, states stringList
+	labels := labelMap{}
+	fs.Var(&types, "type", "only list this device type (repeatable)")
+	var health stringList
+	fs.Var(&states, "state", "only list devices in this state: available, reserved, retiring, unavailable, draining or drained (repeatable)")
+	fs.Var(&health, "health", "only list devices in this health state (repeatable)")
+	fs.Var(labels, "label", "only list devices with label KEY=VALUE (repeatable)")
+	pageSize := fs.Int("page-size", 0, "devices fetched per request (server default if unset)")
+	fs.Parse(args)
+
+	req := &protov2.ListDevicesRequest{
+		Types:    types,
+		Labels:   labels,
+		PageSize: int32(*pageSize),
+	}
+	for _, state := range states {
+		value, ok := protov2.DeviceState_value["DEVICE_STATE_"+strings.ToUpper(state)]
+		if !ok {
+			fmt.Printf("error: unknown state %q\n", state)
+			os.Exit(1)
+		}
+		req.States = append(req.States, protov2.DeviceState(value))
+	}
+	for _, h := range health {
+		value, err := parseHealth(h)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Printf("%s: %s\n", dev.DeviceId, status)
+		req.Health = append(req.Health, value)
 	}
 
-	if err := stream.Err(); err != nil {
-		fmt.Printf("stream error: %v\n", err)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tSTATE\tHEALTH\tHOLDER\tEXPIRES")
+	for {
+		resp, err := client.ListDevices(context.Background(), connect.NewRequest(req))
+		if err != nil {
+			w.Flush()
+			exitWithError(err)
+		}
+		for _, dev := range resp.Msg.Devices {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", dev.Id, dev.Type, describeState(dev.State), describeHealth(dev.Health), dev.ReservedBy, formatTimestamp(dev.ExpiresAt))
+		}
+		if resp.Msg.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.Msg.NextPageToken
+	}
+	w.Flush()
+}
+
+func handleShow(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	deviceID := fs.String("device-id", "", "device ID to show")
+	fs.Parse(args)
+
+	if *deviceID == "" {
+		fmt.Println("error: --device-id is required")
+		os.Exit(1)
+	}
+
+	resp, err := client.GetDevice(context.Background(), connect.NewRequest(&protov2.GetDeviceRequest{DeviceId: *deviceID}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	dev := resp.Msg.Device
+	fmt.Printf("id:       %s\n", dev.Id)
+	fmt.Printf("type:     %s\n", dev.Type)
+	fmt.Printf("state:    %s\n", describeState(dev.State))
+	fmt.Printf("health:   %s\n", describeHealth(dev.Health))
+	if dev.HealthReason != "" {
+		fmt.Printf("reason:   %s\n", dev.HealthReason)
+	}
+	if dev.Failures > 0 {
+		fmt.Printf("failures: %d\n", dev.Failures)
+	}
+	if dev.Location != "" {
+		fmt.Printf("location: %s\n", dev.Location)
+	}
+	if len(dev.Labels) > 0 {
+		fmt.Printf("labels:   %s\n", labelMap(dev.Labels))
+	}
+	if dev.ReservedBy != "" {
+		fmt.Printf("holder:   %s\n", dev.ReservedBy)
+		fmt.Printf("reserved: %s\n", formatTimestamp(dev.ReservedAt))
+		fmt.Printf("expires:  %s\n", formatTimestamp(dev.ExpiresAt))
+	}
+}
+
+func handleUsage(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	user := fs.String("user", "", "only show quotas that apply to this user")
+	fs.Parse(args)
+
+	resp, err := client.GetQuotaUsage(context.Background(), connect.NewRequest(&protov2.GetQuotaUsageRequest{User: *user}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCOPE\tSUBJECT\tTYPE\tIN USE\tLIMIT")
+	for _, u := range resp.Msg.Usage {
+		limit := "unlimited"
+		if u.Limit > 0 {
+			limit = fmt.Sprint(u.Limit)
+		}
+		deviceType := u.DeviceType
+		if deviceType == "" {
+			deviceType = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", u.Scope, u.Subject, deviceType, u.InUse, limit)
+	}
+	w.Flush()
+}
+
+func handleBook(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("book", flag.ExitOnError)
+	deviceID := fs.String("device-id", "", "device ID to book")
+	user := fs.String("user", creds.User, "user name")
+	start := fs.String("start", "", "start time (RFC 3339, or a duration from now such as 2h)")
+	end := fs.String("end", "", "end time (RFC 3339)")
+	length := fs.Duration("for", 0, "booking length, instead of --end")
+	fs.Parse(args)
+
+	if *deviceID == "" || missingUser(*user) || *start == "" {
+		fmt.Println("error: --device-id, --user and --start are required")
+		os.Exit(1)
+	}
+	if (*end == "") == (*length == 0) {
+		fmt.Println("error: exactly one of --end and --for is required")
+		os.Exit(1)
+	}
+
+	startTime, err := parseTime(*start)
+	if err != nil {
+		fmt.Printf("error: --start: %v\n", err)
+		os.Exit(1)
+	}
+	endTime := startTime.Add(*length)
+	if *end != "" {
+		if endTime, err = parseTime(*end); err != nil {
+			fmt.Printf("error: --end: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	resp, err := client.CreateBooking(context.Background(), connect.NewRequest(&protov2.CreateBookingRequest{
+		DeviceId:  *deviceID,
+		User:      *user,
+		StartTime: timestamppb.New(startTime),
+		EndTime:   timestamppb.New(endTime),
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	b := resp.Msg.Booking
+	fmt.Printf("booked: %s (%s) from %s until %s\n", b.DeviceId, b.Id, formatTimestamp(b.StartTime), formatTimestamp(b.EndTime))
+	fmt.Printf("lease: %s\n", b.LeaseToken)
+}
+
+// parseTime accepts an RFC 3339 time or a duration from now.
+func parseTime(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func handleBookings(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("bookings", flag.ExitOnError)
+	deviceID := fs.String("device-id", "", "only list bookings of this device")
+	user := fs.String("user", "", "only list bookings of this user")
+	fs.Parse(args)
+
+	resp, err := client.ListBookings(context.Background(), connect.NewRequest(&protov2.ListBookingsRequest{
+		DeviceId: *deviceID,
+		User:     *user,
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDEVICE\tUSER\tSTART\tEND")
+	for _, b := range resp.Msg.Bookings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", b.Id, b.DeviceId, b.User, formatTimestamp(b.StartTime), formatTimestamp(b.EndTime))
+	}
+	w.Flush()
+}
+
+func handleCancelBooking(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("cancel-booking", flag.ExitOnError)
+	id := fs.String("id", "", "booking ID")
+	lease := fs.String("lease", "", "lease token returned by book")
+	user := fs.String("user", creds.User, "user who made the booking")
+	fs.Parse(args)
+
+	if *id == "" || (*lease == "" && missingUser(*user)) {
+		fmt.Println("error: --id and one of --lease or --user are required")
+		os.Exit(1)
+	}
+
+	resp, err := client.CancelBooking(context.Background(), connect.NewRequest(&protov2.CancelBookingRequest{
+		BookingId:  *id,
+		LeaseToken: *lease,
+		User:       *user,
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+	fmt.Printf("cancelled: %s on %s\n", resp.Msg.Booking.Id, resp.Msg.Booking.DeviceId)
+}
+
+func handleReportFailure(client protov2connect.DeviceServiceClient, args []string) {
+	fs := flag.NewFlagSet("report-failure", flag.ExitOnError)
+	deviceID := fs.String("device-id", "", "device ID")
+	lease := fs.String("lease", "", "lease token of the failed reservation")
+	user := fs.String("user", creds.User, "user holding the device")
+	reason := fs.String("reason", "", "what went wrong")
+	fs.Parse(args)
+
+	if *deviceID == "" || (*lease == "" && missingUser(*user)) {
+		fmt.Println("error: --device-id and one of --lease or --user are required")
+		os.Exit(1)
+	}
+
+	resp, err := client.ReportDeviceFailure(context.Background(), connect.NewRequest(&protov2.ReportDeviceFailureRequest{
+		DeviceId:   *deviceID,
+		LeaseToken: *lease,
+		User:       *user,
+		Reason:     *reason,
+	}))
+	if err != nil {
+		exitWithError(err)
+	}
+	dev := resp.Msg.Device
+	fmt.Printf("reported: %s (%d failures, %s)\n", dev.Id, dev.Failures, describeHealth(dev.Health))
+}
+
+// waitForDrain polls until the devices named by deviceID or deviceType are
+// drained.
+func waitForDrain(client protov2connect.DeviceServiceClient, deviceID, deviceType string, timeout time.Duration) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(watchRetryDelay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("error: timed out waiting for drain")
+			os.Exit(1)
+		case <-ticker.C:
+		}
+		status, err := client.GetDrainStatus(ctx, connect.NewRequest(&protov2.GetDrainStatusRequest{
+			DeviceId:   deviceID,
+			DeviceType: deviceType,
+		}))
+		if err != nil {
+			exitWithError(err)
+		}
+		if status.Msg.Drained {
+			fmt.Println("drained")
+			return
+		}
+	}
+}
+
+func printDrainStatus(devices []*protov2.Device) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tHOLDER\tEXPIRES")
+	for _, dev := range devices {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dev.Id, describeState(dev.State), dev.ReservedBy, formatTimestamp(dev.ExpiresAt))
+	}
+	w.Flush()
+}
+
+func handleAdmin(client protov2connect.AdminServiceClient, clientV2 protov2connect.DeviceServiceClient, args []string) {
+	if len(args) == 0 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("admin "+args[0], flag.ExitOnError)
+	token := fs.String("token", os.Getenv("FLEETRPC_ADMIN_TOKEN"), "admin bearer token")
+	reason := fs.String("reason", "", "why, for the audit log")
+	deviceID := fs.String("device-id", "", "device ID")
+	ctx := context.Background()
+
+	switch args[0] {
+	case "force-release":
+		fs.Parse(args[1:])
+		resp, err := client.ForceRelease(ctx, adminRequest(&protov2.ForceReleaseRequest{DeviceId: *deviceID, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("released: %s (was held by %s)\n", resp.Msg.Reservation.DeviceId, resp.Msg.Reservation.User)
+	case "transfer":
+		to := fs.String("to", "", "user to transfer the reservation to")
+		fs.Parse(args[1:])
+		resp, err := client.TransferReservation(ctx, adminRequest(&protov2.TransferReservationRequest{DeviceId: *deviceID, ToUser: *to, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		r := resp.Msg.Reservation
+		fmt.Printf("transferred: %s to %s until %s\n", r.DeviceId, r.User, formatTimestamp(r.ExpiresAt))
+		fmt.Printf("lease: %s\n", r.LeaseToken)
+	case "release-user":
+		user := fs.String("user", "", "user whose reservations to release")
+		fs.Parse(args[1:])
+		resp, err := client.ReleaseUserReservations(ctx, adminRequest(&protov2.ReleaseUserReservationsRequest{User: *user, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		for _, r := range resp.Msg.Reservations {
+			fmt.Printf("released: %s\n", r.DeviceId)
+		}
+		fmt.Printf("%d reservations released\n", len(resp.Msg.Reservations))
+	case "add-device":
+		id := fs.String("id", "", "new device ID")
+		deviceType := fs.String("type", "", "device type")
+		location := fs.String("location", "", "where the device is")
+		labels := labelMap{}
+		fs.Var(labels, "label", "device label KEY=VALUE (repeatable)")
+		fs.Parse(args[1:])
+		resp, err := client.AddDevice(ctx, adminRequest(&protov2.AddDeviceRequest{
+			Device: &protov2.Device{Id: *id, Type: *deviceType, Labels: labels, Location: *location},
+			Reason: *reason,
+		}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("added: %s (%s)\n", resp.Msg.Device.Id, resp.Msg.Device.Type)
+	case "remove-device":
+		fs.Parse(args[1:])
+		resp, err := client.RemoveDevice(ctx, adminRequest(&protov2.RemoveDeviceRequest{DeviceId: *deviceID, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		if resp.Msg.Removed {
+			fmt.Printf("removed: %s\n", resp.Msg.Device.Id)
+		} else {
+			fmt.Printf("retiring: %s (removed when %s releases it)\n", resp.Msg.Device.Id, resp.Msg.Device.ReservedBy)
+		}
+	case "set-health":
+		state := fs.String("state", "", "healthy, maintenance, offline or quarantined")
+		fs.Parse(args[1:])
+		health, err := parseHealth(*state)
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+			os.Exit(1)
+		}
+		resp, err := client.SetDeviceHealth(ctx, adminRequest(&protov2.SetDeviceHealthRequest{DeviceId: *deviceID, Health: health, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s: %s\n", resp.Msg.Device.Id, describeHealth(resp.Msg.Device.Health))
+	case "drain":
+		deviceType := fs.String("type", "", "device type to drain entirely")
+		wait := fs.Bool("wait", false, "wait until every device is drained")
+		timeout := fs.Duration("timeout", 0, "give up waiting after this long (0 waits indefinitely)")
+		fs.Parse(args[1:])
+		resp, err := client.DrainDevices(ctx, adminRequest(&protov2.DrainRequest{DeviceId: *deviceID, DeviceType: *deviceType, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		printDrainStatus(resp.Msg.Devices)
+		if *wait && !resp.Msg.Drained {
+			waitForDrain(clientV2, *deviceID, *deviceType, *timeout)
+		}
+	case "undrain":
+		deviceType := fs.String("type", "", "device type to return to service")
+		fs.Parse(args[1:])
+		resp, err := client.UndrainDevices(ctx, adminRequest(&protov2.UndrainRequest{DeviceId: *deviceID, DeviceType: *deviceType, Reason: *reason}, *token))
+		if err != nil {
+			exitWithError(err)
+		}
+		printDrainStatus(resp.Msg.Devices)
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// adminRequest sends token, or the login token when it is empty.
+func adminRequest[T any](msg *T, token string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	if token != "" {
+		req.Header().Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func parseHealth(value string) (protov2.HealthState, error) {
+	h, ok := protov2.HealthState_value["HEALTH_STATE_"+strings.ToUpper(value)]
+	if !ok || h == 0 {
+		return 0, fmt.Errorf("unknown health state %q", value)
+	}
+	return protov2.HealthState(h), nil
+}
+
+func describeHealth(h protov2.HealthState) string {
+	return strings.ToLower(strings.TrimPrefix(h.String(), "HEALTH_STATE_"))
+}
+
+func describeState(state protov2.DeviceState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "DEVICE_STATE_"))
+}
+
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.AsTime().Local().Format(time.RFC3339)
+}
+
+// exitWithError prints err along with any ErrorDetail the server attached.
+func exitWithError(err error) {
+	fmt.Printf("error: %v\n", err)
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		for _, d := range connectErr.Details() {
+			msg, valueErr := d.Value()
+			detail, ok := msg.(*proto.ErrorDetail)
+			if valueErr != nil || !ok {
+				continue
+			}
+			fmt.Printf("  reason: %s\n", detail.Reason)
+			if detail.CurrentHolder != "" {
+				fmt.Printf("  held by: %s\n", detail.CurrentHolder)
+			}
+			if detail.QueueLength > 0 {
+				fmt.Printf("  waiting: %d\n", detail.QueueLength)
+			}
+			if detail.Field != "" {
+				fmt.Printf("  field: %s\n", detail.Field)
+			}
+			if q := detail.Quota; q != nil {
+				fmt.Printf("  quota: %s %s %d/%d\n", q.Scope, q.Subject, q.InUse, q.Limit)
+			}
+			if detail.BookingId != "" {
+				fmt.Printf("  booking: %s\n", detail.BookingId)
+			}
+			if detail.Permission != "" {
+				fmt.Printf("  missing permission: %s\n", detail.Permission)
+			}
+		}
+	}
+	os.Exit(1)
+}
+
+func describeStatus(dev *proto.DeviceStatus) string {
+	if dev.Event == "removed" {
+		return "removed"
+	}
+	status := "available"
+	if dev.ReservedBy != "" && !dev.Available {
+		status = fmt.Sprintf("reserved by %s", dev.ReservedBy)
+	} else if dev.Health != "" && dev.Health != "healthy" {
+		status = "unavailable"
+	}
+	if dev.Health != "" && dev.Health != "healthy" {
+		status += fmt.Sprintf(" (%s: %s)", dev.Health, dev.HealthReason)
+	}
+	if dev.Retiring {
+		status += " (retiring)"
+	}
+	if dev.Draining {
+		if dev.ReservedBy != "" && !dev.Available {
+			status += " (draining)"
+		} else {
+			status += " (drained)"
+		}
+	}
+	if dev.PreemptAt != nil {
+		status += fmt.Sprintf(" (preempted at %s)", formatTimestamp(dev.PreemptAt))
+	}
+	return status
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type labelMap map[string]string
+
+func (m labelMap) String() string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m labelMap) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("label %q must be KEY=VALUE", value)
 	}
+	m[k] = v
+	return nil
 }
@@ -1,33 +1,199 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"connectrpc.com/connect"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/gitRasheed/FleetRPC/internal/audit"
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/config"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
 	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
+	"github.com/gitRasheed/FleetRPC/internal/store"
 )
 
 func main() {
+	configPath := flag.String("config", "", "fleet definition file (JSON)")
+	stateDir := flag.String("state-dir", "", "directory for durable reservation state (overrides state_dir)")
+	issueToken := flag.String("issue-token", "", "print a signed token for this user and exit (requires auth.hmac_key_file)")
+	tokenTTL := flag.Duration("token-ttl", 30*24*time.Hour, "lifetime of tokens printed by -issue-token")
+	flag.Parse()
+
 	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
 
-	svc := protoconnect.NewDeviceServiceServer()
-	path, handler := protoconnect.NewDeviceServiceHandler(svc)
+	cfg := config.Default()
+	if *configPath != "" {
+		loaded, err := config.Load(*configPath)
+		if err != nil {
+			slog.Error("Invalid fleet file", "err", err)
+			os.Exit(1)
+		}
+		cfg = loaded
+	}
+	if *stateDir != "" {
+		cfg.StateDir = *stateDir
+	}
+	if *issueToken != "" {
+		if err := printToken(cfg, *issueToken, *tokenTTL); err != nil {
+			slog.Error("Issuing token failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	authn, err := cfg.Authenticator()
+	if err != nil {
+		slog.Error("Loading authentication failed", "err", err)
+		os.Exit(1)
+	}
+	policy, err := cfg.Policy()
+	if err != nil {
+		slog.Error("Loading access policy failed", "err", err)
+		os.Exit(1)
+	}
+
+	fleet := cfg.Fleet()
+	if cfg.StateDir != "" {
+		fileStore, err := store.NewFileStore(cfg.StateDir)
+		if err != nil {
+			slog.Error("Opening state directory failed", "dir", cfg.StateDir, "err", err)
+			os.Exit(1)
+		}
+
+		restored, err := fleet.Restore(fileStore)
+		if err != nil {
+			slog.Error("Restoring reservations failed", "dir", cfg.StateDir, "err", err)
+			os.Exit(1)
+		}
+		slog.Info("Reservations restored", "dir", cfg.StateDir, "count", restored)
+	}
+
+	var auditLog audit.Log
+	if cfg.Admin.AuditLog != "" {
+		fileLog, err := audit.OpenFile(cfg.Admin.AuditLog)
+		if err != nil {
+			slog.Error("Opening audit log failed", "path", cfg.Admin.AuditLog, "err", err)
+			os.Exit(1)
+		}
+		auditLog = fileLog
+	}
+
+	var opts []connect.HandlerOption
+	var authInterceptor *protoconnect.AuthInterceptor
+	if authn != nil {
+		authInterceptor = protoconnect.NewAuthInterceptor(authn)
+		authInterceptor.SetPolicy(policy)
+		opts = append(opts, connect.WithInterceptors(authInterceptor))
+	}
+
+	svc := protoconnect.NewDeviceServiceServer(fleet, cfg.LeasePolicy())
+	admin := protoconnect.NewAdminServiceServer(svc, cfg.Admin.Tokens, auditLog)
+	admin.SetAuth(authn, cfg.Admin.Groups)
+	admin.SetPolicy(policy)
+	path, handler := protoconnect.NewDeviceServiceHandler(svc, opts...)
+	pathV2, handlerV2 := protov2connect.NewDeviceServiceHandler(protoconnect.NewDeviceServiceV2Server(svc), opts...)
+	pathAdmin, handlerAdmin := protov2connect.NewAdminServiceHandler(admin)
+
+	if *configPath != "" {
+		go reloadOnSIGHUP(*configPath, svc, admin, authInterceptor)
+	}
 
 	mux := http.NewServeMux()
 	mux.Handle(path, handler)
+	mux.Handle(pathV2, handlerV2)
+	mux.Handle(pathAdmin, handlerAdmin)
 	mux.Handle("/metrics", promhttp.Handler())
 
 	slog.Info("FleetRPC server ready",
-		"port", ":8080",
+		"addr", cfg.Listen,
+		"devices", len(cfg.Devices),
 		"grpc_path", path,
-		"metrics", "http://localhost:8080/metrics",
+		"grpc_path_v2", pathV2,
+		"admin_path", pathAdmin,
+		"admins", len(cfg.Admin.Tokens),
+		"admin_groups", cfg.Admin.Groups,
+		"auth", authn != nil,
+		"rbac", policy != nil,
+		"metrics", "/metrics",
 	)
 
-	if err := http.ListenAndServe(":8080", mux); err != nil {
+	if err := http.ListenAndServe(cfg.Listen, mux); err != nil {
 		slog.Error("Server failed", "err", err)
 		os.Exit(1)
 	}
 }
+
+// printToken prints a signed token for user, valid for ttl.
+func printToken(cfg *config.Config, user string, ttl time.Duration) error {
+	tokens, err := cfg.HMACTokens()
+	if err != nil {
+		return err
+	}
+	token, err := tokens.Issue(auth.Identity{User: user}, ttl)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+// reloadOnSIGHUP reloads the fleet file on SIGHUP. authInterceptor is nil
+// when authentication is off; turning it on needs a restart.
+func reloadOnSIGHUP(path string, svc *protoconnect.DeviceServiceServer, admin *protoconnect.AdminServiceServer, authInterceptor *protoconnect.AuthInterceptor) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		if err := reload(path, svc, admin, authInterceptor); err != nil {
+			slog.Error("Reload failed; keeping the current settings", "err", err)
+		}
+	}
+}
+
+// reload applies the fleet file at path. Authentication is checked before
+// anything is applied and the fleet applied first, so a file that fails
+// either changes nothing.
+func reload(path string, svc *protoconnect.DeviceServiceServer, admin *protoconnect.AdminServiceServer, authInterceptor *protoconnect.AuthInterceptor) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	var authn auth.Authenticator
+	var policy *rbac.Policy
+	if authInterceptor != nil {
+		if authn, err = cfg.Authenticator(); err != nil {
+			return fmt.Errorf("authentication: %w", err)
+		}
+		if authn == nil {
+			return errors.New("authentication: turning it off needs a restart")
+		}
+		if policy, err = cfg.Policy(); err != nil {
+			return fmt.Errorf("access policy: %w", err)
+		}
+	}
+	if _, err := svc.ApplyFleet(cfg.DeviceList()); err != nil {
+		return fmt.Errorf("fleet: %w", err)
+	}
+
+	svc.SetQuotas(cfg.QuotaPolicy())
+	svc.SetQuarantineAfter(cfg.QuarantineAfter)
+	svc.SetLeasePolicy(cfg.LeasePolicy())
+	admin.SetAdmins(cfg.Admin.Tokens)
+	if authInterceptor != nil {
+		authInterceptor.SetAuthenticator(authn)
+		authInterceptor.SetPolicy(policy)
+		admin.SetAuth(authn, cfg.Admin.Groups)
+		admin.SetPolicy(policy)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is one administrative action, successful or not.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	// DeviceID, DeviceType or User names what the action applied to.
+	DeviceID   string `json:"device_id,omitempty"`
+	DeviceType string `json:"device_type,omitempty"`
+	User       string `json:"user,omitempty"`
+	// Previous is the holder a device was taken from; Target is who it went to.
+	Previous string `json:"previous,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type Log interface {
+	Write(r Record) error
+}
+
+// FileLog appends records to a JSON Lines file, syncing after each one.
+type FileLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func OpenFile(path string) (*FileLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileLog{file: file}, nil
+}
+
+func (l *FileLog) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *FileLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// ReadFile returns the records in a file written by FileLog, oldest first.
+func ReadFile(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for i, line := range bytes.Split(bytes.TrimSpace(data), []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return records, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
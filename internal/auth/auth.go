@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var (
+	ErrNoToken      = errors.New("bearer token required")
+	ErrInvalidToken = errors.New("invalid bearer token")
+	ErrExpiredToken = errors.New("bearer token expired")
+)
+
+// Identity is an authenticated caller.
+type Identity struct {
+	User   string
+	Groups []string
+}
+
+func (id Identity) InGroup(group string) bool {
+	return slices.Contains(id.Groups, group)
+}
+
+// Authenticator resolves a bearer token to the identity it was issued to.
+// It returns an error wrapping ErrInvalidToken or ErrExpiredToken for tokens
+// it does not accept.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+// Chain tries each authenticator in turn and returns the first identity one
+// accepts. Otherwise it returns the first error more specific than
+// ErrInvalidToken, since that comes from the backend the token was meant for.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(ctx context.Context, token string) (Identity, error) {
+	err := ErrInvalidToken
+	for _, a := range c {
+		id, authErr := a.Authenticate(ctx, token)
+		if authErr == nil {
+			return id, nil
+		}
+		if err == ErrInvalidToken {
+			err = authErr
+		}
+	}
+	return Identity{}, err
+}
+
+type contextKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the identity the request was authenticated as, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,287 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// KeySet finds the public key a JWT was signed with.
+type KeySet interface {
+	// Key returns the key with ID kid, or the only key when kid is empty and
+	// the set holds one.
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// JWKS is a parsed JSON Web Key Set.
+type JWKS struct {
+	keys map[string]crypto.PublicKey
+}
+
+// ParseJWKS parses a JSON Web Key Set holding RSA, EC (P-256, P-384, P-521)
+// or Ed25519 keys. Keys not meant for signatures are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	s := &JWKS{keys: make(map[string]crypto.PublicKey, len(set.Keys))}
+	var errs []error
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("keys[%d] (kid %q): %w", i, k.Kid, err))
+			continue
+		}
+		if _, ok := s.keys[k.Kid]; ok {
+			errs = append(errs, fmt.Errorf("keys[%d]: duplicate kid %q", i, k.Kid))
+			continue
+		}
+		s.keys[k.Kid] = key
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if len(s.keys) == 0 {
+		return nil, errors.New("no signing keys")
+	}
+	return s, nil
+}
+
+func LoadJWKSFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("e: %w", err)
+		}
+		if n.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits, got %d", n.BitLen())
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("e: unsupported exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, ok := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, errors.New("x and y must be base64url coordinates")
+		}
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("x must be a base64url Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+const (
+	// jwksMaxAge is how long a fetched key set is used before refetching.
+	jwksMaxAge = time.Hour
+	// jwksMinRefresh limits refetches for unknown key IDs, so tokens with
+	// made-up IDs cannot hammer the identity provider.
+	jwksMinRefresh = time.Minute
+)
+
+// RemoteJWKS fetches a key set from a URL and caches it, refetching when it
+// is an hour old or a token names a key it does not hold.
+type RemoteJWKS struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    *JWKS
+	fetched time.Time
+	// attempted is when the last fetch started, whether or not it succeeded,
+	// and err is how it failed.
+	attempted time.Time
+	err       error
+	// fetching is closed when the fetch in progress ends.
+	fetching chan struct{}
+}
+
+// NewRemoteJWKS fetches with client, or with a 10 second timeout when client
+// is nil.
+func NewRemoteJWKS(url string, client *http.Client) *RemoteJWKS {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &RemoteJWKS{url: url, client: client}
+}
+
+func (r *RemoteJWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	keys, err := r.keySet(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.Key(ctx, kid)
+	if err == nil {
+		return key, nil
+	}
+	refreshed, fetchErr := r.keySet(ctx, true)
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	return refreshed.Key(ctx, kid)
+}
+
+// keySet returns the cached key set, first fetching it if it is missing,
+// too old, or refresh is set. Concurrent callers share one fetch, and
+// fetches start at most once every jwksMinRefresh; in between, callers get
+// the cached keys or the last fetch's error.
+func (r *RemoteJWKS) keySet(ctx context.Context, refresh bool) (*JWKS, error) {
+	r.mu.Lock()
+	if r.keys != nil && !refresh && time.Since(r.fetched) <= jwksMaxAge {
+		defer r.mu.Unlock()
+		return r.keys, nil
+	}
+	done := r.fetching
+	if done == nil {
+		if !r.attempted.IsZero() && time.Since(r.attempted) < jwksMinRefresh {
+			defer r.mu.Unlock()
+			if r.keys == nil {
+				return nil, r.err
+			}
+			return r.keys, nil
+		}
+		r.attempted = time.Now()
+		r.fetching = make(chan struct{})
+		r.mu.Unlock()
+
+		// Waiting callers share the result, so one caller giving up does not
+		// cancel the fetch.
+		keys, err := r.fetch(context.WithoutCancel(ctx))
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if err == nil {
+			r.keys, r.fetched = keys, time.Now()
+		}
+		r.err = err
+		close(r.fetching)
+		r.fetching = nil
+		return keys, err
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.keys, nil
+}
+
+func (r *RemoteJWKS) fetch(ctx context.Context) (*JWKS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching JWKS: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetching JWKS: %w", err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.url, err)
+	}
+	return keys, nil
+}
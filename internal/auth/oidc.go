@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"cmp"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OIDC verifies JWTs issued by an OpenID Connect provider and maps their
+// claims to an Identity.
+type OIDC struct {
+	Keys     KeySet
+	Issuer   string
+	Audience string
+	// UserClaim names the claim holding the user, "email" if empty. Tokens
+	// with email_verified set to false are refused when it is "email".
+	UserClaim string
+	// GroupsClaim names the claim holding the user's groups, "groups" if
+	// empty.
+	GroupsClaim string
+	// Leeway allows for clock skew when checking exp and nbf.
+	Leeway time.Duration
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (o *OIDC) Authenticate(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidToken
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	key, err := o.Keys.Key(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return Identity{}, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	if err := o.checkClaims(claims); err != nil {
+		return Identity{}, err
+	}
+	return o.identity(claims)
+}
+
+func (o *OIDC) checkClaims(claims map[string]any) error {
+	if iss, _ := claims["iss"].(string); iss != o.Issuer {
+		return fmt.Errorf("%w: issuer %q not accepted", ErrInvalidToken, iss)
+	}
+	if !audienceContains(claims["aud"], o.Audience) {
+		return fmt.Errorf("%w: audience does not include %q", ErrInvalidToken, o.Audience)
+	}
+
+	now := time.Now()
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return fmt.Errorf("%w: exp claim required", ErrInvalidToken)
+	}
+	if !now.Before(exp.Add(o.Leeway)) {
+		return ErrExpiredToken
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(o.Leeway).Before(nbf) {
+		return fmt.Errorf("%w: not valid before %s", ErrInvalidToken, nbf.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (o *OIDC) identity(claims map[string]any) (Identity, error) {
+	userClaim := cmp.Or(o.UserClaim, "email")
+	user, _ := claims[userClaim].(string)
+	if user == "" {
+		return Identity{}, fmt.Errorf("%w: %s claim required", ErrInvalidToken, userClaim)
+	}
+	if verified, ok := claims["email_verified"].(bool); userClaim == "email" && ok && !verified {
+		return Identity{}, fmt.Errorf("%w: email %q is not verified", ErrInvalidToken, user)
+	}
+
+	id := Identity{User: user}
+	switch groups := claims[cmp.Or(o.GroupsClaim, "groups")].(type) {
+	case string:
+		id.Groups = []string{groups}
+	case []any:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				id.Groups = append(id.Groups, s)
+			}
+		}
+	}
+	return id, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func audienceContains(aud any, want string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == want
+	case []any:
+		return slices.Contains(aud, any(want))
+	}
+	return false
+}
+
+func numericDate(v any) (time.Time, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// verifySignature checks sig over signed with key for the JWS algorithm alg.
+// The key's type must match alg, so a token cannot pick a weaker check.
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	invalid := fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s needs an RSA key", ErrInvalidToken, alg)
+		}
+		h, digest := jwsHash(alg)
+		var err error
+		if alg[0] == 'R' {
+			err = rsa.VerifyPKCS1v15(pub, h, digest(signed), sig)
+		} else {
+			err = rsa.VerifyPSS(pub, h, digest(signed), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return invalid
+		}
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve.Params().BitSize != map[string]int{"ES256": 256, "ES384": 384, "ES512": 521}[alg] {
+			return fmt.Errorf("%w: %s needs a matching EC key", ErrInvalidToken, alg)
+		}
+		_, digest := jwsHash(alg)
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return invalid
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest(signed), r, s) {
+			return invalid
+		}
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: EdDSA needs an Ed25519 key", ErrInvalidToken)
+		}
+		if !ed25519.Verify(pub, []byte(signed), sig) {
+			return invalid
+		}
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+	return nil
+}
+
+func jwsHash(alg string) (crypto.Hash, func(string) []byte) {
+	var h crypto.Hash
+	var newHash func() hash.Hash
+	switch alg[2:] {
+	case "256":
+		h, newHash = crypto.SHA256, sha256.New
+	case "384":
+		h, newHash = crypto.SHA384, sha512.New384
+	default:
+		h, newHash = crypto.SHA512, sha512.New
+	}
+	return h, func(s string) []byte {
+		d := newHash()
+		d.Write([]byte(s))
+		return d.Sum(nil)
+	}
+}
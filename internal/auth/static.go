@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TokenEntry is one token in a token file.
+type TokenEntry struct {
+	Token  string   `json:"token"`
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+}
+
+// StaticTokens authenticates a fixed set of tokens.
+type StaticTokens struct {
+	// byHash is keyed by the SHA-256 of each token so lookups do not leak
+	// token prefixes through timing.
+	byHash map[[sha256.Size]byte]Identity
+}
+
+func NewStaticTokens(entries []TokenEntry) (*StaticTokens, error) {
+	s := &StaticTokens{byHash: make(map[[sha256.Size]byte]Identity, len(entries))}
+	var errs []error
+	for i, e := range entries {
+		if e.Token == "" || e.User == "" {
+			errs = append(errs, fmt.Errorf("tokens[%d]: token and user are required", i))
+			continue
+		}
+		hash := sha256.Sum256([]byte(e.Token))
+		if _, ok := s.byHash[hash]; ok {
+			errs = append(errs, fmt.Errorf("tokens[%d]: duplicate token", i))
+			continue
+		}
+		s.byHash[hash] = Identity{User: e.User, Groups: e.Groups}
+	}
+	return s, errors.Join(errs...)
+}
+
+// LoadTokenFile reads a JSON file of the form {"tokens": [{"token": ...,
+// "user": ..., "groups": [...]}]}.
+func LoadTokenFile(path string) (*StaticTokens, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Tokens []TokenEntry `json:"tokens"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s, err := NewStaticTokens(file.Tokens)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *StaticTokens) Authenticate(ctx context.Context, token string) (Identity, error) {
+	hash := sha256.Sum256([]byte(token))
+	for h, id := range s.byHash {
+		if subtle.ConstantTimeCompare(h[:], hash[:]) == 1 {
+			return id, nil
+		}
+	}
+	return Identity{}, ErrInvalidToken
+}
@@ -0,0 +1,444 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+)
+
+type Config struct {
+	Listen   string         `json:"listen"`
+	StateDir string         `json:"state_dir"`
+	Leases   LeaseConfig    `json:"leases"`
+	Quotas   QuotaConfig    `json:"quotas"`
+	Devices  []DeviceConfig `json:"devices"`
+	// QuarantineAfter quarantines a device after this many reservations on it
+	// are reported as failed; zero disables automatic quarantine.
+	QuarantineAfter int         `json:"quarantine_after"`
+	Admin           AdminConfig `json:"admin"`
+	Auth            AuthConfig  `json:"auth"`
+	RBAC            *RBACConfig `json:"rbac"`
+}
+
+// AuthConfig turns on bearer-token authentication for DeviceService when
+// any backend is set. They may be combined.
+type AuthConfig struct {
+	// TokenFile lists static tokens and the users they belong to.
+	TokenFile string `json:"token_file"`
+	// HMACKeyFile holds the key signed tokens are issued and verified with.
+	HMACKeyFile string      `json:"hmac_key_file"`
+	OIDC        *OIDCConfig `json:"oidc"`
+}
+
+// OIDCConfig accepts JWTs from an OpenID Connect provider, checked against
+// its key set from JWKSFile or JWKSURL.
+type OIDCConfig struct {
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	JWKSFile string `json:"jwks_file"`
+	JWKSURL  string `json:"jwks_url"`
+	// UserClaim and GroupsClaim default to "email" and "groups".
+	UserClaim   string   `json:"user_claim"`
+	GroupsClaim string   `json:"groups_claim"`
+	Leeway      Duration `json:"leeway"`
+}
+
+// RBACConfig limits what authenticated callers may do to what their roles
+// grant. Users get the roles bound to them, to their groups and
+// DefaultRoles.
+type RBACConfig struct {
+	Roles        map[string]RoleConfig `json:"roles"`
+	Users        map[string][]string   `json:"users"`
+	Groups       map[string][]string   `json:"groups"`
+	DefaultRoles []string              `json:"default_roles"`
+}
+
+// RoleConfig grants permissions on the devices of DeviceTypes (every type
+// if empty) that Selector matches.
+type RoleConfig struct {
+	Permissions []string `json:"permissions"`
+	DeviceTypes []string `json:"device_types"`
+	Selector    string   `json:"selector"`
+}
+
+type AdminConfig struct {
+	// Tokens maps each admin's name to the bearer token they call
+	// AdminService with.
+	Tokens map[string]string `json:"tokens"`
+	// Groups lets authenticated users in any of these groups call
+	// AdminService with their own token.
+	Groups []string `json:"groups"`
+	// AuditLog is a JSON Lines file every admin call is appended to.
+	AuditLog string `json:"audit_log"`
+}
+
+type LeaseConfig struct {
+	MinTTL     Duration `json:"min_ttl"`
+	MaxTTL     Duration `json:"max_ttl"`
+	DefaultTTL Duration `json:"default_ttl"`
+	MaxLease   Duration `json:"max_lease"`
+	// PreemptGrace enables preemption when set.
+	PreemptGrace Duration `json:"preempt_grace"`
+}
+
+// QuotaConfig caps concurrent reservations; zero or missing limits are
+// unlimited.
+type QuotaConfig struct {
+	PerUser int                 `json:"per_user"`
+	Users   map[string]int      `json:"users"`
+	PerType map[string]int      `json:"per_type"`
+	PerTeam int                 `json:"per_team"`
+	Teams   map[string][]string `json:"teams"`
+}
+
+type DeviceConfig struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Labels   map[string]string `json:"labels"`
+	Location string            `json:"location"`
+}
+
+// Duration reads Go duration strings such as "90s" or "45m" from JSON.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"2m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Default is the fleet served when no fleet file is given.
+func Default() *Config {
+	c := &Config{Listen: ":8080"}
+	for _, deviceType := range []string{"iphone", "pixel"} {
+		for i := 0; i < 10; i++ {
+			c.Devices = append(c.Devices, DeviceConfig{Type: deviceType})
+		}
+	}
+	c.applyDefaults()
+	return c
+}
+
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &Config{}
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	c.applyDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// applyDefaults fills unset settings and generates IDs like "iphone-0" for
+// devices that do not name one.
+func (c *Config) applyDefaults() {
+	if c.Listen == "" {
+		c.Listen = ":8080"
+	}
+	defaults := device.DefaultLeasePolicy()
+	setDefault(&c.Leases.MinTTL, defaults.MinTTL)
+	setDefault(&c.Leases.MaxTTL, defaults.MaxTTL)
+	setDefault(&c.Leases.DefaultTTL, defaults.DefaultTTL)
+	setDefault(&c.Leases.MaxLease, defaults.MaxLease)
+
+	generated := make(map[string]int)
+	for i := range c.Devices {
+		d := &c.Devices[i]
+		if d.ID == "" && d.Type != "" {
+			d.ID = fmt.Sprintf("%s-%d", d.Type, generated[d.Type])
+			generated[d.Type]++
+		}
+	}
+}
+
+func setDefault(d *Duration, fallback time.Duration) {
+	if d.Duration == 0 {
+		d.Duration = fallback
+	}
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.Devices) == 0 {
+		errs = append(errs, errors.New("devices: at least one device is required"))
+	}
+
+	seen := make(map[string]int)
+	for i, d := range c.Devices {
+		if d.Type == "" {
+			errs = append(errs, fmt.Errorf("devices[%d]: type is required", i))
+			continue
+		}
+		if first, ok := seen[d.ID]; ok {
+			errs = append(errs, fmt.Errorf("devices[%d]: id %q already used by devices[%d]", i, d.ID, first))
+			continue
+		}
+		seen[d.ID] = i
+	}
+
+	q := c.Quotas
+	if q.PerUser < 0 {
+		errs = append(errs, errors.New("quotas.per_user: must not be negative"))
+	}
+	if q.PerTeam < 0 {
+		errs = append(errs, errors.New("quotas.per_team: must not be negative"))
+	}
+	for _, user := range sortedKeys(q.Users) {
+		if q.Users[user] < 0 {
+			errs = append(errs, fmt.Errorf("quotas.users[%q]: must not be negative", user))
+		}
+	}
+	for _, t := range sortedKeys(q.PerType) {
+		if q.PerType[t] < 0 {
+			errs = append(errs, fmt.Errorf("quotas.per_type[%q]: must not be negative", t))
+		}
+	}
+
+	if c.QuarantineAfter < 0 {
+		errs = append(errs, errors.New("quarantine_after: must not be negative"))
+	}
+
+	tokenOwners := make(map[string]string)
+	for _, name := range sortedKeys(c.Admin.Tokens) {
+		token := c.Admin.Tokens[name]
+		if token == "" {
+			errs = append(errs, fmt.Errorf("admin.tokens[%q]: must not be empty", name))
+			continue
+		}
+		if other, ok := tokenOwners[token]; ok {
+			errs = append(errs, fmt.Errorf("admin.tokens[%q]: same token as %q", name, other))
+			continue
+		}
+		tokenOwners[token] = name
+	}
+
+	if o := c.Auth.OIDC; o != nil {
+		if o.Issuer == "" {
+			errs = append(errs, errors.New("auth.oidc.issuer: is required"))
+		}
+		if o.Audience == "" {
+			errs = append(errs, errors.New("auth.oidc.audience: is required"))
+		}
+		if (o.JWKSFile == "") == (o.JWKSURL == "") {
+			errs = append(errs, errors.New("auth.oidc: exactly one of jwks_file and jwks_url is required"))
+		}
+		if o.Leeway.Duration < 0 {
+			errs = append(errs, errors.New("auth.oidc.leeway: must not be negative"))
+		}
+	}
+	authEnabled := c.Auth.TokenFile != "" || c.Auth.HMACKeyFile != "" || c.Auth.OIDC != nil
+	if len(c.Admin.Groups) > 0 && !authEnabled {
+		errs = append(errs, errors.New("admin.groups: needs an auth backend to say who is in them"))
+	}
+	if c.RBAC != nil {
+		if !authEnabled {
+			errs = append(errs, errors.New("rbac: needs an auth backend to say who callers are"))
+		}
+		if _, err := c.Policy(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	l := c.Leases
+	if l.MinTTL.Duration <= 0 {
+		errs = append(errs, errors.New("leases.min_ttl: must be positive"))
+	}
+	if l.MinTTL.Duration > l.MaxTTL.Duration {
+		errs = append(errs, fmt.Errorf("leases: min_ttl %s is greater than max_ttl %s", l.MinTTL, l.MaxTTL))
+	}
+	if l.DefaultTTL.Duration < l.MinTTL.Duration || l.DefaultTTL.Duration > l.MaxTTL.Duration {
+		errs = append(errs, fmt.Errorf("leases.default_ttl: %s is outside [%s, %s]", l.DefaultTTL, l.MinTTL, l.MaxTTL))
+	}
+	if l.MaxLease.Duration < l.MaxTTL.Duration {
+		errs = append(errs, fmt.Errorf("leases.max_lease: %s is shorter than max_ttl %s", l.MaxLease, l.MaxTTL))
+	}
+	if l.PreemptGrace.Duration < 0 {
+		errs = append(errs, errors.New("leases.preempt_grace: must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) LeasePolicy() device.LeasePolicy {
+	return device.LeasePolicy{
+		MinTTL:     c.Leases.MinTTL.Duration,
+		MaxTTL:     c.Leases.MaxTTL.Duration,
+		DefaultTTL: c.Leases.DefaultTTL.Duration,
+		MaxLease:   c.Leases.MaxLease.Duration,
+
+		PreemptGrace: c.Leases.PreemptGrace.Duration,
+	}
+}
+
+func (c *Config) QuotaPolicy() device.Quotas {
+	return device.Quotas{
+		PerUser: c.Quotas.PerUser,
+		Users:   c.Quotas.Users,
+		PerType: c.Quotas.PerType,
+		PerTeam: c.Quotas.PerTeam,
+		Teams:   c.Quotas.Teams,
+	}
+}
+
+// Authenticator loads the configured token backends, or returns nil when
+// authentication is off.
+func (c *Config) Authenticator() (auth.Authenticator, error) {
+	var chain auth.Chain
+	if c.Auth.TokenFile != "" {
+		tokens, err := auth.LoadTokenFile(c.Auth.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth.token_file: %w", err)
+		}
+		chain = append(chain, tokens)
+	}
+	if c.Auth.HMACKeyFile != "" {
+		hmacTokens, err := c.HMACTokens()
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, hmacTokens)
+	}
+	if o := c.Auth.OIDC; o != nil {
+		var keys auth.KeySet
+		if o.JWKSURL != "" {
+			keys = auth.NewRemoteJWKS(o.JWKSURL, nil)
+		} else {
+			jwks, err := auth.LoadJWKSFile(o.JWKSFile)
+			if err != nil {
+				return nil, fmt.Errorf("auth.oidc.jwks_file: %w", err)
+			}
+			keys = jwks
+		}
+		chain = append(chain, &auth.OIDC{
+			Keys:        keys,
+			Issuer:      o.Issuer,
+			Audience:    o.Audience,
+			UserClaim:   o.UserClaim,
+			GroupsClaim: o.GroupsClaim,
+			Leeway:      o.Leeway.Duration,
+		})
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return chain, nil
+}
+
+// Policy builds the access policy, or returns nil when RBAC is off.
+func (c *Config) Policy() (*rbac.Policy, error) {
+	r := c.RBAC
+	if r == nil {
+		return nil, nil
+	}
+
+	var errs []error
+	roles := make([]rbac.Role, 0, len(r.Roles))
+	for _, name := range sortedKeys(r.Roles) {
+		rc := r.Roles[name]
+		sel, err := device.ParseSelector(rc.Selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rbac.roles[%q].selector: %w", name, err))
+		}
+		role := rbac.Role{Name: name, DeviceTypes: rc.DeviceTypes, Selector: sel}
+		for _, perm := range rc.Permissions {
+			role.Permissions = append(role.Permissions, rbac.Permission(perm))
+		}
+		roles = append(roles, role)
+	}
+	policy, err := rbac.NewPolicy(roles, r.Users, r.Groups, r.DefaultRoles)
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, fmt.Errorf("rbac: %w", err))
+		}
+	}
+	return policy, errors.Join(errs...)
+}
+
+func (c *Config) HMACTokens() (*auth.HMACTokens, error) {
+	if c.Auth.HMACKeyFile == "" {
+		return nil, errors.New("auth.hmac_key_file: not set")
+	}
+	tokens, err := auth.LoadHMACKeyFile(c.Auth.HMACKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("auth.hmac_key_file: %w", err)
+	}
+	return tokens, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Fleet builds one pool per device type from the configured devices.
+func (c *Config) Fleet() *device.Fleet {
+	byType := make(map[string][]*device.Device)
+	for _, d := range c.DeviceList() {
+		byType[d.Type] = append(byType[d.Type], d)
+	}
+
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	pools := make([]*device.DevicePool, 0, len(types))
+	for _, t := range types {
+		pools = append(pools, device.NewDevicePoolWithDevices(t, byType[t]))
+	}
+	fleet := device.NewFleet(pools...)
+	fleet.SetQuotas(c.QuotaPolicy())
+	fleet.SetQuarantineAfter(c.QuarantineAfter)
+	return fleet
+}
+
+func (c *Config) DeviceList() []*device.Device {
+	devices := make([]*device.Device, 0, len(c.Devices))
+	for _, d := range c.Devices {
+		devices = append(devices, d.Device())
+	}
+	return devices
+}
+
+func (d DeviceConfig) Device() *device.Device {
+	return &device.Device{
+		ID:       d.ID,
+		Type:     d.Type,
+		Labels:   d.Labels,
+		Location: d.Location,
+	}
+}
@@ -0,0 +1,176 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+var ErrDeviceExists = errors.New("device already exists")
+
+// AddedDevice is a device added with Fleet.AddDevice rather than from the
+// fleet file.
+type AddedDevice struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Location string            `json:"location,omitempty"`
+}
+
+// DeviceStore is implemented by stores that also persist added devices.
+type DeviceStore interface {
+	SaveDevice(d AddedDevice) error
+	DeleteDevice(id string) error
+	LoadDevices() ([]AddedDevice, error)
+}
+
+// ForceRelease frees deviceID whoever holds it and returns the device as it
+// was before release.
+func (p *DevicePool) ForceRelease(deviceID string) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID {
+			if !IsReserved(d) {
+				return Device{}, ErrNotReserved
+			}
+			held := *d
+			p.freeLocked(d, EventForceReleased)
+			return held, nil
+		}
+	}
+	return Device{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+// Transfer hands deviceID's reservation to user under a new lease token,
+// keeping its expiry, and returns the previous holder. Quotas are not checked.
+func (p *DevicePool) Transfer(deviceID, user string) (Device, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID != deviceID {
+			continue
+		}
+		if !IsReserved(d) {
+			return Device{}, "", ErrNotReserved
+		}
+
+		previous := d.ReservedBy
+		p.quotas.release(previous, d.Type)
+		p.quotas.add(user, d.Type)
+		d.ReservedBy = user
+		d.LeaseToken = newLeaseToken()
+		p.persistLocked(d)
+		if p.events != nil {
+			p.events.Publish(Event{Kind: EventTransferred, Device: *d, PreviousHolder: previous})
+		}
+		return *d, previous, nil
+	}
+	return Device{}, "", fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+// ReleaseUser frees every device user holds in the pool, or only those in
+// deviceIDs if it is not nil, and returns them as they were before release.
+func (p *DevicePool) ReleaseUser(user string, deviceIDs []string) []Device {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var released []Device
+	for _, d := range append([]*Device(nil), p.devices...) {
+		if IsReserved(d) && d.ReservedBy == user && (deviceIDs == nil || slices.Contains(deviceIDs, d.ID)) {
+			released = append(released, *d)
+			p.freeLocked(d, EventForceReleased)
+		}
+	}
+	return released
+}
+
+func (f *Fleet) ForceRelease(deviceID string) (Device, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, err
+	}
+	return p.ForceRelease(deviceID)
+}
+
+func (f *Fleet) Transfer(deviceID, user string) (Device, string, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, "", err
+	}
+	return p.Transfer(deviceID, user)
+}
+
+// ReleaseUser frees every device user holds across the fleet, or only those
+// in deviceIDs if it is not nil.
+func (f *Fleet) ReleaseUser(user string, deviceIDs []string) []Device {
+	var released []Device
+	for _, p := range f.Pools() {
+		released = append(released, p.ReleaseUser(user, deviceIDs)...)
+	}
+	return released
+}
+
+// AddDevice adds d to the pool for its type, creating the pool if needed.
+// Unlike Apply it refuses IDs already in the fleet. The device is kept by
+// later calls to Apply and, if the fleet's store is a DeviceStore, across
+// restarts, until RemoveDevice or until Apply is given a device with its ID.
+func (f *Fleet) AddDevice(d *Device) (Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.hasLocked(d.ID) {
+		return Device{}, fmt.Errorf("%w %q", ErrDeviceExists, d.ID)
+	}
+	p := f.poolForLocked(d.Type)
+	p.Add(d)
+	f.added[d.ID] = true
+	if ds, ok := f.store.(DeviceStore); ok {
+		if err := ds.SaveDevice(AddedDevice{ID: d.ID, Type: d.Type, Labels: d.Labels, Location: d.Location}); err != nil {
+			slog.Error("Persisting device failed", "device_id", d.ID, "err", err)
+		}
+	}
+	added, _ := p.Get(d.ID)
+	return added, nil
+}
+
+// forgetAddedLocked stops treating deviceID as added with AddDevice.
+func (f *Fleet) forgetAddedLocked(deviceID string) {
+	if !f.added[deviceID] {
+		return
+	}
+	delete(f.added, deviceID)
+	if ds, ok := f.store.(DeviceStore); ok {
+		if err := ds.DeleteDevice(deviceID); err != nil {
+			slog.Error("Persisting device failed", "device_id", deviceID, "err", err)
+		}
+	}
+}
+
+// RemoveDevice removes deviceID if it is free, or retires it until its
+// reservation ends; removed reports which.
+func (f *Fleet) RemoveDevice(deviceID string) (dev Device, removed bool, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, p := range f.pools {
+		d, ok := p.Get(deviceID)
+		if !ok {
+			continue
+		}
+		f.forgetAddedLocked(deviceID)
+		removed = p.Retire(deviceID)
+		if removed {
+			if p.Len() == 0 {
+				f.dropPoolLocked(p)
+			}
+			return d, true, nil
+		}
+		d, _ = p.Get(deviceID)
+		return d, false, nil
+	}
+	return Device{}, false, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
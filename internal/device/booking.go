@@ -0,0 +1,265 @@
+package device
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+var (
+	ErrBookingConflict = errors.New("device is already booked or reserved for that time")
+	ErrUnknownBooking  = errors.New("unknown booking")
+)
+
+// Booking reserves a device for a future window. It activates into an
+// ordinary reservation under its lease token when the window starts.
+type Booking struct {
+	ID         string    `json:"id"`
+	DeviceID   string    `json:"device_id"`
+	DeviceType string    `json:"device_type"`
+	User       string    `json:"user"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	LeaseToken string    `json:"lease_token"`
+
+	// overQuota is set once activation has been held back by a quota, so it
+	// is only logged once.
+	overQuota bool
+}
+
+// BookingStore is implemented by stores that also persist bookings.
+type BookingStore interface {
+	SaveBooking(b Booking) error
+	DeleteBooking(id string) error
+	LoadBookings() ([]Booking, error)
+}
+
+// BookingConflict names the booking or reservation a new booking overlaps.
+type BookingConflict struct {
+	DeviceID string
+	// BookingID is empty when the conflict is the current reservation.
+	BookingID string
+	Until     time.Time
+}
+
+func (e *BookingConflict) Error() string {
+	if e.BookingID == "" {
+		return fmt.Sprintf("%s is reserved until %s", e.DeviceID, e.Until.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s is booked by %s until %s", e.DeviceID, e.BookingID, e.Until.Format(time.RFC3339))
+}
+
+func (e *BookingConflict) Is(target error) bool {
+	return target == ErrBookingConflict
+}
+
+func newBookingID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "bk-" + hex.EncodeToString(b)
+}
+
+func overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// Book reserves deviceID for user from start to end. It fails with a
+// *BookingConflict if the window overlaps another booking or the current
+// reservation, or a *QuotaError if user is already at a quota.
+func (p *DevicePool) Book(deviceID, user string, start, end time.Time) (Booking, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var d *Device
+	for _, candidate := range p.devices {
+		if candidate.ID == deviceID && !candidate.Retiring {
+			d = candidate
+		}
+	}
+	if d == nil {
+		return Booking{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+	}
+
+	if IsReserved(d) && d.ExpiresAt.After(start) {
+		return Booking{}, &BookingConflict{DeviceID: deviceID, Until: d.ExpiresAt}
+	}
+	for _, b := range p.bookings {
+		if b.DeviceID == deviceID && overlaps(start, end, b.Start, b.End) {
+			return Booking{}, &BookingConflict{DeviceID: deviceID, BookingID: b.ID, Until: b.End}
+		}
+	}
+	if err := p.quotas.check(user, d.Type); err != nil {
+		return Booking{}, err
+	}
+
+	b := &Booking{
+		ID:         newBookingID(),
+		DeviceID:   deviceID,
+		DeviceType: d.Type,
+		User:       user,
+		Start:      start,
+		End:        end,
+		LeaseToken: newLeaseToken(),
+	}
+	p.addBookingLocked(b)
+	p.saveBookingLocked(*b)
+	slog.Info("Booking created", "booking_id", b.ID, "device_id", deviceID, "user", user, "start", start, "end", end)
+	return *b, nil
+}
+
+func (p *DevicePool) addBookingLocked(b *Booking) {
+	p.bookings = append(p.bookings, b)
+	sort.Slice(p.bookings, func(i, j int) bool { return p.bookings[i].Start.Before(p.bookings[j].Start) })
+}
+
+// CancelBooking removes a booking that has not started. The caller must hold
+// its lease token or be its user.
+func (p *DevicePool) CancelBooking(id, user, token string) (Booking, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, b := range p.bookings {
+		if b.ID != id {
+			continue
+		}
+		if !holdsBooking(b, user, token) {
+			return Booking{}, ErrLeaseMismatch
+		}
+		p.dropBookingLocked(b)
+		slog.Info("Booking cancelled", "booking_id", id, "device_id", b.DeviceID, "user", b.User)
+		return *b, nil
+	}
+	return Booking{}, fmt.Errorf("%w %q", ErrUnknownBooking, id)
+}
+
+func holdsBooking(b *Booking, user, token string) bool {
+	if token != "" {
+		return token == b.LeaseToken
+	}
+	return user != "" && user == b.User
+}
+
+func (p *DevicePool) HasBooking(id string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, b := range p.bookings {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Bookings returns copies of the pool's pending bookings by start time.
+func (p *DevicePool) Bookings() []Booking {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]Booking, len(p.bookings))
+	for i, b := range p.bookings {
+		result[i] = *b
+	}
+	return result
+}
+
+// bookingFreeLocked reports whether d has no booking starting before until.
+func (p *DevicePool) bookingFreeLocked(d *Device, until time.Time) bool {
+	next, ok := p.nextBookingLocked(d.ID)
+	return !ok || !next.Start.Before(until)
+}
+
+func (p *DevicePool) nextBookingLocked(deviceID string) (*Booking, bool) {
+	for _, b := range p.bookings {
+		if b.DeviceID == deviceID {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// activateBookingsLocked turns bookings whose window has started into
+// reservations, and drops those whose window passed while the device was
+// still held. A booking whose user is at a quota waits, holding the device,
+// until they are not.
+func (p *DevicePool) activateBookingsLocked(now time.Time) {
+	for _, b := range append([]*Booking(nil), p.bookings...) {
+		if b.Start.After(now) {
+			continue
+		}
+		if !b.End.After(now) {
+			slog.Warn("Booking missed", "booking_id", b.ID, "device_id", b.DeviceID, "user", b.User)
+			p.dropBookingLocked(b)
+			continue
+		}
+		for _, d := range p.devices {
+			if d.ID == b.DeviceID && d.ReservedBy == "" && d.Health == Healthy && !d.Draining {
+				if err := p.quotas.acquire(b.User, d.Type); err != nil {
+					if !b.overQuota {
+						b.overQuota = true
+						slog.Warn("Booking held back by quota", "booking_id", b.ID, "device_id", d.ID, "user", b.User, "err", err)
+					}
+					break
+				}
+				p.dropBookingLocked(b)
+				p.assignLocked(d, b.User, b.End.Sub(now), 0, b.LeaseToken)
+				slog.Info("Booking activated", "booking_id", b.ID, "device_id", d.ID, "user", b.User, "expires_at", d.ExpiresAt)
+				break
+			}
+		}
+	}
+}
+
+func (p *DevicePool) dropBookingLocked(b *Booking) {
+	for i, existing := range p.bookings {
+		if existing == b {
+			p.bookings = append(p.bookings[:i:i], p.bookings[i+1:]...)
+			break
+		}
+	}
+	if bs, ok := p.store.(BookingStore); ok {
+		if err := bs.DeleteBooking(b.ID); err != nil {
+			slog.Error("Persisting booking failed", "booking_id", b.ID, "err", err)
+		}
+	}
+}
+
+func (p *DevicePool) saveBookingLocked(b Booking) {
+	if bs, ok := p.store.(BookingStore); ok {
+		if err := bs.SaveBooking(b); err != nil {
+			slog.Error("Persisting booking failed", "booking_id", b.ID, "err", err)
+		}
+	}
+}
+
+// Book books deviceID in whichever pool holds it.
+func (f *Fleet) Book(deviceID, user string, start, end time.Time) (Booking, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Booking{}, err
+	}
+	return p.Book(deviceID, user, start, end)
+}
+
+func (f *Fleet) CancelBooking(id, user, token string) (Booking, error) {
+	for _, p := range f.Pools() {
+		if p.HasBooking(id) {
+			return p.CancelBooking(id, user, token)
+		}
+	}
+	return Booking{}, fmt.Errorf("%w %q", ErrUnknownBooking, id)
+}
+
+// Bookings returns every pending booking by start time.
+func (f *Fleet) Bookings() []Booking {
+	var result []Booking
+	for _, p := range f.Pools() {
+		result = append(result, p.Bookings()...)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
@@ -1,15 +1,47 @@
 package device
 
-import "time"
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
 
 type Device struct {
 	ID         string
 	Type       string
+	Labels     map[string]string
+	Location   string
 	ReservedBy string
 	ReservedAt time.Time
 	ExpiresAt  time.Time
+	LeaseToken string
+	// Priority is the holder's reservation priority.
+	Priority int
+	// PreemptAt is when a higher-priority waiter takes the device from its
+	// holder; zero if no preemption is scheduled.
+	PreemptAt time.Time
+	Retiring  bool
+	// Draining devices are not handed out; see IsDrained.
+	Draining bool
+
+	Health       Health
+	HealthReason string
+	// Failures counts reservations reported as failed since the device was
+	// last healthy.
+	Failures        int
+	failureReported string
 }
 
 func IsAvailable(d *Device) bool {
-	return d.ReservedBy == "" || time.Now().After(d.ExpiresAt)
+	return !d.Retiring && !d.Draining && d.Health == Healthy && !IsReserved(d)
+}
+
+func IsReserved(d *Device) bool {
+	return d.ReservedBy != "" && !time.Now().After(d.ExpiresAt)
+}
+
+func newLeaseToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
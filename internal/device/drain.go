@@ -0,0 +1,142 @@
+package device
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// IsDrained reports whether d is draining and its last reservation has ended.
+func IsDrained(d *Device) bool {
+	return d.Draining && !IsReserved(d)
+}
+
+// Drain stops deviceID from being handed out. Its holder keeps it until
+// release or expiry, after which an EventDrained is published; a free device
+// is drained at once.
+func (p *DevicePool) Drain(deviceID string) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID {
+			p.drainLocked(d)
+			return *d, nil
+		}
+	}
+	return Device{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+// DrainAll drains every device in the pool, including devices added later,
+// until UndrainAll.
+func (p *DevicePool) DrainAll() []Device {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.draining = true
+	result := make([]Device, len(p.devices))
+	for i, d := range p.devices {
+		p.drainLocked(d)
+		result[i] = *d
+	}
+	slog.Info("Pool draining", "type", p.deviceType)
+	return result
+}
+
+func (p *DevicePool) drainLocked(d *Device) {
+	if d.Draining {
+		return
+	}
+	d.Draining = true
+	p.persistLocked(d)
+	p.unpreemptLocked(d)
+	if IsReserved(d) {
+		slog.Info("Device draining", "device_id", d.ID, "holder", d.ReservedBy, "expires_at", d.ExpiresAt)
+		p.publishLocked(EventDraining, d)
+		return
+	}
+	slog.Info("Device drained", "device_id", d.ID)
+	p.publishLocked(EventDrained, d)
+}
+
+// Undrain returns deviceID to service.
+func (p *DevicePool) Undrain(deviceID string) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID {
+			p.undrainLocked(d)
+			p.dispatchLocked(p.deviceType)
+			return *d, nil
+		}
+	}
+	return Device{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+// UndrainAll returns every device in the pool to service.
+func (p *DevicePool) UndrainAll() []Device {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.draining = false
+	result := make([]Device, len(p.devices))
+	for i, d := range p.devices {
+		p.undrainLocked(d)
+		result[i] = *d
+	}
+	p.dispatchLocked(p.deviceType)
+	slog.Info("Pool undrained", "type", p.deviceType)
+	return result
+}
+
+func (p *DevicePool) undrainLocked(d *Device) {
+	if !d.Draining {
+		return
+	}
+	d.Draining = false
+	p.persistLocked(d)
+	slog.Info("Device undrained", "device_id", d.ID)
+	p.publishLocked(EventUndrained, d)
+}
+
+// Draining reports whether the whole pool is draining.
+func (p *DevicePool) Draining() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.draining
+}
+
+// Drain drains deviceID in whichever pool holds it.
+func (f *Fleet) Drain(deviceID string) (Device, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, err
+	}
+	return p.Drain(deviceID)
+}
+
+func (f *Fleet) Undrain(deviceID string) (Device, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, err
+	}
+	return p.Undrain(deviceID)
+}
+
+// DrainType drains every device of deviceType.
+func (f *Fleet) DrainType(deviceType string) ([]Device, error) {
+	p, err := f.Pool(deviceType)
+	if err != nil {
+		return nil, err
+	}
+	return p.DrainAll(), nil
+}
+
+func (f *Fleet) UndrainType(deviceType string) ([]Device, error) {
+	p, err := f.Pool(deviceType)
+	if err != nil {
+		return nil, err
+	}
+	return p.UndrainAll(), nil
+}
@@ -0,0 +1,145 @@
+package device
+
+import "sync"
+
+type EventKind string
+
+const (
+	EventSnapshot EventKind = "snapshot"
+	EventReserved EventKind = "reserved"
+	EventReleased EventKind = "released"
+	EventExpired  EventKind = "expired"
+	EventExtended EventKind = "extended"
+	EventAdded    EventKind = "added"
+	EventUpdated  EventKind = "updated"
+	EventRetiring EventKind = "retiring"
+	EventRemoved  EventKind = "removed"
+
+	EventPreempting          EventKind = "preempting"
+	EventPreempted           EventKind = "preempted"
+	EventPreemptionCancelled EventKind = "preemption_cancelled"
+
+	EventHealthChanged EventKind = "health_changed"
+
+	EventDraining  EventKind = "draining"
+	EventDrained   EventKind = "drained"
+	EventUndrained EventKind = "undrained"
+
+	EventForceReleased EventKind = "force_released"
+	EventTransferred   EventKind = "transferred"
+)
+
+const historySize = 1024
+
+// Event describes a change to one device; Device is a copy taken when the
+// change happened. Revisions increase by one with every published event and
+// restart with every broker, which Epoch identifies.
+// PreviousHolder is set on release, expiry and transfer events.
+type Event struct {
+	Epoch          string
+	Revision       uint64
+	Kind           EventKind
+	Device         Device
+	PreviousHolder string
+}
+
+// Broker fans pool events out to subscribers without ever blocking the pool,
+// and keeps recent events so reconnecting watchers can catch up.
+type Broker struct {
+	mu       sync.Mutex
+	subs     map[*Subscription]struct{}
+	epoch    string
+	revision uint64
+	history  []Event
+}
+
+// Subscription delivers events on C. C is closed if the subscriber falls more
+// than its buffer behind, after which it must resubscribe and resync.
+type Subscription struct {
+	C      <-chan Event
+	events chan Event
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[*Subscription]struct{}), epoch: newLeaseToken()}
+}
+
+func (b *Broker) Subscribe(buffer int) *Subscription {
+	sub, _, _ := b.SubscribeSince(b.epoch, 0, buffer)
+	return sub
+}
+
+// SubscribeSince subscribes and returns the buffered events after revision
+// since of epoch. ok is false when epoch is another broker's, such as one
+// from before a restart, or the events are no longer buffered, and the
+// caller must resync from a snapshot.
+func (b *Broker) SubscribeSince(epoch string, since uint64, buffer int) (sub *Subscription, missed []Event, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := make(chan Event, buffer)
+	sub = &Subscription{C: events, events: events}
+	b.subs[sub] = struct{}{}
+
+	if epoch != b.epoch || since > b.revision {
+		return sub, nil, false
+	}
+	if since < b.revision-uint64(len(b.history)) {
+		return sub, nil, false
+	}
+	for _, e := range b.history {
+		if e.Revision > since {
+			missed = append(missed, e)
+		}
+	}
+	return sub, missed, true
+}
+
+func (b *Broker) Epoch() string {
+	return b.epoch
+}
+
+func (b *Broker) Revision() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.revision
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+func (b *Broker) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revision++
+	e.Epoch, e.Revision = b.epoch, b.revision
+	if len(b.history) == historySize {
+		b.history = b.history[1:]
+	}
+	b.history = append(b.history, e)
+
+	for sub := range b.subs {
+		select {
+		case sub.events <- e:
+		default:
+			delete(b.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (p *DevicePool) publishLocked(kind EventKind, d *Device) {
+	if p.events == nil {
+		return
+	}
+	p.events.Publish(Event{Kind: kind, Device: *d})
+}
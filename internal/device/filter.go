@@ -0,0 +1,40 @@
+package device
+
+import "slices"
+
+// Filter selects devices for a watcher. Empty fields match everything; set
+// fields must all match.
+type Filter struct {
+	Types      []string
+	DeviceIDs  []string
+	Labels     map[string]string
+	ReservedBy string
+}
+
+func (f Filter) Matches(d Device) bool {
+	return f.matches(d, d.ReservedBy)
+}
+
+// MatchesEvent also matches a release or expiry for the filter's user, so
+// holders see their own reservations end.
+func (f Filter) MatchesEvent(e Event) bool {
+	if f.matches(e.Device, e.Device.ReservedBy) {
+		return true
+	}
+	return e.PreviousHolder != "" && f.matches(e.Device, e.PreviousHolder)
+}
+
+func (f Filter) matches(d Device, holder string) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, d.Type) {
+		return false
+	}
+	if len(f.DeviceIDs) > 0 && !slices.Contains(f.DeviceIDs, d.ID) {
+		return false
+	}
+	for k, v := range f.Labels {
+		if d.Labels[k] != v {
+			return false
+		}
+	}
+	return f.ReservedBy == "" || f.ReservedBy == holder
+}
@@ -0,0 +1,113 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrUnknownType = errors.New("unknown device type")
+
+// Fleet is the registry of device pools, one per device type.
+type Fleet struct {
+	mu     sync.RWMutex
+	pools  map[string]*DevicePool
+	store  Store
+	events *Broker
+	quotas *quotaTracker
+	health *healthPolicy
+	// added holds the IDs of devices added with AddDevice.
+	added map[string]bool
+}
+
+func NewFleet(pools ...*DevicePool) *Fleet {
+	f := &Fleet{pools: make(map[string]*DevicePool), events: NewBroker(), quotas: newQuotaTracker(), health: &healthPolicy{}, added: make(map[string]bool)}
+	for _, p := range pools {
+		p.mu.Lock()
+		p.events = f.events
+		p.quotas = f.quotas
+		p.health = f.health
+		for _, d := range p.devices {
+			if d.ReservedBy != "" {
+				f.quotas.add(d.ReservedBy, d.Type)
+			}
+		}
+		p.mu.Unlock()
+		f.pools[p.Type()] = p
+	}
+	return f
+}
+
+func (f *Fleet) Events() *Broker {
+	return f.events
+}
+
+func (f *Fleet) Pool(deviceType string) (*DevicePool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	p, ok := f.pools[deviceType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, deviceType)
+	}
+	return p, nil
+}
+
+// PoolFor returns the pool holding deviceID.
+func (f *Fleet) PoolFor(deviceID string) (*DevicePool, error) {
+	for _, p := range f.Pools() {
+		if p.Has(deviceID) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+func (f *Fleet) hasLocked(deviceID string) bool {
+	for _, p := range f.pools {
+		if p.Has(deviceID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pools returns every pool ordered by device type.
+func (f *Fleet) Pools() []*DevicePool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := make([]*DevicePool, 0, len(f.pools))
+	for _, p := range f.pools {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type() < result[j].Type() })
+	return result
+}
+
+func (f *Fleet) All() []*Device {
+	var result []*Device
+	for _, p := range f.Pools() {
+		result = append(result, p.All()...)
+	}
+	return result
+}
+
+func (f *Fleet) Snapshot() []Device {
+	var result []Device
+	for _, p := range f.Pools() {
+		result = append(result, p.Snapshot()...)
+	}
+	return result
+}
+
+func (f *Fleet) CleanupExpired() {
+	ticker := time.NewTicker(1 * time.Second)
+	for range ticker.C {
+		for _, p := range f.Pools() {
+			p.Expire()
+		}
+	}
+}
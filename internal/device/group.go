@@ -0,0 +1,214 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrUnsatisfiable = errors.New("no devices in the fleet can satisfy every requirement")
+
+// Requirement is one device of a group reservation.
+type Requirement struct {
+	Type     string
+	Selector Selector
+	Scope    Scope
+}
+
+func (r Requirement) matches(d *Device) bool {
+	return d.Type == r.Type && r.Selector.Matches(d) && r.Scope.Allows(d)
+}
+
+// ReserveGroup reserves one device per requirement, all or none, under a
+// single lease token shared by every device in the group. It returns
+// ErrUnsatisfiable when the fleet could never satisfy reqs together,
+// ErrNoDevices when it could but the devices are busy and a *QuotaError when
+// the group would take user over quota.
+func (f *Fleet) ReserveGroup(user string, reqs []Requirement, ttl time.Duration) ([]Device, error) {
+	var pools []*DevicePool
+	for _, r := range reqs {
+		p, err := f.Pool(r.Type)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, p)
+	}
+	unlock := lockPools(pools)
+	defer unlock()
+
+	var usable, available []*Device
+	until := time.Now().Add(ttl)
+	for _, p := range uniquePools(pools) {
+		p.dispatchLocked(p.deviceType)
+		for _, d := range p.devices {
+			if d.Retiring {
+				continue
+			}
+			usable = append(usable, d)
+			if IsAvailable(d) && p.bookingFreeLocked(d, until) {
+				available = append(available, d)
+			}
+		}
+	}
+	if matchRequirements(reqs, usable) == nil {
+		return nil, ErrUnsatisfiable
+	}
+	types := make([]string, len(reqs))
+	for i, r := range reqs {
+		types[i] = r.Type
+	}
+	matched := matchRequirements(reqs, available)
+	if matched == nil {
+		if err := f.quotas.check(user, types...); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoDevices
+	}
+	if err := f.quotas.acquire(user, types...); err != nil {
+		return nil, err
+	}
+
+	token := newLeaseToken()
+	result := make([]Device, len(matched))
+	for i, d := range matched {
+		poolOf(pools, d).assignLocked(d, user, ttl, 0, token)
+		result[i] = *d
+	}
+	return result, nil
+}
+
+// Group returns copies of the devices reserved under token.
+func (f *Fleet) Group(token string) []Device {
+	var held []Device
+	for _, p := range f.Pools() {
+		for _, d := range p.Snapshot() {
+			if IsReserved(&d) && d.LeaseToken == token {
+				held = append(held, d)
+			}
+		}
+	}
+	return held
+}
+
+// ReleaseGroup releases every device reserved under token.
+func (f *Fleet) ReleaseGroup(token string) ([]Device, error) {
+	pools := f.Pools()
+	unlock := lockPools(pools)
+	defer unlock()
+
+	var released []Device
+	for _, p := range pools {
+		for _, d := range append([]*Device(nil), p.devices...) {
+			if IsReserved(d) && d.LeaseToken == token {
+				released = append(released, *d)
+				p.freeLocked(d, EventReleased)
+			}
+		}
+	}
+	if len(released) == 0 {
+		return nil, fmt.Errorf("%w: no devices hold group lease", ErrNotReserved)
+	}
+	return released, nil
+}
+
+// ExtendGroup extends every device reserved under token, or none if any of
+// them is already at maxLease or its next booking.
+func (f *Fleet) ExtendGroup(token string, extension, maxLease time.Duration) ([]Device, error) {
+	pools := f.Pools()
+	unlock := lockPools(pools)
+	defer unlock()
+
+	var held []*Device
+	var limits []time.Time
+	for _, p := range pools {
+		for _, d := range p.devices {
+			if IsReserved(d) && d.LeaseToken == token {
+				limit, err := p.extendLimitLocked(d, maxLease)
+				if err != nil {
+					return nil, err
+				}
+				held = append(held, d)
+				limits = append(limits, limit)
+			}
+		}
+	}
+	if len(held) == 0 {
+		return nil, fmt.Errorf("%w: no devices hold group lease", ErrNotReserved)
+	}
+
+	extended := make([]Device, len(held))
+	for i, d := range held {
+		poolOf(pools, d).extendLocked(d, extension, limits[i])
+		extended[i] = *d
+	}
+	return extended, nil
+}
+
+// lockPools write-locks each distinct pool in device type order, so
+// concurrent group operations cannot deadlock, and returns the unlock.
+func lockPools(pools []*DevicePool) func() {
+	unique := uniquePools(pools)
+	for _, p := range unique {
+		p.mu.Lock()
+	}
+	return func() {
+		for i := len(unique) - 1; i >= 0; i-- {
+			unique[i].mu.Unlock()
+		}
+	}
+}
+
+func uniquePools(pools []*DevicePool) []*DevicePool {
+	seen := make(map[*DevicePool]bool)
+	var unique []*DevicePool
+	for _, p := range pools {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].deviceType < unique[j].deviceType })
+	return unique
+}
+
+func poolOf(pools []*DevicePool, d *Device) *DevicePool {
+	for _, p := range pools {
+		if p.deviceType == d.Type {
+			return p
+		}
+	}
+	return nil
+}
+
+// matchRequirements gives each requirement a distinct matching device, or
+// returns nil if that is impossible. It uses augmenting paths so a broad
+// requirement never takes the only device a narrower one could use.
+func matchRequirements(reqs []Requirement, devices []*Device) []*Device {
+	owner := make(map[*Device]int)
+	var assign func(i int, seen map[*Device]bool) bool
+	assign = func(i int, seen map[*Device]bool) bool {
+		for _, d := range devices {
+			if seen[d] || !reqs[i].matches(d) {
+				continue
+			}
+			seen[d] = true
+			if j, taken := owner[d]; !taken || assign(j, seen) {
+				owner[d] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range reqs {
+		if !assign(i, make(map[*Device]bool)) {
+			return nil
+		}
+	}
+	result := make([]*Device, len(reqs))
+	for d, i := range owner {
+		result[i] = d
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package device
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Health is a device's operational state. Only healthy devices are handed
+// out; a holder keeps a device that becomes unhealthy until they release it.
+type Health int
+
+const (
+	Healthy Health = iota
+	Maintenance
+	Offline
+	Quarantined
+)
+
+var healthNames = []string{"healthy", "maintenance", "offline", "quarantined"}
+
+func (h Health) String() string {
+	if h < 0 || int(h) >= len(healthNames) {
+		return fmt.Sprintf("Health(%d)", int(h))
+	}
+	return healthNames[h]
+}
+
+func ParseHealth(s string) (Health, error) {
+	for i, name := range healthNames {
+		if s == name {
+			return Health(i), nil
+		}
+	}
+	return Healthy, fmt.Errorf("unknown health state %q", s)
+}
+
+func (h Health) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *Health) UnmarshalText(text []byte) error {
+	parsed, err := ParseHealth(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// healthPolicy is shared by a fleet's pools.
+type healthPolicy struct {
+	quarantineAfter atomic.Int64
+}
+
+func (hp *healthPolicy) threshold() int {
+	if hp == nil {
+		return 0
+	}
+	return int(hp.quarantineAfter.Load())
+}
+
+// SetHealth changes deviceID's health state. Returning a device to healthy
+// clears its reported failures and makes it available again.
+func (p *DevicePool) SetHealth(deviceID string, h Health, reason string) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID {
+			p.setHealthLocked(d, h, reason)
+			return *d, nil
+		}
+	}
+	return Device{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+func (p *DevicePool) setHealthLocked(d *Device, h Health, reason string) {
+	previous := d.Health
+	d.Health = h
+	d.HealthReason = reason
+	if h == Healthy {
+		d.Failures = 0
+	}
+	slog.Info("Device health changed", "device_id", d.ID, "from", previous, "to", h, "reason", reason)
+	p.persistLocked(d)
+	p.publishLocked(EventHealthChanged, d)
+
+	if h != Healthy {
+		p.unpreemptLocked(d)
+	}
+	p.dispatchLocked(d.Type)
+}
+
+// ReportFailure records that the current reservation of deviceID failed
+// because of the device. Each reservation counts once; after the fleet's
+// quarantine threshold of failures the device is quarantined.
+func (p *DevicePool) ReportFailure(deviceID, user, token, reason string) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID != deviceID {
+			continue
+		}
+		if !IsReserved(d) {
+			return Device{}, ErrNotReserved
+		}
+		if !holdsLease(d, user, token) {
+			return Device{}, ErrLeaseMismatch
+		}
+		if d.failureReported == d.LeaseToken {
+			return *d, nil
+		}
+
+		d.failureReported = d.LeaseToken
+		d.Failures++
+		slog.Warn("Device failure reported", "device_id", d.ID, "user", d.ReservedBy, "failures", d.Failures, "reason", reason)
+		if limit := p.health.threshold(); limit > 0 && d.Failures >= limit && d.Health == Healthy {
+			p.setHealthLocked(d, Quarantined, fmt.Sprintf("%d failures reported, last: %s", d.Failures, reason))
+		}
+		return *d, nil
+	}
+	return Device{}, fmt.Errorf("%w %q", ErrUnknownDevice, deviceID)
+}
+
+// SetHealth changes the health of deviceID in whichever pool holds it.
+func (f *Fleet) SetHealth(deviceID string, h Health, reason string) (Device, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, err
+	}
+	return p.SetHealth(deviceID, h, reason)
+}
+
+func (f *Fleet) ReportFailure(deviceID, user, token, reason string) (Device, error) {
+	p, err := f.PoolFor(deviceID)
+	if err != nil {
+		return Device{}, err
+	}
+	return p.ReportFailure(deviceID, user, token, reason)
+}
+
+// SetQuarantineAfter sets how many reported failures quarantine a healthy
+// device. Zero disables automatic quarantine.
+func (f *Fleet) SetQuarantineAfter(failures int) {
+	f.health.quarantineAfter.Store(int64(failures))
+}
@@ -0,0 +1,36 @@
+package device
+
+import "time"
+
+type LeasePolicy struct {
+	MinTTL     time.Duration
+	MaxTTL     time.Duration
+	DefaultTTL time.Duration
+	MaxLease   time.Duration
+	// PreemptGrace is how long a holder keeps a device after a higher-priority
+	// waiter claims it. Zero disables preemption.
+	PreemptGrace time.Duration
+}
+
+func DefaultLeasePolicy() LeasePolicy {
+	return LeasePolicy{
+		MinTTL:     10 * time.Second,
+		MaxTTL:     1 * time.Hour,
+		DefaultTTL: 2 * time.Minute,
+		MaxLease:   8 * time.Hour,
+	}
+}
+
+// ClampTTL returns the TTL to grant for a requested one; zero means the default.
+func (lp LeasePolicy) ClampTTL(requested time.Duration) time.Duration {
+	if requested <= 0 {
+		return lp.DefaultTTL
+	}
+	if requested < lp.MinTTL {
+		return lp.MinTTL
+	}
+	if requested > lp.MaxTTL {
+		return lp.MaxTTL
+	}
+	return requested
+}
@@ -1,18 +1,44 @@
 package device
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 )
 
+var (
+	ErrNoDevices     = errors.New("no devices available")
+	ErrUnknownDevice = errors.New("unknown device")
+	ErrNotReserved   = errors.New("device is not reserved")
+	ErrLeaseMismatch = errors.New("lease token or user does not match current holder")
+	ErrMaxLease      = errors.New("reservation already at maximum lease length")
+)
+
 type DevicePool struct {
-	mu      sync.RWMutex
-	devices []*Device
+	mu         sync.RWMutex
+	deviceType string
+	devices    []*Device
+	queues     map[string][]*Waiter
+	store      Store
+	events     *Broker
+	quotas     *quotaTracker
+	// preemptions maps a device ID to the waiter it is being preempted for.
+	preemptions map[string]*Waiter
+	bookings    []*Booking
+	health      *healthPolicy
+	// draining is set while the whole pool drains.
+	draining bool
+	// dropped is set once the pool is removed from its fleet.
+	dropped error
 }
 
 func NewDevicePool(deviceType string, count int) *DevicePool {
-	pool := &DevicePool{}
+	pool := &DevicePool{
+		deviceType:  deviceType,
+		queues:      make(map[string][]*Waiter),
+		preemptions: make(map[string]*Waiter),
+	}
 	for i := 0; i < count; i++ {
 		pool.devices = append(pool.devices, &Device{
 			ID:   fmt.Sprintf("%s-%d", deviceType, i),
@@ -22,47 +48,256 @@ func NewDevicePool(deviceType string, count int) *DevicePool {
 	return pool
 }
 
-func (p *DevicePool) Reserve(user, requestedType string, ttl time.Duration) (*Device, bool) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+func NewDevicePoolWithDevices(deviceType string, devices []*Device) *DevicePool {
+	return &DevicePool{
+		deviceType:  deviceType,
+		devices:     devices,
+		queues:      make(map[string][]*Waiter),
+		preemptions: make(map[string]*Waiter),
+	}
+}
+
+func (p *DevicePool) Type() string {
+	return p.deviceType
+}
+
+func (p *DevicePool) Has(deviceID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 
 	for _, d := range p.devices {
-		if d.Type == requestedType && IsAvailable(d) {
-			now := time.Now()
-			d.ReservedBy = user
-			d.ReservedAt = now
-			d.ExpiresAt = now.Add(ttl)
-			return d, true
+		if d.ID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns a copy of deviceID.
+func (p *DevicePool) Get(deviceID string) (Device, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID {
+			return *d, true
 		}
 	}
-	return nil, false
+	return Device{}, false
+}
+
+func (p *DevicePool) Reserve(user, requestedType string, ttl time.Duration) (Device, bool) {
+	d, err := p.ReserveMatching(Request{User: user, Type: requestedType, TTL: ttl})
+	return d, err == nil
 }
 
-func (p *DevicePool) Release(deviceID string) bool {
+// ReserveMatching reserves an available device of r.Type that r.Selector
+// matches and r.Scope allows, after serving waiters of at least r.Priority.
+// It returns a copy of the reserved device and fails with ErrNoDevices or,
+// when the user is over quota, a *QuotaError.
+func (p *DevicePool) ReserveMatching(r Request) (Device, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	p.dispatchFromLocked(r.Type, r.Priority)
+	d := p.findAvailableLocked(r.Type, r.Selector, r.Scope, r.TTL)
+	if d == nil {
+		if err := p.quotas.check(r.User, r.Type); err != nil {
+			return Device{}, err
+		}
+		return Device{}, ErrNoDevices
+	}
+	if err := p.quotas.acquire(r.User, r.Type); err != nil {
+		return Device{}, err
+	}
+	p.assignLocked(d, r.User, r.TTL, r.Priority, newLeaseToken())
+	return *d, nil
+}
+
+// CanMatch reports whether any device of deviceType that is not being
+// retired matches sel and is in scope, reserved or not.
+func (p *DevicePool) CanMatch(deviceType string, sel Selector, scope Scope) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	for _, d := range p.devices {
-		if d.ID == deviceID && !IsAvailable(d) {
-			d.ReservedBy = ""
+		if d.Type == deviceType && !d.Retiring && sel.Matches(d) && scope.Allows(d) {
 			return true
 		}
 	}
 	return false
 }
 
+// findAvailableLocked returns a free device of deviceType that sel matches,
+// scope allows and that is not booked within ttl from now.
+func (p *DevicePool) findAvailableLocked(deviceType string, sel Selector, scope Scope, ttl time.Duration) *Device {
+	until := time.Now().Add(ttl)
+	for _, d := range p.devices {
+		if d.Type == deviceType && IsAvailable(d) && sel.Matches(d) && scope.Allows(d) && p.bookingFreeLocked(d, until) {
+			return d
+		}
+	}
+	return nil
+}
+
+func (p *DevicePool) assignLocked(d *Device, user string, ttl time.Duration, priority int, token string) {
+	if d.ReservedBy != "" {
+		// Expired but not yet swept; end the old lease as the sweep would,
+		// without handing d to a waiter.
+		p.publishFreedLocked(EventExpired, d, p.clearLocked(d))
+	}
+	now := time.Now()
+	d.ReservedBy = user
+	d.Priority = priority
+	d.ReservedAt = now
+	d.ExpiresAt = now.Add(ttl)
+	d.LeaseToken = token
+	p.persistLocked(d)
+	p.publishLocked(EventReserved, d)
+}
+
+func (p *DevicePool) Release(deviceID, user, token string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID && IsReserved(d) {
+			if !holdsLease(d, user, token) {
+				return ErrLeaseMismatch
+			}
+			p.freeLocked(d, EventReleased)
+			return nil
+		}
+	}
+	return ErrNotReserved
+}
+
+// Extend pushes deviceID's expiry out by extension, up to maxLease, and
+// returns a copy of the extended device.
+func (p *DevicePool) Extend(deviceID, user, token string, extension, maxLease time.Duration) (Device, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == deviceID && IsReserved(d) {
+			if !holdsLease(d, user, token) {
+				return Device{}, ErrLeaseMismatch
+			}
+			limit, err := p.extendLimitLocked(d, maxLease)
+			if err != nil {
+				return Device{}, err
+			}
+			p.extendLocked(d, extension, limit)
+			return *d, nil
+		}
+	}
+	return Device{}, ErrNotReserved
+}
+
+// extendLimitLocked returns how far d's reservation may be extended: maxLease
+// after it was reserved or the start of its next booking, whichever is first.
+// It fails if d is already there.
+func (p *DevicePool) extendLimitLocked(d *Device, maxLease time.Duration) (time.Time, error) {
+	limit := d.ReservedAt.Add(maxLease)
+	if !d.ExpiresAt.Before(limit) {
+		return limit, ErrMaxLease
+	}
+	if b, ok := p.nextBookingLocked(d.ID); ok && b.Start.Before(limit) {
+		if !d.ExpiresAt.Before(b.Start) {
+			return b.Start, &BookingConflict{DeviceID: d.ID, BookingID: b.ID, Until: b.End}
+		}
+		limit = b.Start
+	}
+	return limit, nil
+}
+
+// extendLocked pushes d's expiry out by extension, capped at limit.
+func (p *DevicePool) extendLocked(d *Device, extension time.Duration, limit time.Time) {
+	d.ExpiresAt = d.ExpiresAt.Add(extension)
+	if d.ExpiresAt.After(limit) {
+		d.ExpiresAt = limit
+	}
+	p.persistLocked(d)
+	p.publishLocked(EventExtended, d)
+}
+
+// freeLocked clears d's reservation, drops it if it is being retired and
+// otherwise hands it to the next waiter.
+func (p *DevicePool) freeLocked(d *Device, kind EventKind) {
+	holder := p.clearLocked(d)
+	p.persistLocked(d)
+	p.publishFreedLocked(kind, d, holder)
+	if d.Retiring {
+		p.removeLocked(d.ID)
+		return
+	}
+	if d.Draining {
+		p.publishLocked(EventDrained, d)
+	}
+	p.dispatchLocked(d.Type)
+}
+
+// clearLocked ends d's reservation and releases its holder's quota,
+// returning the holder.
+func (p *DevicePool) clearLocked(d *Device) string {
+	holder := d.ReservedBy
+	p.quotas.release(holder, d.Type)
+	d.ReservedBy = ""
+	d.LeaseToken = ""
+	d.Priority = 0
+	d.PreemptAt = time.Time{}
+	delete(p.preemptions, d.ID)
+	return holder
+}
+
+func (p *DevicePool) publishFreedLocked(kind EventKind, d *Device, holder string) {
+	if p.events != nil {
+		p.events.Publish(Event{Kind: kind, Device: *d, PreviousHolder: holder})
+	}
+}
+
+func holdsLease(d *Device, user, token string) bool {
+	if token != "" {
+		return token == d.LeaseToken
+	}
+	return user != "" && user == d.ReservedBy
+}
+
 func (p *DevicePool) CleanupExpired() {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(1 * time.Second)
 	for range ticker.C {
-		p.mu.Lock()
-		now := time.Now()
-		for _, d := range p.devices {
-			if d.ReservedBy != "" && now.After(d.ExpiresAt) {
-				d.ReservedBy = ""
-			}
+		p.Expire()
+	}
+}
+
+func (p *DevicePool) Expire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, d := range append([]*Device(nil), p.devices...) {
+		if d.ReservedBy != "" && now.After(d.ExpiresAt) {
+			p.freeLocked(d, EventExpired)
+			continue
 		}
-		p.mu.Unlock()
+		p.evictLocked(d, now)
 	}
+	p.activateBookingsLocked(now)
+	for deviceType := range p.queues {
+		p.dispatchLocked(deviceType)
+	}
+}
+
+// Snapshot returns copies of every device, safe to read without the pool lock.
+func (p *DevicePool) Snapshot() []Device {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := make([]Device, len(p.devices))
+	for i, d := range p.devices {
+		result[i] = *d
+	}
+	return result
 }
 
 func (p *DevicePool) All() []*Device {
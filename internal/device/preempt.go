@@ -0,0 +1,100 @@
+package device
+
+import (
+	"log/slog"
+	"time"
+)
+
+// schedulePreemptionsLocked gives each preempting waiter on the deviceType
+// queue that has no device a victim: the lowest-priority holder of a device
+// it matches, soonest to expire first. The holder is notified and keeps the
+// device for the waiter's grace period.
+func (p *DevicePool) schedulePreemptionsLocked(deviceType string) {
+	for _, w := range p.queues[deviceType] {
+		if w.preemptAfter <= 0 || p.victimOfLocked(w) != nil || p.quotas.check(w.user, deviceType) != nil {
+			continue
+		}
+		d := p.findVictimLocked(w)
+		if d == nil {
+			continue
+		}
+
+		d.PreemptAt = time.Now().Add(w.preemptAfter)
+		p.preemptions[d.ID] = w
+		slog.Info("Preemption scheduled",
+			"device_id", d.ID,
+			"holder", d.ReservedBy,
+			"holder_priority", d.Priority,
+			"for", w.user,
+			"priority", w.priority,
+			"preempt_at", d.PreemptAt,
+		)
+		p.publishLocked(EventPreempting, d)
+	}
+}
+
+func (p *DevicePool) findVictimLocked(w *Waiter) *Device {
+	var victim *Device
+	for _, d := range p.devices {
+		if d.Type != w.deviceType || d.Retiring || d.Draining || d.Health != Healthy || !IsReserved(d) || !d.PreemptAt.IsZero() {
+			continue
+		}
+		if d.Priority >= w.priority || !w.selector.Matches(d) || !w.scope.Allows(d) {
+			continue
+		}
+		if victim == nil || d.Priority < victim.Priority ||
+			(d.Priority == victim.Priority && d.ExpiresAt.Before(victim.ExpiresAt)) {
+			victim = d
+		}
+	}
+	return victim
+}
+
+func (p *DevicePool) victimOfLocked(w *Waiter) *Device {
+	for id, waiter := range p.preemptions {
+		if waiter == w {
+			for _, d := range p.devices {
+				if d.ID == id {
+					return d
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cancelPreemptionLocked lets the holder of w's victim keep it.
+func (p *DevicePool) cancelPreemptionLocked(w *Waiter) {
+	d := p.victimOfLocked(w)
+	if d == nil {
+		return
+	}
+	delete(p.preemptions, d.ID)
+	d.PreemptAt = time.Time{}
+	slog.Info("Preemption cancelled", "device_id", d.ID, "holder", d.ReservedBy, "for", w.user)
+	p.publishLocked(EventPreemptionCancelled, d)
+}
+
+// unpreemptLocked calls off any preemption of d once it can no longer be
+// handed out, since evicting its holder would not free a usable device.
+func (p *DevicePool) unpreemptLocked(d *Device) {
+	if waiter := p.preemptions[d.ID]; waiter != nil {
+		p.cancelPreemptionLocked(waiter)
+	}
+}
+
+// evictLocked frees d from its holder once its preemption is due.
+func (p *DevicePool) evictLocked(d *Device, now time.Time) {
+	if d.PreemptAt.IsZero() || now.Before(d.PreemptAt) {
+		return
+	}
+	w := p.preemptions[d.ID]
+	slog.Info("Device preempted",
+		"device_id", d.ID,
+		"holder", d.ReservedBy,
+		"holder_priority", d.Priority,
+		"for", w.user,
+		"priority", w.priority,
+	)
+	p.freeLocked(d, EventPreempted)
+}
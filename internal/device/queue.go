@@ -0,0 +1,184 @@
+package device
+
+import (
+	"math"
+	"time"
+)
+
+// Request describes a reservation to make now or to wait for.
+type Request struct {
+	User     string
+	Type     string
+	Selector Selector
+	TTL      time.Duration
+	// Priority orders waiters, highest first; equal priorities are served in
+	// arrival order.
+	Priority int
+	// PreemptAfter, when positive, lets a waiter reclaim a device from a
+	// lower-priority holder, who keeps it for this grace period.
+	PreemptAfter time.Duration
+	// Scope limits the devices the request may be given, on top of Selector.
+	Scope Scope
+}
+
+// Waiter is a queued reservation that is granted the next device of its type
+// to become available, by priority and then in the order waiters joined the
+// queue. A waiter with a selector lets later waiters take devices it does not
+// match.
+type Waiter struct {
+	user         string
+	deviceType   string
+	selector     Selector
+	scope        Scope
+	ttl          time.Duration
+	priority     int
+	preemptAfter time.Duration
+	granted      chan Device
+	err          error
+}
+
+// Granted delivers a copy of the reserved device, or is closed without one if
+// the wait failed; Err then says why.
+func (w *Waiter) Granted() <-chan Device {
+	return w.granted
+}
+
+func (w *Waiter) Err() error {
+	return w.err
+}
+
+// Enqueue joins the wait queue for deviceType. If a device is already free
+// and nobody is ahead in the queue, the waiter is granted it immediately.
+func (p *DevicePool) Enqueue(user, deviceType string, ttl time.Duration) *Waiter {
+	return p.EnqueueMatching(Request{User: user, Type: deviceType, TTL: ttl})
+}
+
+// EnqueueMatching is Enqueue for a device that r.Selector matches, queued
+// ahead of every waiter with a lower priority.
+func (p *DevicePool) EnqueueMatching(r Request) *Waiter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w := &Waiter{
+		user:         r.User,
+		deviceType:   r.Type,
+		selector:     r.Selector,
+		scope:        r.Scope,
+		ttl:          r.TTL,
+		priority:     r.Priority,
+		preemptAfter: r.PreemptAfter,
+		granted:      make(chan Device, 1),
+	}
+	if p.dropped != nil {
+		w.fail(p.dropped)
+		return w
+	}
+	queue := p.queues[r.Type]
+	i := len(queue)
+	for i > 0 && queue[i-1].priority < w.priority {
+		i--
+	}
+	p.queues[r.Type] = append(queue[:i:i], append([]*Waiter{w}, queue[i:]...)...)
+	p.dispatchLocked(r.Type)
+	return w
+}
+
+// Position reports the 1-based place of w in its queue, or 0 once it has
+// been granted a device or cancelled.
+func (p *DevicePool) Position(w *Waiter) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for i, queued := range p.queues[w.deviceType] {
+		if queued == w {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (p *DevicePool) QueueLength(deviceType string) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.queues[deviceType])
+}
+
+// Cancel removes w from its queue. A device granted to w but never received
+// is released and handed to the next waiter.
+func (p *DevicePool) Cancel(w *Waiter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.removeWaiterLocked(w)
+	select {
+	case granted, ok := <-w.granted:
+		for _, d := range p.devices {
+			if ok && d.ID == granted.ID && IsReserved(d) && d.LeaseToken == granted.LeaseToken {
+				p.freeLocked(d, EventReleased)
+				break
+			}
+		}
+	default:
+	}
+}
+
+// drop fails every waiter with err, and every later one, once the fleet has
+// let go of the pool and nothing will dispatch from it again.
+func (p *DevicePool) drop(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.dropped = err
+	for _, queue := range p.queues {
+		for _, w := range queue {
+			p.removeWaiterLocked(w)
+			w.fail(err)
+		}
+	}
+}
+
+func (w *Waiter) fail(err error) {
+	w.err = err
+	close(w.granted)
+}
+
+// removeWaiterLocked takes w off its queue and calls off any preemption it
+// scheduled.
+func (p *DevicePool) removeWaiterLocked(w *Waiter) {
+	queue := p.queues[w.deviceType]
+	for i, queued := range queue {
+		if queued == w {
+			p.queues[w.deviceType] = append(queue[:i:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(p.queues[w.deviceType]) == 0 {
+		delete(p.queues, w.deviceType)
+	}
+	p.cancelPreemptionLocked(w)
+}
+
+// dispatchLocked hands available devices to waiters on the deviceType queue,
+// then schedules preemptions for waiters still without one.
+func (p *DevicePool) dispatchLocked(deviceType string) {
+	p.dispatchFromLocked(deviceType, math.MinInt)
+	p.schedulePreemptionsLocked(deviceType)
+}
+
+// dispatchFromLocked gives each available device to the first waiter with at
+// least minPriority that matches it and is within quota.
+func (p *DevicePool) dispatchFromLocked(deviceType string, minPriority int) {
+	for _, w := range append([]*Waiter(nil), p.queues[deviceType]...) {
+		if w.priority < minPriority || p.findAvailableLocked(deviceType, Selector{}, nil, 0) == nil {
+			return
+		}
+		d := p.findAvailableLocked(deviceType, w.selector, w.scope, w.ttl)
+		if d == nil || p.quotas.acquire(w.user, deviceType) != nil {
+			continue
+		}
+		p.removeWaiterLocked(w)
+		p.assignLocked(d, w.user, w.ttl, w.priority, newLeaseToken())
+		w.granted <- *d
+	}
+}
@@ -0,0 +1,306 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+)
+
+var ErrQuotaExceeded = errors.New("reservation quota exceeded")
+
+// Quotas cap how many devices may be reserved at once. Zero limits are
+// unlimited.
+type Quotas struct {
+	// PerUser caps each user's devices across all types; Users overrides it
+	// for named users.
+	PerUser int
+	Users   map[string]int
+	// PerType caps each user's devices of one type.
+	PerType map[string]int
+	// PerTeam caps the devices held by all members of each team together.
+	// Teams lists each team's members; users are also members of the teams
+	// their groups are named after (see Fleet.SetGroups).
+	PerTeam int
+	Teams   map[string][]string
+}
+
+// Quota scopes.
+const (
+	QuotaUser = "user"
+	QuotaTeam = "team"
+	QuotaType = "type"
+)
+
+// QuotaError names the quota a reservation would exceed.
+type QuotaError struct {
+	Scope string
+	// Subject is the user or team the quota applies to.
+	Subject    string
+	DeviceType string
+	InUse      int
+	Limit      int
+}
+
+func (e *QuotaError) Error() string {
+	switch e.Scope {
+	case QuotaTeam:
+		return fmt.Sprintf("team %q already holds %d of its %d devices", e.Subject, e.InUse, e.Limit)
+	case QuotaType:
+		return fmt.Sprintf("user %q already holds %d of their %d %s devices", e.Subject, e.InUse, e.Limit, e.DeviceType)
+	default:
+		return fmt.Sprintf("user %q already holds %d of their %d devices", e.Subject, e.InUse, e.Limit)
+	}
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// QuotaUsage is the number of devices held against one quota.
+type QuotaUsage struct {
+	Scope      string
+	Subject    string
+	DeviceType string
+	InUse      int
+	// Limit is zero when the quota is unlimited.
+	Limit int
+}
+
+// quotaTracker counts the devices each user holds. Pools update it under
+// their own lock, so it is shared by every pool in a fleet.
+type quotaTracker struct {
+	mu     sync.Mutex
+	limits Quotas
+	teams  map[string][]string // user -> teams
+	groups map[string][]string // user -> groups
+	users  map[string]int
+	types  map[string]map[string]int // user -> type -> count
+}
+
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{
+		groups: make(map[string][]string),
+		users:  make(map[string]int),
+		types:  make(map[string]map[string]int),
+	}
+}
+
+func (q *quotaTracker) setLimits(limits Quotas) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.limits = limits
+	q.teams = make(map[string][]string)
+	for team, members := range limits.Teams {
+		for _, user := range members {
+			q.teams[user] = append(q.teams[user], team)
+		}
+	}
+	for _, teams := range q.teams {
+		sort.Strings(teams)
+	}
+}
+
+func (q *quotaTracker) setGroups(user string, groups []string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(groups) == 0 {
+		delete(q.groups, user)
+		return
+	}
+	q.groups[user] = slices.Clone(groups)
+}
+
+// teamsLocked returns the teams user is listed in or has a group named
+// after.
+func (q *quotaTracker) teamsLocked(user string) []string {
+	teams := slices.Clone(q.teams[user])
+	for _, group := range q.groups[user] {
+		if _, ok := q.limits.Teams[group]; ok && !slices.Contains(teams, group) {
+			teams = append(teams, group)
+		}
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// check reports the first quota that reserving one device of each of types
+// for user would exceed.
+func (q *quotaTracker) check(user string, types ...string) error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.checkLocked(user, types)
+}
+
+// acquire is check followed by counting the devices against user's quotas.
+func (q *quotaTracker) acquire(user string, types ...string) error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.checkLocked(user, types); err != nil {
+		return err
+	}
+	for _, t := range types {
+		q.addLocked(user, t)
+	}
+	return nil
+}
+
+func (q *quotaTracker) checkLocked(user string, types []string) error {
+	if limit := q.userLimitLocked(user); limit > 0 && q.users[user]+len(types) > limit {
+		return &QuotaError{Scope: QuotaUser, Subject: user, InUse: q.users[user], Limit: limit}
+	}
+
+	wanted := make(map[string]int)
+	for _, t := range types {
+		wanted[t]++
+	}
+	for _, t := range slices.Sorted(maps.Keys(wanted)) {
+		held := q.types[user][t]
+		if limit := q.limits.PerType[t]; limit > 0 && held+wanted[t] > limit {
+			return &QuotaError{Scope: QuotaType, Subject: user, DeviceType: t, InUse: held, Limit: limit}
+		}
+	}
+
+	if limit := q.limits.PerTeam; limit > 0 {
+		for _, team := range q.teamsLocked(user) {
+			if held := q.teamUsageLocked(team); held+len(types) > limit {
+				return &QuotaError{Scope: QuotaTeam, Subject: team, InUse: held, Limit: limit}
+			}
+		}
+	}
+	return nil
+}
+
+func (q *quotaTracker) userLimitLocked(user string) int {
+	if limit, ok := q.limits.Users[user]; ok {
+		return limit
+	}
+	return q.limits.PerUser
+}
+
+func (q *quotaTracker) teamUsageLocked(team string) int {
+	members := make(map[string]bool)
+	for _, user := range q.limits.Teams[team] {
+		members[user] = true
+	}
+	for user, groups := range q.groups {
+		if slices.Contains(groups, team) {
+			members[user] = true
+		}
+	}
+
+	held := 0
+	for user := range members {
+		held += q.users[user]
+	}
+	return held
+}
+
+// add counts a device against user without checking limits, for
+// reservations restored from a store.
+func (q *quotaTracker) add(user, deviceType string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addLocked(user, deviceType)
+}
+
+func (q *quotaTracker) addLocked(user, deviceType string) {
+	q.users[user]++
+	if q.types[user] == nil {
+		q.types[user] = make(map[string]int)
+	}
+	q.types[user][deviceType]++
+}
+
+func (q *quotaTracker) release(user, deviceType string) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.users[user]--; q.users[user] <= 0 {
+		delete(q.users, user)
+	}
+	if q.types[user][deviceType]--; q.types[user][deviceType] <= 0 {
+		delete(q.types[user], deviceType)
+		if len(q.types[user]) == 0 {
+			delete(q.types, user)
+		}
+	}
+}
+
+// usage lists every quota user is subject to, or every user and team that
+// holds devices or has a quota when user is empty.
+func (q *quotaTracker) usage(user string) []QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	users := []string{user}
+	teams := q.teamsLocked(user)
+	if user == "" {
+		users = slices.Sorted(maps.Keys(q.users))
+		teams = slices.Sorted(maps.Keys(q.limits.Teams))
+	}
+
+	var result []QuotaUsage
+	for _, u := range users {
+		result = append(result, QuotaUsage{Scope: QuotaUser, Subject: u, InUse: q.users[u], Limit: q.userLimitLocked(u)})
+		types := make(map[string]bool)
+		for t := range q.types[u] {
+			types[t] = true
+		}
+		if user != "" {
+			for t := range q.limits.PerType {
+				types[t] = true
+			}
+		}
+		for _, t := range slices.Sorted(maps.Keys(types)) {
+			result = append(result, QuotaUsage{Scope: QuotaType, Subject: u, DeviceType: t, InUse: q.types[u][t], Limit: q.limits.PerType[t]})
+		}
+	}
+	for _, team := range teams {
+		result = append(result, QuotaUsage{Scope: QuotaTeam, Subject: team, InUse: q.teamUsageLocked(team), Limit: q.limits.PerTeam})
+	}
+	return result
+}
+
+// SetQuotas replaces the fleet's quotas. Devices already reserved stay
+// reserved even if they now exceed a quota.
+func (f *Fleet) SetQuotas(q Quotas) {
+	f.quotas.setLimits(q)
+}
+
+// SetGroups records the groups user was last authenticated with, making them
+// a member of the teams named after any of them.
+func (f *Fleet) SetGroups(user string, groups []string) {
+	f.quotas.setGroups(user, groups)
+}
+
+// CheckQuota reports whether user may reserve one more device of each of
+// types, returning a *QuotaError if not.
+func (f *Fleet) CheckQuota(user string, types ...string) error {
+	return f.quotas.check(user, types...)
+}
+
+// QuotaUsage lists the devices held against user's quotas, or against every
+// quota in use when user is empty.
+func (f *Fleet) QuotaUsage(user string) []QuotaUsage {
+	return f.quotas.usage(user)
+}
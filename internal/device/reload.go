@@ -0,0 +1,194 @@
+package device
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+)
+
+// FleetChanges lists the device IDs affected by Fleet.Apply.
+type FleetChanges struct {
+	Added    []string
+	Updated  []string
+	Retiring []string
+	Removed  []string
+}
+
+func (c FleetChanges) Empty() bool {
+	return len(c.Added)+len(c.Updated)+len(c.Retiring)+len(c.Removed) == 0
+}
+
+// Apply reconciles the fleet with a new device inventory. New devices become
+// available immediately; devices missing from the inventory are removed once
+// free, so current holders keep them until release or expiry. Devices added
+// with AddDevice are kept, unless the inventory now lists them.
+func (f *Fleet) Apply(devices []*Device) (FleetChanges, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var changes FleetChanges
+	wanted := make(map[string]*Device, len(devices))
+	for _, d := range devices {
+		wanted[d.ID] = d
+	}
+
+	for deviceType, p := range f.pools {
+		for _, d := range p.All() {
+			if w, ok := wanted[d.ID]; ok && w.Type != deviceType {
+				return changes, fmt.Errorf("device %q cannot change type from %s to %s; remove it first", d.ID, deviceType, w.Type)
+			}
+		}
+	}
+
+	for _, d := range devices {
+		f.forgetAddedLocked(d.ID)
+		p := f.poolForLocked(d.Type)
+		switch p.Add(d) {
+		case deviceAdded:
+			changes.Added = append(changes.Added, d.ID)
+		case deviceUpdated:
+			changes.Updated = append(changes.Updated, d.ID)
+		}
+	}
+
+	for _, p := range f.pools {
+		for _, d := range p.All() {
+			if _, ok := wanted[d.ID]; ok || f.added[d.ID] {
+				continue
+			}
+			wasRetiring := d.Retiring
+			if p.Retire(d.ID) {
+				changes.Removed = append(changes.Removed, d.ID)
+			} else if !wasRetiring {
+				changes.Retiring = append(changes.Retiring, d.ID)
+			}
+		}
+		if p.Len() == 0 {
+			f.dropPoolLocked(p)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Updated)
+	sort.Strings(changes.Retiring)
+	sort.Strings(changes.Removed)
+	return changes, nil
+}
+
+// poolForLocked returns the pool for deviceType, creating an empty one that
+// shares the fleet's store, events and policies if there is none.
+func (f *Fleet) poolForLocked(deviceType string) *DevicePool {
+	p, ok := f.pools[deviceType]
+	if !ok {
+		p = NewDevicePoolWithDevices(deviceType, nil)
+		p.store = f.store
+		p.events = f.events
+		p.quotas = f.quotas
+		p.health = f.health
+		f.pools[deviceType] = p
+	}
+	return p
+}
+
+// dropPoolLocked removes the empty pool p from the fleet, failing its
+// waiters since a pool created if the type returns will not serve them.
+func (f *Fleet) dropPoolLocked(p *DevicePool) {
+	delete(f.pools, p.Type())
+	p.drop(fmt.Errorf("%w %q: its last device was removed", ErrUnknownType, p.Type()))
+}
+
+type addResult int
+
+const (
+	deviceUnchanged addResult = iota
+	deviceAdded
+	deviceUpdated
+)
+
+// Add inserts d, or refreshes the labels and location of the device with the
+// same ID and cancels its retirement.
+func (p *DevicePool) Add(d *Device) addResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, existing := range p.devices {
+		if existing.ID != d.ID {
+			continue
+		}
+		if !existing.Retiring && existing.Location == d.Location && maps.Equal(existing.Labels, d.Labels) {
+			return deviceUnchanged
+		}
+		existing.Labels = d.Labels
+		existing.Location = d.Location
+		existing.Retiring = false
+		p.publishLocked(EventUpdated, existing)
+		p.dispatchLocked(p.deviceType)
+		return deviceUpdated
+	}
+
+	added := &Device{
+		ID:       d.ID,
+		Type:     d.Type,
+		Labels:   d.Labels,
+		Location: d.Location,
+		Draining: p.draining,
+	}
+	p.devices = append(p.devices, added)
+	p.publishLocked(EventAdded, added)
+	p.dispatchLocked(p.deviceType)
+	return deviceAdded
+}
+
+// Retire removes deviceID if it is free and reports true; a reserved device
+// is marked retiring and removed when its reservation ends.
+func (p *DevicePool) Retire(deviceID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID != deviceID {
+			continue
+		}
+		if IsReserved(d) {
+			if !d.Retiring {
+				d.Retiring = true
+				p.publishLocked(EventRetiring, d)
+			}
+			return false
+		}
+		if d.ReservedBy != "" {
+			// Expired but not yet swept; free it as the sweep would so the
+			// holder's quota is released.
+			d.Retiring = true
+			p.freeLocked(d, EventExpired)
+			return true
+		}
+		p.removeLocked(deviceID)
+		return true
+	}
+	return false
+}
+
+func (p *DevicePool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return len(p.devices)
+}
+
+func (p *DevicePool) removeLocked(deviceID string) {
+	for i, d := range p.devices {
+		if d.ID == deviceID {
+			p.devices = append(p.devices[:i:i], p.devices[i+1:]...)
+			for _, b := range append([]*Booking(nil), p.bookings...) {
+				if b.DeviceID == deviceID {
+					slog.Warn("Booking dropped with its device", "booking_id", b.ID, "device_id", deviceID, "user", b.User)
+					p.dropBookingLocked(b)
+				}
+			}
+			p.publishLocked(EventRemoved, d)
+			return
+		}
+	}
+}
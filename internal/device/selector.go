@@ -0,0 +1,212 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Selector chooses devices by label. The zero Selector matches every device.
+//
+// A selector expression is a comma-separated list of requirements, all of
+// which must hold:
+//
+//	os=17, os==17, os!=16     equality
+//	sim in (esim, physical)   set membership, also notin
+//	api>=33, api<34           numeric comparison (>, >=, <, <=)
+//	sim, !rooted              label present or absent
+//
+// != and notin also match devices without the label; numeric comparisons
+// never match a label that is not a number.
+type Selector struct {
+	expr         string
+	requirements []requirement
+}
+
+type operator string
+
+const (
+	opEquals       operator = "="
+	opNotEquals    operator = "!="
+	opIn           operator = "in"
+	opNotIn        operator = "notin"
+	opExists       operator = "exists"
+	opDoesNotExist operator = "!"
+	opGreater      operator = ">"
+	opGreaterEqual operator = ">="
+	opLess         operator = "<"
+	opLessEqual    operator = "<="
+)
+
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+	number float64
+}
+
+var (
+	setRequirement     = regexp.MustCompile(`^([A-Za-z0-9_./-]+)\s+(in|notin)\s*\((.*)\)$`)
+	compareRequirement = regexp.MustCompile(`^([A-Za-z0-9_./-]+)\s*(==|=|!=|>=|<=|>|<)\s*(.*)$`)
+	existsRequirement  = regexp.MustCompile(`^(!?)\s*([A-Za-z0-9_./-]+)$`)
+)
+
+// ParseSelector parses a selector expression. An empty expression matches
+// every device.
+func ParseSelector(expr string) (Selector, error) {
+	sel := Selector{expr: strings.TrimSpace(expr)}
+	if sel.expr == "" {
+		return sel, nil
+	}
+
+	var errs []error
+	for _, part := range splitRequirements(sel.expr) {
+		r, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sel.requirements = append(sel.requirements, r)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Selector{}, err
+	}
+	return sel, nil
+}
+
+// splitRequirements splits on commas outside parentheses.
+func splitRequirements(expr string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+func parseRequirement(text string) (requirement, error) {
+	if text == "" {
+		return requirement{}, errors.New("empty requirement")
+	}
+
+	if m := setRequirement.FindStringSubmatch(text); m != nil {
+		r := requirement{key: m[1], op: operator(m[2])}
+		for _, v := range strings.Split(m[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				r.values = append(r.values, v)
+			}
+		}
+		if len(r.values) == 0 {
+			return requirement{}, fmt.Errorf("%q: %s needs at least one value", text, r.op)
+		}
+		return r, nil
+	}
+
+	if m := compareRequirement.FindStringSubmatch(text); m != nil {
+		r := requirement{key: m[1], op: operator(m[2]), values: []string{strings.TrimSpace(m[3])}}
+		if r.op == "==" {
+			r.op = opEquals
+		}
+		if r.values[0] == "" || strings.ContainsAny(r.values[0], "=!<>(), ") {
+			return requirement{}, fmt.Errorf("%q: invalid value %q", text, r.values[0])
+		}
+		switch r.op {
+		case opGreater, opGreaterEqual, opLess, opLessEqual:
+			n, err := strconv.ParseFloat(r.values[0], 64)
+			if err != nil {
+				return requirement{}, fmt.Errorf("%q: %s needs a number, got %q", text, r.op, r.values[0])
+			}
+			r.number = n
+		}
+		return r, nil
+	}
+
+	if m := existsRequirement.FindStringSubmatch(text); m != nil {
+		if m[1] == "!" {
+			return requirement{key: m[2], op: opDoesNotExist}, nil
+		}
+		return requirement{key: m[2], op: opExists}, nil
+	}
+
+	return requirement{}, fmt.Errorf("%q: not a requirement", text)
+}
+
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+func (s Selector) String() string {
+	return s.expr
+}
+
+func (s Selector) Matches(d *Device) bool {
+	for _, r := range s.requirements {
+		if !r.matches(d.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Scope is the set of devices a caller may be given: those any of its
+// selectors match. A nil Scope allows every device; an empty one none.
+type Scope []Selector
+
+func (s Scope) Allows(d *Device) bool {
+	if s == nil {
+		return true
+	}
+	for _, sel := range s {
+		if sel.Matches(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r requirement) matches(labels map[string]string) bool {
+	value, ok := labels[r.key]
+	switch r.op {
+	case opExists:
+		return ok
+	case opDoesNotExist:
+		return !ok
+	case opEquals:
+		return ok && value == r.values[0]
+	case opNotEquals:
+		return !ok || value != r.values[0]
+	case opIn:
+		return ok && slices.Contains(r.values, value)
+	case opNotIn:
+		return !ok || !slices.Contains(r.values, value)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if !ok || err != nil {
+		return false
+	}
+	switch r.op {
+	case opGreater:
+		return n > r.number
+	case opGreaterEqual:
+		return n >= r.number
+	case opLess:
+		return n < r.number
+	case opLessEqual:
+		return n <= r.number
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package device
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Reservation is the persisted state of one device: its lease, and its
+// health and drain state, which outlive leases. A record with an empty
+// ReservedBy means the device was released.
+type Reservation struct {
+	DeviceID   string    `json:"device_id"`
+	ReservedBy string    `json:"reserved_by,omitempty"`
+	ReservedAt time.Time `json:"reserved_at,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty"`
+	LeaseToken string    `json:"lease_token,omitempty"`
+	Priority   int       `json:"priority,omitempty"`
+
+	Health       Health `json:"health,omitempty"`
+	HealthReason string `json:"health_reason,omitempty"`
+	Draining     bool   `json:"draining,omitempty"`
+}
+
+// Empty reports whether r is of a free, healthy device that is not
+// draining, which needs no record.
+func (r Reservation) Empty() bool {
+	return r.ReservedBy == "" && r.Health == Healthy && r.HealthReason == "" && !r.Draining
+}
+
+// Store persists reservation changes so they survive a server restart.
+type Store interface {
+	Save(r Reservation) error
+	Load() ([]Reservation, error)
+}
+
+func reservationOf(d *Device) Reservation {
+	return Reservation{
+		DeviceID:   d.ID,
+		ReservedBy: d.ReservedBy,
+		ReservedAt: d.ReservedAt,
+		ExpiresAt:  d.ExpiresAt,
+		LeaseToken: d.LeaseToken,
+		Priority:   d.Priority,
+
+		Health:       d.Health,
+		HealthReason: d.HealthReason,
+		Draining:     d.Draining,
+	}
+}
+
+func (p *DevicePool) SetStore(s Store) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.store = s
+}
+
+func (p *DevicePool) persistLocked(d *Device) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.Save(reservationOf(d)); err != nil {
+		slog.Error("Persisting reservation failed", "device_id", d.ID, "err", err)
+	}
+}
+
+// restore reapplies a persisted health and drain state, and the reservation
+// if it has not yet expired. It reports whether the reservation was restored.
+func (p *DevicePool) restore(r Reservation) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, d := range p.devices {
+		if d.ID == r.DeviceID {
+			d.Health = r.Health
+			d.HealthReason = r.HealthReason
+			d.Draining = r.Draining
+			if r.ReservedBy == "" || time.Now().After(r.ExpiresAt) {
+				return false
+			}
+			d.ReservedBy = r.ReservedBy
+			d.ReservedAt = r.ReservedAt
+			d.ExpiresAt = r.ExpiresAt
+			d.LeaseToken = r.LeaseToken
+			d.Priority = r.Priority
+			p.quotas.add(r.ReservedBy, d.Type)
+			return true
+		}
+	}
+	return false
+}
+
+// restoreDevices re-adds devices added with AddDevice before a restart. Any
+// the fleet already has have since been listed in the fleet file, so are
+// dropped from ds.
+func (f *Fleet) restoreDevices(ds DeviceStore) error {
+	devices, err := ds.LoadDevices()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, d := range devices {
+		if f.hasLocked(d.ID) {
+			if err := ds.DeleteDevice(d.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		f.poolForLocked(d.Type).Add(&Device{ID: d.ID, Type: d.Type, Labels: d.Labels, Location: d.Location})
+		f.added[d.ID] = true
+	}
+	return nil
+}
+
+func (p *DevicePool) restoreBooking(b Booking) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.addBookingLocked(&b)
+}
+
+// Restore loads reservations, bookings if s is a BookingStore and added
+// devices if s is a DeviceStore, from s into the fleet and persists every
+// later change to it. It returns the number of reservations restored.
+func (f *Fleet) Restore(s Store) (int, error) {
+	if ds, ok := s.(DeviceStore); ok {
+		if err := f.restoreDevices(ds); err != nil {
+			return 0, err
+		}
+	}
+
+	reservations, err := s.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, r := range reservations {
+		p, err := f.PoolFor(r.DeviceID)
+		if err != nil {
+			continue
+		}
+		if p.restore(r) {
+			restored++
+		}
+	}
+
+	if bs, ok := s.(BookingStore); ok {
+		bookings, err := bs.LoadBookings()
+		if err != nil {
+			return restored, err
+		}
+		for _, b := range bookings {
+			if p, err := f.PoolFor(b.DeviceID); err == nil {
+				p.restoreBooking(b)
+			}
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.store = s
+	for _, p := range f.pools {
+		p.SetStore(s)
+	}
+	return restored, nil
+}
@@ -0,0 +1,179 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+)
+
+// Permission allows one kind of call.
+type Permission string
+
+const (
+	View          Permission = "view"
+	Reserve       Permission = "reserve"
+	Release       Permission = "release"
+	Extend        Permission = "extend"
+	Book          Permission = "book"
+	ReportFailure Permission = "report_failure"
+	SetHealth     Permission = "set_health"
+	Drain         Permission = "drain"
+	ForceRelease  Permission = "force_release"
+	Transfer      Permission = "transfer"
+	ReleaseUser   Permission = "release_user"
+	ManageDevices Permission = "manage_devices"
+
+	// All grants every permission.
+	All Permission = "*"
+)
+
+// Permissions lists every permission a role may grant.
+var Permissions = []Permission{
+	View, Reserve, Release, Extend, Book, ReportFailure, SetHealth, Drain,
+	ForceRelease, Transfer, ReleaseUser, ManageDevices,
+}
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// DeniedError names the permission a caller was missing.
+type DeniedError struct {
+	User       string
+	Permission Permission
+	// On is what the permission was needed on, such as `device "iphone-1"`;
+	// empty when the call needs it at all.
+	On string
+}
+
+func (e *DeniedError) Error() string {
+	if e.On == "" {
+		return fmt.Sprintf("%s lacks permission %q", e.User, e.Permission)
+	}
+	return fmt.Sprintf("%s lacks permission %q on %s", e.User, e.Permission, e.On)
+}
+
+func (e *DeniedError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Role grants permissions on the devices of DeviceTypes (every type if
+// empty) that Selector matches.
+type Role struct {
+	Name        string
+	Permissions []Permission
+	DeviceTypes []string
+	Selector    device.Selector
+}
+
+func (r *Role) grants(perm Permission) bool {
+	return slices.Contains(r.Permissions, perm) || slices.Contains(r.Permissions, All)
+}
+
+func (r *Role) coversType(deviceType string) bool {
+	return len(r.DeviceTypes) == 0 || slices.Contains(r.DeviceTypes, deviceType)
+}
+
+// Policy maps identities to roles.
+type Policy struct {
+	roles map[string]*Role
+	// users and groups map a user or group to the roles bound to it.
+	users        map[string][]string
+	groups       map[string][]string
+	defaultRoles []string
+}
+
+// NewPolicy binds roles to users and groups. defaultRoles apply to every
+// authenticated user.
+func NewPolicy(roles []Role, users, groups map[string][]string, defaultRoles []string) (*Policy, error) {
+	p := &Policy{roles: make(map[string]*Role, len(roles)), users: users, groups: groups, defaultRoles: defaultRoles}
+	var errs []error
+	for _, r := range roles {
+		if _, ok := p.roles[r.Name]; ok {
+			errs = append(errs, fmt.Errorf("role %q defined twice", r.Name))
+			continue
+		}
+		for _, perm := range r.Permissions {
+			if perm != All && !slices.Contains(Permissions, perm) {
+				errs = append(errs, fmt.Errorf("role %q: unknown permission %q", r.Name, perm))
+			}
+		}
+		p.roles[r.Name] = &r
+	}
+
+	check := func(what string, bound []string) {
+		for _, name := range bound {
+			if _, ok := p.roles[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown role %q", what, name))
+			}
+		}
+	}
+	for _, user := range slices.Sorted(maps.Keys(users)) {
+		check(fmt.Sprintf("user %q", user), users[user])
+	}
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		check(fmt.Sprintf("group %q", group), groups[group])
+	}
+	check("default_roles", defaultRoles)
+	return p, errors.Join(errs...)
+}
+
+// Roles returns the roles id holds through its user, its groups and the
+// default roles.
+func (p *Policy) Roles(id auth.Identity) []*Role {
+	names := slices.Clone(p.defaultRoles)
+	names = append(names, p.users[id.User]...)
+	for _, g := range id.Groups {
+		names = append(names, p.groups[g]...)
+	}
+	slices.Sort(names)
+
+	var roles []*Role
+	for _, name := range slices.Compact(names) {
+		if r, ok := p.roles[name]; ok {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// Check reports whether id holds perm on any device.
+func (p *Policy) Check(id auth.Identity, perm Permission) error {
+	for _, r := range p.Roles(id) {
+		if r.grants(perm) {
+			return nil
+		}
+	}
+	return &DeniedError{User: id.User, Permission: perm}
+}
+
+// CheckDevice reports whether id holds perm on d.
+func (p *Policy) CheckDevice(id auth.Identity, perm Permission, d *device.Device) error {
+	for _, r := range p.Roles(id) {
+		if r.grants(perm) && r.coversType(d.Type) && r.Selector.Matches(d) {
+			return nil
+		}
+	}
+	return &DeniedError{User: id.User, Permission: perm, On: fmt.Sprintf("device %q", d.ID)}
+}
+
+// Scope returns the devices of deviceType id may use perm on, nil meaning
+// all of them. It fails when id may use perm on none.
+func (p *Policy) Scope(id auth.Identity, perm Permission, deviceType string) (device.Scope, error) {
+	scope := device.Scope{}
+	for _, r := range p.Roles(id) {
+		if !r.grants(perm) || !r.coversType(deviceType) {
+			continue
+		}
+		if r.Selector.Empty() {
+			return nil, nil
+		}
+		scope = append(scope, r.Selector)
+	}
+	if len(scope) == 0 {
+		return nil, &DeniedError{User: id.User, Permission: perm, On: deviceType + " devices"}
+	}
+	return scope, nil
+}
@@ -9,6 +9,8 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,10 +23,105 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED          ErrorReason = 0
+	ErrorReason_ERROR_REASON_INVALID_ARGUMENT     ErrorReason = 1
+	ErrorReason_ERROR_REASON_UNKNOWN_DEVICE_TYPE  ErrorReason = 2
+	ErrorReason_ERROR_REASON_UNKNOWN_DEVICE       ErrorReason = 3
+	ErrorReason_ERROR_REASON_NO_DEVICES_AVAILABLE ErrorReason = 4
+	ErrorReason_ERROR_REASON_NOT_RESERVED         ErrorReason = 5
+	ErrorReason_ERROR_REASON_LEASE_MISMATCH       ErrorReason = 6
+	ErrorReason_ERROR_REASON_MAX_LEASE_REACHED    ErrorReason = 7
+	ErrorReason_ERROR_REASON_QUOTA_EXCEEDED       ErrorReason = 8
+	ErrorReason_ERROR_REASON_BOOKING_CONFLICT     ErrorReason = 9
+	ErrorReason_ERROR_REASON_UNKNOWN_BOOKING      ErrorReason = 10
+	// The call needs credentials that were missing or not recognised.
+	ErrorReason_ERROR_REASON_UNAUTHENTICATED ErrorReason = 11
+	ErrorReason_ERROR_REASON_DEVICE_EXISTS   ErrorReason = 12
+	// The caller is authenticated but not allowed to make the call.
+	ErrorReason_ERROR_REASON_PERMISSION_DENIED ErrorReason = 13
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "ERROR_REASON_INVALID_ARGUMENT",
+		2:  "ERROR_REASON_UNKNOWN_DEVICE_TYPE",
+		3:  "ERROR_REASON_UNKNOWN_DEVICE",
+		4:  "ERROR_REASON_NO_DEVICES_AVAILABLE",
+		5:  "ERROR_REASON_NOT_RESERVED",
+		6:  "ERROR_REASON_LEASE_MISMATCH",
+		7:  "ERROR_REASON_MAX_LEASE_REACHED",
+		8:  "ERROR_REASON_QUOTA_EXCEEDED",
+		9:  "ERROR_REASON_BOOKING_CONFLICT",
+		10: "ERROR_REASON_UNKNOWN_BOOKING",
+		11: "ERROR_REASON_UNAUTHENTICATED",
+		12: "ERROR_REASON_DEVICE_EXISTS",
+		13: "ERROR_REASON_PERMISSION_DENIED",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED":          0,
+		"ERROR_REASON_INVALID_ARGUMENT":     1,
+		"ERROR_REASON_UNKNOWN_DEVICE_TYPE":  2,
+		"ERROR_REASON_UNKNOWN_DEVICE":       3,
+		"ERROR_REASON_NO_DEVICES_AVAILABLE": 4,
+		"ERROR_REASON_NOT_RESERVED":         5,
+		"ERROR_REASON_LEASE_MISMATCH":       6,
+		"ERROR_REASON_MAX_LEASE_REACHED":    7,
+		"ERROR_REASON_QUOTA_EXCEEDED":       8,
+		"ERROR_REASON_BOOKING_CONFLICT":     9,
+		"ERROR_REASON_UNKNOWN_BOOKING":      10,
+		"ERROR_REASON_UNAUTHENTICATED":      11,
+		"ERROR_REASON_DEVICE_EXISTS":        12,
+		"ERROR_REASON_PERMISSION_DENIED":    13,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_device_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_proto_device_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{0}
+}
+
 type ReserveRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	DeviceType    string                 `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ignored when the server authenticates callers; the reservation is made
+	// for the authenticated user instead.
+	User       string               `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	DeviceType string               `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Ttl        *durationpb.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Label selector such as "os=17, sim in (esim, physical), api>=33"; see
+	// README. A selector no device of the type can match is rejected.
+	Selector string `protobuf:"bytes,4,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Higher priorities are served first; equal priorities in arrival order.
+	Priority int32 `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	// ReserveAndWait only: reclaim a device from a lower-priority holder after
+	// the server's preemption grace period if none frees up first.
+	Preempt       bool `protobuf:"varint,6,opt,name=preempt,proto3" json:"preempt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,10 +170,40 @@ func (x *ReserveRequest) GetDeviceType() string {
 	return ""
 }
 
+func (x *ReserveRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *ReserveRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ReserveRequest) GetPreempt() bool {
+	if x != nil {
+		return x.Preempt
+	}
+	return false
+}
+
 type ReserveResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
 	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,3,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,16 +252,84 @@ func (x *ReserveResponse) GetStatus() string {
 	return ""
 }
 
+func (x *ReserveResponse) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ReserveResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type ReserveUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueuePosition int32                  `protobuf:"varint,1,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
+	Reservation   *ReserveResponse       `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveUpdate) Reset() {
+	*x = ReserveUpdate{}
+	mi := &file_proto_device_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveUpdate) ProtoMessage() {}
+
+func (x *ReserveUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_device_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveUpdate.ProtoReflect.Descriptor instead.
+func (*ReserveUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReserveUpdate) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *ReserveUpdate) GetReservation() *ReserveResponse {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
 type ReleaseRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReleaseRequest) Reset() {
 	*x = ReleaseRequest{}
-	mi := &file_proto_device_proto_msgTypes[2]
+	mi := &file_proto_device_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +341,7 @@ func (x *ReleaseRequest) String() string {
 func (*ReleaseRequest) ProtoMessage() {}
 
 func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_device_proto_msgTypes[2]
+	mi := &file_proto_device_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +354,7 @@ func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
 func (*ReleaseRequest) Descriptor() ([]byte, []int) {
-	return file_proto_device_proto_rawDescGZIP(), []int{2}
+	return file_proto_device_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ReleaseRequest) GetDeviceId() string {
@@ -169,6 +364,20 @@ func (x *ReleaseRequest) GetDeviceId() string {
 	return ""
 }
 
+func (x *ReleaseRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
 type ReleaseResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -178,7 +387,7 @@ type ReleaseResponse struct {
 
 func (x *ReleaseResponse) Reset() {
 	*x = ReleaseResponse{}
-	mi := &file_proto_device_proto_msgTypes[3]
+	mi := &file_proto_device_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -190,7 +399,7 @@ func (x *ReleaseResponse) String() string {
 func (*ReleaseResponse) ProtoMessage() {}
 
 func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_device_proto_msgTypes[3]
+	mi := &file_proto_device_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -203,7 +412,7 @@ func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
 func (*ReleaseResponse) Descriptor() ([]byte, []int) {
-	return file_proto_device_proto_rawDescGZIP(), []int{3}
+	return file_proto_device_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ReleaseResponse) GetStatus() string {
@@ -213,15 +422,145 @@ func (x *ReleaseResponse) GetStatus() string {
 	return ""
 }
 
-type WatchRequest struct {
+type ExtendRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Extension     *durationpb.Duration   `protobuf:"bytes,4,opt,name=extension,proto3" json:"extension,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendRequest) Reset() {
+	*x = ExtendRequest{}
+	mi := &file_proto_device_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendRequest) ProtoMessage() {}
+
+func (x *ExtendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_device_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendRequest.ProtoReflect.Descriptor instead.
+func (*ExtendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExtendRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetExtension() *durationpb.Duration {
+	if x != nil {
+		return x.Extension
+	}
+	return nil
+}
+
+type ExtendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendResponse) Reset() {
+	*x = ExtendResponse{}
+	mi := &file_proto_device_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendResponse) ProtoMessage() {}
+
+func (x *ExtendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_device_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendResponse.ProtoReflect.Descriptor instead.
+func (*ExtendResponse) Descriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExtendResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExtendResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after this revision; zero starts with a full snapshot.
+	SinceRevision uint64 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	// The epoch since_revision was sent with. Revisions restart with the
+	// server, so resuming from another epoch resyncs from a snapshot.
+	Epoch string `protobuf:"bytes,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// Filters; empty fields match every device and set fields must all match.
+	Types         []string          `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	DeviceIds     []string          `protobuf:"bytes,3,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	Labels        map[string]string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ReservedBy    string            `protobuf:"bytes,5,opt,name=reserved_by,json=reservedBy,proto3" json:"reserved_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WatchRequest) Reset() {
 	*x = WatchRequest{}
-	mi := &file_proto_device_proto_msgTypes[4]
+	mi := &file_proto_device_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -233,7 +572,7 @@ func (x *WatchRequest) String() string {
 func (*WatchRequest) ProtoMessage() {}
 
 func (x *WatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_device_proto_msgTypes[4]
+	mi := &file_proto_device_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,21 +585,82 @@ func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
 func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return file_proto_device_proto_rawDescGZIP(), []int{4}
+	return file_proto_device_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetSinceRevision() uint64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetDeviceIds() []string {
+	if x != nil {
+		return x.DeviceIds
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetReservedBy() string {
+	if x != nil {
+		return x.ReservedBy
+	}
+	return ""
 }
 
 type DeviceStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
-	ReservedBy    string                 `protobuf:"bytes,2,opt,name=reserved_by,json=reservedBy,proto3" json:"reserved_by,omitempty"`
-	Available     bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId   string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	ReservedBy string                 `protobuf:"bytes,2,opt,name=reserved_by,json=reservedBy,proto3" json:"reserved_by,omitempty"`
+	Available  bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Retiring   bool                   `protobuf:"varint,4,opt,name=retiring,proto3" json:"retiring,omitempty"`
+	// snapshot, reserved, released, expired, extended, added, updated, retiring,
+	// removed, preempting, preempted, preemption_cancelled, health_changed,
+	// draining, drained, undrained, force_released or transferred.
+	// "resync" carries no device and means the watcher must discard its state
+	// because the snapshot that follows replaces it.
+	Event    string `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	Revision uint64 `protobuf:"varint,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	// When the holder loses the device to a higher-priority waiter; unset
+	// unless a preemption is scheduled.
+	PreemptAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=preempt_at,json=preemptAt,proto3" json:"preempt_at,omitempty"`
+	// healthy, maintenance, offline or quarantined; only healthy devices are
+	// handed out.
+	Health       string `protobuf:"bytes,8,opt,name=health,proto3" json:"health,omitempty"`
+	HealthReason string `protobuf:"bytes,9,opt,name=health_reason,json=healthReason,proto3" json:"health_reason,omitempty"`
+	// Not handed out; drained once reserved_by is empty.
+	Draining bool `protobuf:"varint,10,opt,name=draining,proto3" json:"draining,omitempty"`
+	// Identifies the server run that numbered revision.
+	Epoch         string `protobuf:"bytes,11,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeviceStatus) Reset() {
 	*x = DeviceStatus{}
-	mi := &file_proto_device_proto_msgTypes[5]
+	mi := &file_proto_device_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -272,7 +672,7 @@ func (x *DeviceStatus) String() string {
 func (*DeviceStatus) ProtoMessage() {}
 
 func (x *DeviceStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_device_proto_msgTypes[5]
+	mi := &file_proto_device_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -285,7 +685,7 @@ func (x *DeviceStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeviceStatus.ProtoReflect.Descriptor instead.
 func (*DeviceStatus) Descriptor() ([]byte, []int) {
-	return file_proto_device_proto_rawDescGZIP(), []int{5}
+	return file_proto_device_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeviceStatus) GetDeviceId() string {
@@ -309,31 +709,364 @@ func (x *DeviceStatus) GetAvailable() bool {
 	return false
 }
 
+func (x *DeviceStatus) GetRetiring() bool {
+	if x != nil {
+		return x.Retiring
+	}
+	return false
+}
+
+func (x *DeviceStatus) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *DeviceStatus) GetPreemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreemptAt
+	}
+	return nil
+}
+
+func (x *DeviceStatus) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetHealthReason() string {
+	if x != nil {
+		return x.HealthReason
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *DeviceStatus) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+// Quota is a reservation quota and how much of it is in use.
+type Quota struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "user", "team" or "type" (a user's devices of one type).
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	// The user or team the quota applies to.
+	Subject    string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	DeviceType string `protobuf:"bytes,3,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	InUse      int32  `protobuf:"varint,4,opt,name=in_use,json=inUse,proto3" json:"in_use,omitempty"`
+	// Zero when unlimited.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_proto_device_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_device_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Quota) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Quota) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Quota) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Quota) GetInUse() int32 {
+	if x != nil {
+		return x.InUse
+	}
+	return 0
+}
+
+func (x *Quota) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ErrorDetail is attached to every error returned by DeviceService.
+type ErrorDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        ErrorReason            `protobuf:"varint,1,opt,name=reason,proto3,enum=devicefleet.v1.ErrorReason" json:"reason,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	DeviceId      string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	CurrentHolder string                 `protobuf:"bytes,4,opt,name=current_holder,json=currentHolder,proto3" json:"current_holder,omitempty"`
+	QueueLength   int32                  `protobuf:"varint,5,opt,name=queue_length,json=queueLength,proto3" json:"queue_length,omitempty"`
+	Field         string                 `protobuf:"bytes,6,opt,name=field,proto3" json:"field,omitempty"`
+	// The quota a QUOTA_EXCEEDED request would have exceeded.
+	Quota *Quota `protobuf:"bytes,7,opt,name=quota,proto3" json:"quota,omitempty"`
+	// The booking a BOOKING_CONFLICT request overlaps or an UNKNOWN_BOOKING
+	// request named; empty when the conflict is the current reservation.
+	BookingId string `protobuf:"bytes,8,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	// The permission a PERMISSION_DENIED caller lacks.
+	Permission    string `protobuf:"bytes,9,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	mi := &file_proto_device_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_device_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_proto_device_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ErrorDetail) GetReason() ErrorReason {
+	if x != nil {
+		return x.Reason
+	}
+	return ErrorReason_ERROR_REASON_UNSPECIFIED
+}
+
+func (x *ErrorDetail) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetCurrentHolder() string {
+	if x != nil {
+		return x.CurrentHolder
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetQueueLength() int32 {
+	if x != nil {
+		return x.QueueLength
+	}
+	return 0
+}
+
+func (x *ErrorDetail) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetQuota() *Quota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+func (x *ErrorDetail) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetPermission() string {
+	if x != nil {
+		return x.Permission
+	}
+	return ""
+}
+
 var File_proto_device_proto protoreflect.FileDescriptor
 
 const file_proto_device_proto_rawDesc = "" +
 	"\n" +
-	"\x12proto/device.proto\x12\x0edevicefleet.v1\"E\n" +
+	"\x12proto/device.proto\x12\x0edevicefleet.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc4\x01\n" +
 	"\x0eReserveRequest\x12\x12\n" +
 	"\x04user\x18\x01 \x01(\tR\x04user\x12\x1f\n" +
 	"\vdevice_type\x18\x02 \x01(\tR\n" +
-	"deviceType\"F\n" +
+	"deviceType\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1a\n" +
+	"\bselector\x18\x04 \x01(\tR\bselector\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x18\n" +
+	"\apreempt\x18\x06 \x01(\bR\apreempt\"\xa2\x01\n" +
 	"\x0fReserveResponse\x12\x1b\n" +
 	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\"-\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1f\n" +
+	"\vlease_token\x18\x03 \x01(\tR\n" +
+	"leaseToken\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"y\n" +
+	"\rReserveUpdate\x12%\n" +
+	"\x0equeue_position\x18\x01 \x01(\x05R\rqueuePosition\x12A\n" +
+	"\vreservation\x18\x02 \x01(\v2\x1f.devicefleet.v1.ReserveResponseR\vreservation\"b\n" +
 	"\x0eReleaseRequest\x12\x1b\n" +
-	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\")\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\")\n" +
 	"\x0fReleaseResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\x0e\n" +
-	"\fWatchRequest\"j\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x9a\x01\n" +
+	"\rExtendRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x127\n" +
+	"\textension\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\textension\"c\n" +
+	"\x0eExtendResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x9e\x02\n" +
+	"\fWatchRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x04R\rsinceRevision\x12\x14\n" +
+	"\x05epoch\x18\x06 \x01(\tR\x05epoch\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12\x1d\n" +
+	"\n" +
+	"device_ids\x18\x03 \x03(\tR\tdeviceIds\x12@\n" +
+	"\x06labels\x18\x04 \x03(\v2(.devicefleet.v1.WatchRequest.LabelsEntryR\x06labels\x12\x1f\n" +
+	"\vreserved_by\x18\x05 \x01(\tR\n" +
+	"reservedBy\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe2\x02\n" +
 	"\fDeviceStatus\x12\x1b\n" +
 	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
 	"\vreserved_by\x18\x02 \x01(\tR\n" +
 	"reservedBy\x12\x1c\n" +
-	"\tavailable\x18\x03 \x01(\bR\tavailable2\x81\x02\n" +
+	"\tavailable\x18\x03 \x01(\bR\tavailable\x12\x1a\n" +
+	"\bretiring\x18\x04 \x01(\bR\bretiring\x12\x14\n" +
+	"\x05event\x18\x05 \x01(\tR\x05event\x12\x1a\n" +
+	"\brevision\x18\x06 \x01(\x04R\brevision\x129\n" +
+	"\n" +
+	"preempt_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tpreemptAt\x12\x16\n" +
+	"\x06health\x18\b \x01(\tR\x06health\x12#\n" +
+	"\rhealth_reason\x18\t \x01(\tR\fhealthReason\x12\x1a\n" +
+	"\bdraining\x18\n" +
+	" \x01(\bR\bdraining\x12\x14\n" +
+	"\x05epoch\x18\v \x01(\tR\x05epoch\"\x85\x01\n" +
+	"\x05Quota\x12\x14\n" +
+	"\x05scope\x18\x01 \x01(\tR\x05scope\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1f\n" +
+	"\vdevice_type\x18\x03 \x01(\tR\n" +
+	"deviceType\x12\x15\n" +
+	"\x06in_use\x18\x04 \x01(\x05R\x05inUse\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xcc\x02\n" +
+	"\vErrorDetail\x123\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x1b.devicefleet.v1.ErrorReasonR\x06reason\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12%\n" +
+	"\x0ecurrent_holder\x18\x04 \x01(\tR\rcurrentHolder\x12!\n" +
+	"\fqueue_length\x18\x05 \x01(\x05R\vqueueLength\x12\x14\n" +
+	"\x05field\x18\x06 \x01(\tR\x05field\x12+\n" +
+	"\x05quota\x18\a \x01(\v2\x15.devicefleet.v1.QuotaR\x05quota\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\b \x01(\tR\tbookingId\x12\x1e\n" +
+	"\n" +
+	"permission\x18\t \x01(\tR\n" +
+	"permission*\xec\x03\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dERROR_REASON_INVALID_ARGUMENT\x10\x01\x12$\n" +
+	" ERROR_REASON_UNKNOWN_DEVICE_TYPE\x10\x02\x12\x1f\n" +
+	"\x1bERROR_REASON_UNKNOWN_DEVICE\x10\x03\x12%\n" +
+	"!ERROR_REASON_NO_DEVICES_AVAILABLE\x10\x04\x12\x1d\n" +
+	"\x19ERROR_REASON_NOT_RESERVED\x10\x05\x12\x1f\n" +
+	"\x1bERROR_REASON_LEASE_MISMATCH\x10\x06\x12\"\n" +
+	"\x1eERROR_REASON_MAX_LEASE_REACHED\x10\a\x12\x1f\n" +
+	"\x1bERROR_REASON_QUOTA_EXCEEDED\x10\b\x12!\n" +
+	"\x1dERROR_REASON_BOOKING_CONFLICT\x10\t\x12 \n" +
+	"\x1cERROR_REASON_UNKNOWN_BOOKING\x10\n" +
+	"\x12 \n" +
+	"\x1cERROR_REASON_UNAUTHENTICATED\x10\v\x12\x1e\n" +
+	"\x1aERROR_REASON_DEVICE_EXISTS\x10\f\x12\"\n" +
+	"\x1eERROR_REASON_PERMISSION_DENIED\x10\r2\xa8\x03\n" +
 	"\rDeviceService\x12P\n" +
-	"\rReserveDevice\x12\x1e.devicefleet.v1.ReserveRequest\x1a\x1f.devicefleet.v1.ReserveResponse\x12P\n" +
-	"\rReleaseDevice\x12\x1e.devicefleet.v1.ReleaseRequest\x1a\x1f.devicefleet.v1.ReleaseResponse\x12L\n" +
+	"\rReserveDevice\x12\x1e.devicefleet.v1.ReserveRequest\x1a\x1f.devicefleet.v1.ReserveResponse\x12Q\n" +
+	"\x0eReserveAndWait\x12\x1e.devicefleet.v1.ReserveRequest\x1a\x1d.devicefleet.v1.ReserveUpdate0\x01\x12P\n" +
+	"\rReleaseDevice\x12\x1e.devicefleet.v1.ReleaseRequest\x1a\x1f.devicefleet.v1.ReleaseResponse\x12R\n" +
+	"\x11ExtendReservation\x12\x1d.devicefleet.v1.ExtendRequest\x1a\x1e.devicefleet.v1.ExtendResponse\x12L\n" +
 	"\fWatchDevices\x12\x1c.devicefleet.v1.WatchRequest\x1a\x1c.devicefleet.v1.DeviceStatus0\x01B=Z;github.com/gitRasheed/FleetRPC/internal/service/proto;protob\x06proto3"
 
 var (
@@ -348,27 +1081,50 @@ func file_proto_device_proto_rawDescGZIP() []byte {
 	return file_proto_device_proto_rawDescData
 }
 
-var file_proto_device_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_device_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_device_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_proto_device_proto_goTypes = []any{
-	(*ReserveRequest)(nil),  // 0: devicefleet.v1.ReserveRequest
-	(*ReserveResponse)(nil), // 1: devicefleet.v1.ReserveResponse
-	(*ReleaseRequest)(nil),  // 2: devicefleet.v1.ReleaseRequest
-	(*ReleaseResponse)(nil), // 3: devicefleet.v1.ReleaseResponse
-	(*WatchRequest)(nil),    // 4: devicefleet.v1.WatchRequest
-	(*DeviceStatus)(nil),    // 5: devicefleet.v1.DeviceStatus
+	(ErrorReason)(0),              // 0: devicefleet.v1.ErrorReason
+	(*ReserveRequest)(nil),        // 1: devicefleet.v1.ReserveRequest
+	(*ReserveResponse)(nil),       // 2: devicefleet.v1.ReserveResponse
+	(*ReserveUpdate)(nil),         // 3: devicefleet.v1.ReserveUpdate
+	(*ReleaseRequest)(nil),        // 4: devicefleet.v1.ReleaseRequest
+	(*ReleaseResponse)(nil),       // 5: devicefleet.v1.ReleaseResponse
+	(*ExtendRequest)(nil),         // 6: devicefleet.v1.ExtendRequest
+	(*ExtendResponse)(nil),        // 7: devicefleet.v1.ExtendResponse
+	(*WatchRequest)(nil),          // 8: devicefleet.v1.WatchRequest
+	(*DeviceStatus)(nil),          // 9: devicefleet.v1.DeviceStatus
+	(*Quota)(nil),                 // 10: devicefleet.v1.Quota
+	(*ErrorDetail)(nil),           // 11: devicefleet.v1.ErrorDetail
+	nil,                           // 12: devicefleet.v1.WatchRequest.LabelsEntry
+	(*durationpb.Duration)(nil),   // 13: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
 }
 var file_proto_device_proto_depIdxs = []int32{
-	0, // 0: devicefleet.v1.DeviceService.ReserveDevice:input_type -> devicefleet.v1.ReserveRequest
-	2, // 1: devicefleet.v1.DeviceService.ReleaseDevice:input_type -> devicefleet.v1.ReleaseRequest
-	4, // 2: devicefleet.v1.DeviceService.WatchDevices:input_type -> devicefleet.v1.WatchRequest
-	1, // 3: devicefleet.v1.DeviceService.ReserveDevice:output_type -> devicefleet.v1.ReserveResponse
-	3, // 4: devicefleet.v1.DeviceService.ReleaseDevice:output_type -> devicefleet.v1.ReleaseResponse
-	5, // 5: devicefleet.v1.DeviceService.WatchDevices:output_type -> devicefleet.v1.DeviceStatus
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	13, // 0: devicefleet.v1.ReserveRequest.ttl:type_name -> google.protobuf.Duration
+	14, // 1: devicefleet.v1.ReserveResponse.expires_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: devicefleet.v1.ReserveUpdate.reservation:type_name -> devicefleet.v1.ReserveResponse
+	13, // 3: devicefleet.v1.ExtendRequest.extension:type_name -> google.protobuf.Duration
+	14, // 4: devicefleet.v1.ExtendResponse.expires_at:type_name -> google.protobuf.Timestamp
+	12, // 5: devicefleet.v1.WatchRequest.labels:type_name -> devicefleet.v1.WatchRequest.LabelsEntry
+	14, // 6: devicefleet.v1.DeviceStatus.preempt_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: devicefleet.v1.ErrorDetail.reason:type_name -> devicefleet.v1.ErrorReason
+	10, // 8: devicefleet.v1.ErrorDetail.quota:type_name -> devicefleet.v1.Quota
+	1,  // 9: devicefleet.v1.DeviceService.ReserveDevice:input_type -> devicefleet.v1.ReserveRequest
+	1,  // 10: devicefleet.v1.DeviceService.ReserveAndWait:input_type -> devicefleet.v1.ReserveRequest
+	4,  // 11: devicefleet.v1.DeviceService.ReleaseDevice:input_type -> devicefleet.v1.ReleaseRequest
+	6,  // 12: devicefleet.v1.DeviceService.ExtendReservation:input_type -> devicefleet.v1.ExtendRequest
+	8,  // 13: devicefleet.v1.DeviceService.WatchDevices:input_type -> devicefleet.v1.WatchRequest
+	2,  // 14: devicefleet.v1.DeviceService.ReserveDevice:output_type -> devicefleet.v1.ReserveResponse
+	3,  // 15: devicefleet.v1.DeviceService.ReserveAndWait:output_type -> devicefleet.v1.ReserveUpdate
+	5,  // 16: devicefleet.v1.DeviceService.ReleaseDevice:output_type -> devicefleet.v1.ReleaseResponse
+	7,  // 17: devicefleet.v1.DeviceService.ExtendReservation:output_type -> devicefleet.v1.ExtendResponse
+	9,  // 18: devicefleet.v1.DeviceService.WatchDevices:output_type -> devicefleet.v1.DeviceStatus
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_device_proto_init() }
@@ -381,13 +1137,14 @@ func file_proto_device_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_device_proto_rawDesc), len(file_proto_device_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_device_proto_goTypes,
 		DependencyIndexes: file_proto_device_proto_depIdxs,
+		EnumInfos:         file_proto_device_proto_enumTypes,
 		MessageInfos:      file_proto_device_proto_msgTypes,
 	}.Build()
 	File_proto_device_proto = out.File
@@ -0,0 +1,392 @@
+package protoconnect
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	connect "connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/audit"
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+// AdminServiceServer serves devicefleet.v2.AdminService from the same fleet
+// as a v1 DeviceServiceServer. Every call, allowed or not, is audited.
+type AdminServiceServer struct {
+	v1    *DeviceServiceServer
+	audit audit.Log
+
+	mu sync.RWMutex
+	// admins maps each admin's name to their bearer token.
+	admins map[string]string
+	// Users authn authenticates into one of groups are also admins, as are
+	// users policy grants the call's permission.
+	authn  auth.Authenticator
+	groups []string
+	policy *rbac.Policy
+}
+
+// NewAdminServiceServer serves the admins in admins, a map of names to
+// bearer tokens. log may be nil, in which case records only go to the
+// server log.
+func NewAdminServiceServer(v1 *DeviceServiceServer, admins map[string]string, log audit.Log) *AdminServiceServer {
+	return &AdminServiceServer{v1: v1, audit: log, admins: admins}
+}
+
+// SetAuth lets users authn authenticates as members of any of groups call
+// AdminService with their own tokens. A nil authn turns this off.
+func (s *AdminServiceServer) SetAuth(authn auth.Authenticator, groups []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authn, s.groups = authn, groups
+}
+
+// SetPolicy lets users authenticated by the authenticator passed to SetAuth
+// make the calls policy grants them, on the devices it grants them on.
+func (s *AdminServiceServer) SetPolicy(policy *rbac.Policy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policy = policy
+}
+
+// SetAdmins replaces the admin tokens.
+func (s *AdminServiceServer) SetAdmins(admins map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.admins = admins
+}
+
+func (s *AdminServiceServer) ForceRelease(ctx context.Context, req *connect.Request[protov2.ForceReleaseRequest]) (*connect.Response[protov2.ForceReleaseResponse], error) {
+	rec := audit.Record{Action: "force_release", DeviceID: req.Msg.DeviceId, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.ForceRelease, s.target(rec.DeviceID)); err != nil {
+		return nil, err
+	}
+	if rec.DeviceID == "" {
+		return nil, s.fail(rec, invalidArgument("device_id", "device_id is required"))
+	}
+
+	dev, err := s.v1.fleet.ForceRelease(rec.DeviceID)
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+	}
+	rec.Previous = dev.ReservedBy
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.ForceReleaseResponse{
+		Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RELEASED),
+	}), nil
+}
+
+func (s *AdminServiceServer) TransferReservation(ctx context.Context, req *connect.Request[protov2.TransferReservationRequest]) (*connect.Response[protov2.TransferReservationResponse], error) {
+	rec := audit.Record{Action: "transfer", DeviceID: req.Msg.DeviceId, Target: req.Msg.ToUser, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.Transfer, s.target(rec.DeviceID)); err != nil {
+		return nil, err
+	}
+	if rec.DeviceID == "" {
+		return nil, s.fail(rec, invalidArgument("device_id", "device_id is required"))
+	}
+	if rec.Target == "" {
+		return nil, s.fail(rec, invalidArgument("to_user", "to_user is required"))
+	}
+
+	dev, previous, err := s.v1.fleet.Transfer(rec.DeviceID, rec.Target)
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+	}
+	rec.Previous = previous
+	s.record(rec)
+	return connect.NewResponse(&protov2.TransferReservationResponse{
+		Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RESERVED),
+	}), nil
+}
+
+func (s *AdminServiceServer) ReleaseUserReservations(ctx context.Context, req *connect.Request[protov2.ReleaseUserReservationsRequest]) (*connect.Response[protov2.ReleaseUserReservationsResponse], error) {
+	rec := audit.Record{Action: "release_user", User: req.Msg.User, Reason: req.Msg.Reason}
+	// Only the devices checked here are released, so any the user reserves
+	// meanwhile are not released unchecked.
+	held := s.heldBy(rec.User)
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.ReleaseUser, held...); err != nil {
+		return nil, err
+	}
+	if rec.User == "" {
+		return nil, s.fail(rec, invalidArgument("user", "user is required"))
+	}
+
+	deviceIDs := make([]string, len(held))
+	for i, dev := range held {
+		deviceIDs[i] = dev.ID
+	}
+	resp := &protov2.ReleaseUserReservationsResponse{}
+	for _, dev := range s.v1.fleet.ReleaseUser(rec.User, deviceIDs) {
+		resp.Reservations = append(resp.Reservations, reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RELEASED))
+		s.record(audit.Record{Action: "force_release", Actor: rec.Actor, DeviceID: dev.ID, Previous: dev.ReservedBy, Reason: rec.Reason})
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(resp), nil
+}
+
+func (s *AdminServiceServer) AddDevice(ctx context.Context, req *connect.Request[protov2.AddDeviceRequest]) (*connect.Response[protov2.AddDeviceResponse], error) {
+	d := req.Msg.Device
+	rec := audit.Record{Action: "add_device", DeviceID: d.GetId(), Reason: req.Msg.Reason}
+	target := &device.Device{ID: d.GetId(), Type: d.GetType(), Labels: d.GetLabels()}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.ManageDevices, target); err != nil {
+		return nil, err
+	}
+	if rec.DeviceID == "" {
+		return nil, s.fail(rec, invalidArgument("device.id", "device.id is required"))
+	}
+	if d.Type == "" {
+		return nil, s.fail(rec, invalidArgument("device.type", "device.type is required"))
+	}
+
+	dev, err := s.v1.fleet.AddDevice(&device.Device{
+		ID:       d.Id,
+		Type:     d.Type,
+		Labels:   d.Labels,
+		Location: d.Location,
+	})
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.AddDeviceResponse{Device: deviceV2(dev, device.EventAdded)}), nil
+}
+
+func (s *AdminServiceServer) RemoveDevice(ctx context.Context, req *connect.Request[protov2.RemoveDeviceRequest]) (*connect.Response[protov2.RemoveDeviceResponse], error) {
+	rec := audit.Record{Action: "remove_device", DeviceID: req.Msg.DeviceId, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.ManageDevices, s.target(rec.DeviceID)); err != nil {
+		return nil, err
+	}
+	if rec.DeviceID == "" {
+		return nil, s.fail(rec, invalidArgument("device_id", "device_id is required"))
+	}
+
+	dev, removed, err := s.v1.fleet.RemoveDevice(rec.DeviceID)
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+	}
+	kind := device.EventRemoved
+	if !removed {
+		kind = device.EventRetiring
+		rec.Previous = dev.ReservedBy
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.RemoveDeviceResponse{Device: deviceV2(dev, kind), Removed: removed}), nil
+}
+
+func (s *AdminServiceServer) SetDeviceHealth(ctx context.Context, req *connect.Request[protov2.SetDeviceHealthRequest]) (*connect.Response[protov2.SetDeviceHealthResponse], error) {
+	rec := audit.Record{Action: "set_health", DeviceID: req.Msg.DeviceId, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.SetHealth, s.target(rec.DeviceID)); err != nil {
+		return nil, err
+	}
+	if rec.DeviceID == "" {
+		return nil, s.fail(rec, invalidArgument("device_id", "device_id is required"))
+	}
+	health, ok := healthFromV2(req.Msg.Health)
+	if !ok {
+		return nil, s.fail(rec, invalidArgument("health", "health must be set"))
+	}
+
+	dev, err := s.v1.fleet.SetHealth(rec.DeviceID, health, rec.Reason)
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.SetDeviceHealthResponse{Device: deviceV2(dev, "")}), nil
+}
+
+func (s *AdminServiceServer) DrainDevices(ctx context.Context, req *connect.Request[protov2.DrainRequest]) (*connect.Response[protov2.DrainResponse], error) {
+	rec := audit.Record{Action: "drain", DeviceID: req.Msg.DeviceId, DeviceType: req.Msg.DeviceType, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.Drain, s.drainTargets(rec.DeviceID, rec.DeviceType)...); err != nil {
+		return nil, err
+	}
+	if err := drainTarget(rec.DeviceID, rec.DeviceType); err != nil {
+		return nil, s.fail(rec, err)
+	}
+
+	var devices []device.Device
+	var err error
+	if rec.DeviceID != "" {
+		var dev device.Device
+		dev, err = s.v1.fleet.Drain(rec.DeviceID)
+		devices = []device.Device{dev}
+	} else {
+		devices, err = s.v1.fleet.DrainType(rec.DeviceType)
+	}
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID, DeviceType: rec.DeviceType}))
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.DrainResponse{Devices: devicesV2(devices), Drained: allDrained(devices)}), nil
+}
+
+func (s *AdminServiceServer) UndrainDevices(ctx context.Context, req *connect.Request[protov2.UndrainRequest]) (*connect.Response[protov2.UndrainResponse], error) {
+	rec := audit.Record{Action: "undrain", DeviceID: req.Msg.DeviceId, DeviceType: req.Msg.DeviceType, Reason: req.Msg.Reason}
+	if err := s.authorize(ctx, req.Header(), &rec, rbac.Drain, s.drainTargets(rec.DeviceID, rec.DeviceType)...); err != nil {
+		return nil, err
+	}
+	if err := drainTarget(rec.DeviceID, rec.DeviceType); err != nil {
+		return nil, s.fail(rec, err)
+	}
+
+	var devices []device.Device
+	var err error
+	if rec.DeviceID != "" {
+		var dev device.Device
+		dev, err = s.v1.fleet.Undrain(rec.DeviceID)
+		devices = []device.Device{dev}
+	} else {
+		devices, err = s.v1.fleet.UndrainType(rec.DeviceType)
+	}
+	if err != nil {
+		return nil, s.fail(rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID, DeviceType: rec.DeviceType}))
+	}
+	s.record(rec)
+	s.refreshAvailableMetrics()
+	return connect.NewResponse(&protov2.UndrainResponse{Devices: devicesV2(devices)}), nil
+}
+
+// authorize sets rec.Actor to the admin whose bearer token is in header:
+// an admin token, or a user token for a member of an admin group or a user
+// the policy grants perm on every target (any device if there are none). It
+// also requires a reason, since every admin call is audited with one.
+func (s *AdminServiceServer) authorize(ctx context.Context, header http.Header, rec *audit.Record, perm rbac.Permission, targets ...*device.Device) error {
+	if token, ok := bearerToken(header); ok {
+		s.mu.RLock()
+		for name, adminToken := range s.admins {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+				rec.Actor = name
+			}
+		}
+		authn, groups, policy := s.authn, s.groups, s.policy
+		s.mu.RUnlock()
+
+		if rec.Actor == "" && authn != nil && (len(groups) > 0 || policy != nil) {
+			if id, err := authn.Authenticate(ctx, token); err == nil {
+				rec.Actor = id.User
+				if !slices.ContainsFunc(groups, id.InGroup) {
+					if err := adminPermission(policy, id, perm, targets); err != nil {
+						return s.fail(*rec, poolError(err, &proto.ErrorDetail{DeviceId: rec.DeviceID}))
+					}
+				}
+			}
+		}
+	}
+	if rec.Actor == "" {
+		return s.fail(*rec, unauthenticated(errors.New("admin bearer token required")))
+	}
+	if rec.Reason == "" {
+		return s.fail(*rec, invalidArgument("reason", "reason is required"))
+	}
+	return nil
+}
+
+func adminPermission(policy *rbac.Policy, id auth.Identity, perm rbac.Permission, targets []*device.Device) error {
+	if policy == nil {
+		return &rbac.DeniedError{User: id.User, Permission: perm}
+	}
+	targets = slices.DeleteFunc(targets, func(d *device.Device) bool { return d == nil })
+	if len(targets) == 0 {
+		return policy.Check(id, perm)
+	}
+	for _, target := range targets {
+		if err := policy.CheckDevice(id, perm, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// target returns deviceID for a permission check, or nil if there is no
+// such device.
+func (s *AdminServiceServer) target(deviceID string) *device.Device {
+	pool, err := s.v1.fleet.PoolFor(deviceID)
+	if err != nil {
+		return nil
+	}
+	dev, ok := pool.Get(deviceID)
+	if !ok {
+		return nil
+	}
+	return &dev
+}
+
+// heldBy returns the devices user holds, for a permission check.
+func (s *AdminServiceServer) heldBy(user string) []*device.Device {
+	var held []*device.Device
+	for _, dev := range s.v1.fleet.Snapshot() {
+		if device.IsReserved(&dev) && dev.ReservedBy == user {
+			held = append(held, &dev)
+		}
+	}
+	return held
+}
+
+// drainTargets returns deviceID, or every device of deviceType, for a
+// permission check.
+func (s *AdminServiceServer) drainTargets(deviceID, deviceType string) []*device.Device {
+	if deviceID != "" {
+		return []*device.Device{s.target(deviceID)}
+	}
+	pool, err := s.v1.fleet.Pool(deviceType)
+	if err != nil {
+		return nil
+	}
+	var targets []*device.Device
+	for _, dev := range pool.Snapshot() {
+		targets = append(targets, &dev)
+	}
+	return targets
+}
+
+func (s *AdminServiceServer) fail(rec audit.Record, err *connect.Error) *connect.Error {
+	rec.Error = err.Message()
+	s.record(rec)
+	return err
+}
+
+func (s *AdminServiceServer) record(rec audit.Record) {
+	rec.Time = time.Now()
+	slog.Info("Audit",
+		"actor", rec.Actor,
+		"action", rec.Action,
+		"device_id", rec.DeviceID,
+		"user", rec.User,
+		"previous", rec.Previous,
+		"target", rec.Target,
+		"type", rec.DeviceType,
+		"reason", rec.Reason,
+		"error", rec.Error,
+	)
+	if s.audit == nil {
+		return
+	}
+	if err := s.audit.Write(rec); err != nil {
+		slog.Error("Writing audit record failed", "action", rec.Action, "err", err)
+	}
+}
+
+func (s *AdminServiceServer) refreshAvailableMetrics() {
+	currentlyAvailable.Reset()
+	for _, pool := range s.v1.fleet.Pools() {
+		updateAvailableMetric(pool)
+	}
+}
@@ -0,0 +1,128 @@
+package protoconnect
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	connect "connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+// AuthInterceptor authenticates the bearer token on every call it handles
+// and puts the caller's identity in the handler's context. Calls without a
+// token it accepts fail with Unauthenticated. With a policy, calls also need
+// the RPC's permission, and handlers limit devices to the caller's roles.
+type AuthInterceptor struct {
+	mu     sync.RWMutex
+	authn  auth.Authenticator
+	policy *rbac.Policy
+}
+
+func NewAuthInterceptor(authn auth.Authenticator) *AuthInterceptor {
+	return &AuthInterceptor{authn: authn}
+}
+
+// SetAuthenticator replaces the authenticator for subsequent calls.
+func (i *AuthInterceptor) SetAuthenticator(authn auth.Authenticator) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.authn = authn
+}
+
+// SetPolicy replaces the access policy; nil lets every authenticated caller
+// make every call.
+func (i *AuthInterceptor) SetPolicy(policy *rbac.Policy) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.policy = policy
+}
+
+func (i *AuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx, err := i.authenticate(ctx, req.Spec().Procedure, req.Peer().Addr, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+func (i *AuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *AuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.authenticate(ctx, conn.Spec().Procedure, conn.Peer().Addr, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+func (i *AuthInterceptor) authenticate(ctx context.Context, procedure, peer string, header http.Header) (context.Context, error) {
+	token, ok := bearerToken(header)
+	if !ok {
+		return ctx, unauthenticated(auth.ErrNoToken)
+	}
+	i.mu.RLock()
+	authn, policy := i.authn, i.policy
+	i.mu.RUnlock()
+
+	id, err := authn.Authenticate(ctx, token)
+	if err != nil {
+		slog.Info("Authentication failed", "procedure", procedure, "peer", peer, "err", err)
+		return ctx, unauthenticated(err)
+	}
+	ctx = auth.WithIdentity(ctx, id)
+	if policy == nil {
+		return ctx, nil
+	}
+
+	perm, ok := procedurePermissions[procedure]
+	if !ok {
+		return ctx, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s is not access controlled", procedure))
+	}
+	if perm != "" {
+		if err := policy.Check(id, perm); err != nil {
+			slog.Info("Permission denied", "procedure", procedure, "user", id.User, "permission", perm)
+			return ctx, poolError(err, &proto.ErrorDetail{})
+		}
+	}
+	return withPolicy(ctx, policy), nil
+}
+
+func bearerToken(header http.Header) (string, bool) {
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	return token, ok && token != ""
+}
+
+func unauthenticated(err error) *connect.Error {
+	return withDetail(connect.NewError(connect.CodeUnauthenticated, err), &proto.ErrorDetail{
+		Reason: proto.ErrorReason_ERROR_REASON_UNAUTHENTICATED,
+	})
+}
+
+// callerUser returns the authenticated user when there is one, so callers
+// cannot act as anyone else, and requested otherwise. It passes the
+// authenticated user's groups on to the fleet for team quotas.
+func (s *DeviceServiceServer) callerUser(ctx context.Context, requested string) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		s.fleet.SetGroups(id.User, id.Groups)
+		return id.User
+	}
+	return requested
+}
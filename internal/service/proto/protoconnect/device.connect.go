@@ -36,9 +36,15 @@ const (
 	// DeviceServiceReserveDeviceProcedure is the fully-qualified name of the DeviceService's
 	// ReserveDevice RPC.
 	DeviceServiceReserveDeviceProcedure = "/devicefleet.v1.DeviceService/ReserveDevice"
+	// DeviceServiceReserveAndWaitProcedure is the fully-qualified name of the DeviceService's
+	// ReserveAndWait RPC.
+	DeviceServiceReserveAndWaitProcedure = "/devicefleet.v1.DeviceService/ReserveAndWait"
 	// DeviceServiceReleaseDeviceProcedure is the fully-qualified name of the DeviceService's
 	// ReleaseDevice RPC.
 	DeviceServiceReleaseDeviceProcedure = "/devicefleet.v1.DeviceService/ReleaseDevice"
+	// DeviceServiceExtendReservationProcedure is the fully-qualified name of the DeviceService's
+	// ExtendReservation RPC.
+	DeviceServiceExtendReservationProcedure = "/devicefleet.v1.DeviceService/ExtendReservation"
 	// DeviceServiceWatchDevicesProcedure is the fully-qualified name of the DeviceService's
 	// WatchDevices RPC.
 	DeviceServiceWatchDevicesProcedure = "/devicefleet.v1.DeviceService/WatchDevices"
@@ -47,7 +53,9 @@ const (
 // DeviceServiceClient is a client for the devicefleet.v1.DeviceService service.
 type DeviceServiceClient interface {
 	ReserveDevice(context.Context, *connect.Request[proto.ReserveRequest]) (*connect.Response[proto.ReserveResponse], error)
+	ReserveAndWait(context.Context, *connect.Request[proto.ReserveRequest]) (*connect.ServerStreamForClient[proto.ReserveUpdate], error)
 	ReleaseDevice(context.Context, *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error)
+	ExtendReservation(context.Context, *connect.Request[proto.ExtendRequest]) (*connect.Response[proto.ExtendResponse], error)
 	WatchDevices(context.Context, *connect.Request[proto.WatchRequest]) (*connect.ServerStreamForClient[proto.DeviceStatus], error)
 }
 
@@ -68,12 +76,24 @@ func NewDeviceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 			connect.WithSchema(deviceServiceMethods.ByName("ReserveDevice")),
 			connect.WithClientOptions(opts...),
 		),
+		reserveAndWait: connect.NewClient[proto.ReserveRequest, proto.ReserveUpdate](
+			httpClient,
+			baseURL+DeviceServiceReserveAndWaitProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReserveAndWait")),
+			connect.WithClientOptions(opts...),
+		),
 		releaseDevice: connect.NewClient[proto.ReleaseRequest, proto.ReleaseResponse](
 			httpClient,
 			baseURL+DeviceServiceReleaseDeviceProcedure,
 			connect.WithSchema(deviceServiceMethods.ByName("ReleaseDevice")),
 			connect.WithClientOptions(opts...),
 		),
+		extendReservation: connect.NewClient[proto.ExtendRequest, proto.ExtendResponse](
+			httpClient,
+			baseURL+DeviceServiceExtendReservationProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ExtendReservation")),
+			connect.WithClientOptions(opts...),
+		),
 		watchDevices: connect.NewClient[proto.WatchRequest, proto.DeviceStatus](
 			httpClient,
 			baseURL+DeviceServiceWatchDevicesProcedure,
@@ -85,9 +105,11 @@ func NewDeviceServiceClient(httpClient connect.HTTPClient, baseURL string, opts
 
 // deviceServiceClient implements DeviceServiceClient.
 type deviceServiceClient struct {
-	reserveDevice *connect.Client[proto.ReserveRequest, proto.ReserveResponse]
-	releaseDevice *connect.Client[proto.ReleaseRequest, proto.ReleaseResponse]
-	watchDevices  *connect.Client[proto.WatchRequest, proto.DeviceStatus]
+	reserveDevice     *connect.Client[proto.ReserveRequest, proto.ReserveResponse]
+	reserveAndWait    *connect.Client[proto.ReserveRequest, proto.ReserveUpdate]
+	releaseDevice     *connect.Client[proto.ReleaseRequest, proto.ReleaseResponse]
+	extendReservation *connect.Client[proto.ExtendRequest, proto.ExtendResponse]
+	watchDevices      *connect.Client[proto.WatchRequest, proto.DeviceStatus]
 }
 
 // ReserveDevice calls devicefleet.v1.DeviceService.ReserveDevice.
@@ -95,11 +117,21 @@ func (c *deviceServiceClient) ReserveDevice(ctx context.Context, req *connect.Re
 	return c.reserveDevice.CallUnary(ctx, req)
 }
 
+// ReserveAndWait calls devicefleet.v1.DeviceService.ReserveAndWait.
+func (c *deviceServiceClient) ReserveAndWait(ctx context.Context, req *connect.Request[proto.ReserveRequest]) (*connect.ServerStreamForClient[proto.ReserveUpdate], error) {
+	return c.reserveAndWait.CallServerStream(ctx, req)
+}
+
 // ReleaseDevice calls devicefleet.v1.DeviceService.ReleaseDevice.
 func (c *deviceServiceClient) ReleaseDevice(ctx context.Context, req *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error) {
 	return c.releaseDevice.CallUnary(ctx, req)
 }
 
+// ExtendReservation calls devicefleet.v1.DeviceService.ExtendReservation.
+func (c *deviceServiceClient) ExtendReservation(ctx context.Context, req *connect.Request[proto.ExtendRequest]) (*connect.Response[proto.ExtendResponse], error) {
+	return c.extendReservation.CallUnary(ctx, req)
+}
+
 // WatchDevices calls devicefleet.v1.DeviceService.WatchDevices.
 func (c *deviceServiceClient) WatchDevices(ctx context.Context, req *connect.Request[proto.WatchRequest]) (*connect.ServerStreamForClient[proto.DeviceStatus], error) {
 	return c.watchDevices.CallServerStream(ctx, req)
@@ -108,7 +140,9 @@ func (c *deviceServiceClient) WatchDevices(ctx context.Context, req *connect.Req
 // DeviceServiceHandler is an implementation of the devicefleet.v1.DeviceService service.
 type DeviceServiceHandler interface {
 	ReserveDevice(context.Context, *connect.Request[proto.ReserveRequest]) (*connect.Response[proto.ReserveResponse], error)
+	ReserveAndWait(context.Context, *connect.Request[proto.ReserveRequest], *connect.ServerStream[proto.ReserveUpdate]) error
 	ReleaseDevice(context.Context, *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error)
+	ExtendReservation(context.Context, *connect.Request[proto.ExtendRequest]) (*connect.Response[proto.ExtendResponse], error)
 	WatchDevices(context.Context, *connect.Request[proto.WatchRequest], *connect.ServerStream[proto.DeviceStatus]) error
 }
 
@@ -125,12 +159,24 @@ func NewDeviceServiceHandler(svc DeviceServiceHandler, opts ...connect.HandlerOp
 		connect.WithSchema(deviceServiceMethods.ByName("ReserveDevice")),
 		connect.WithHandlerOptions(opts...),
 	)
+	deviceServiceReserveAndWaitHandler := connect.NewServerStreamHandler(
+		DeviceServiceReserveAndWaitProcedure,
+		svc.ReserveAndWait,
+		connect.WithSchema(deviceServiceMethods.ByName("ReserveAndWait")),
+		connect.WithHandlerOptions(opts...),
+	)
 	deviceServiceReleaseDeviceHandler := connect.NewUnaryHandler(
 		DeviceServiceReleaseDeviceProcedure,
 		svc.ReleaseDevice,
 		connect.WithSchema(deviceServiceMethods.ByName("ReleaseDevice")),
 		connect.WithHandlerOptions(opts...),
 	)
+	deviceServiceExtendReservationHandler := connect.NewUnaryHandler(
+		DeviceServiceExtendReservationProcedure,
+		svc.ExtendReservation,
+		connect.WithSchema(deviceServiceMethods.ByName("ExtendReservation")),
+		connect.WithHandlerOptions(opts...),
+	)
 	deviceServiceWatchDevicesHandler := connect.NewServerStreamHandler(
 		DeviceServiceWatchDevicesProcedure,
 		svc.WatchDevices,
@@ -141,8 +187,12 @@ func NewDeviceServiceHandler(svc DeviceServiceHandler, opts ...connect.HandlerOp
 		switch r.URL.Path {
 		case DeviceServiceReserveDeviceProcedure:
 			deviceServiceReserveDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceReserveAndWaitProcedure:
+			deviceServiceReserveAndWaitHandler.ServeHTTP(w, r)
 		case DeviceServiceReleaseDeviceProcedure:
 			deviceServiceReleaseDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceExtendReservationProcedure:
+			deviceServiceExtendReservationHandler.ServeHTTP(w, r)
 		case DeviceServiceWatchDevicesProcedure:
 			deviceServiceWatchDevicesHandler.ServeHTTP(w, r)
 		default:
@@ -158,10 +208,18 @@ func (UnimplementedDeviceServiceHandler) ReserveDevice(context.Context, *connect
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v1.DeviceService.ReserveDevice is not implemented"))
 }
 
+func (UnimplementedDeviceServiceHandler) ReserveAndWait(context.Context, *connect.Request[proto.ReserveRequest], *connect.ServerStream[proto.ReserveUpdate]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v1.DeviceService.ReserveAndWait is not implemented"))
+}
+
 func (UnimplementedDeviceServiceHandler) ReleaseDevice(context.Context, *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v1.DeviceService.ReleaseDevice is not implemented"))
 }
 
+func (UnimplementedDeviceServiceHandler) ExtendReservation(context.Context, *connect.Request[proto.ExtendRequest]) (*connect.Response[proto.ExtendResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v1.DeviceService.ExtendReservation is not implemented"))
+}
+
 func (UnimplementedDeviceServiceHandler) WatchDevices(context.Context, *connect.Request[proto.WatchRequest], *connect.ServerStream[proto.DeviceStatus]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v1.DeviceService.WatchDevices is not implemented"))
 }
@@ -2,42 +2,190 @@ package protoconnect
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	connect "connectrpc.com/connect"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
 	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
 )
 
+const (
+	defaultDeviceType = "iphone"
+	watchBuffer       = 256
+
+	eventResync device.EventKind = "resync"
+)
+
 var (
 	totalReservations = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "devicefleet_reservations_total",
 		Help: "Total number of reservation attempts",
 	}, []string{"status"})
 
-	currentlyAvailable = promauto.NewGauge(prometheus.GaugeOpts{
+	currentlyAvailable = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "devicefleet_devices_available",
 		Help: "Current number of available devices",
-	})
+	}, []string{"type"})
+
+	preemptions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "devicefleet_preemptions_total",
+		Help: "Preemptions by device type and outcome (scheduled, completed or cancelled)",
+	}, []string{"type", "outcome"})
+
+	quotaInUse = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "devicefleet_quota_in_use",
+		Help: "Devices held against each reservation quota",
+	}, []string{"scope", "subject", "type"})
+
+	quotaLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "devicefleet_quota_limit",
+		Help: "Limit of each reservation quota in use, zero when unlimited",
+	}, []string{"scope", "subject", "type"})
+
+	devicesByHealth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "devicefleet_devices_by_health",
+		Help: "Devices in each health state",
+	}, []string{"type", "health"})
+
+	healthChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "devicefleet_health_changes_total",
+		Help: "Device health changes by device type and new state",
+	}, []string{"type", "health"})
 )
 
 type DeviceServiceServer struct {
-	pool *device.DevicePool
+	fleet *device.Fleet
+
+	mu     sync.RWMutex
+	leases device.LeasePolicy
 }
 
-func NewDeviceServiceServer() *DeviceServiceServer {
-	pool := device.NewDevicePool("iphone", 10)
-	go pool.CleanupExpired()
-	updateAvailableMetric(pool)
-	return &DeviceServiceServer{pool: pool}
+func NewDeviceServiceServer(fleet *device.Fleet, leases device.LeasePolicy) *DeviceServiceServer {
+	go fleet.CleanupExpired()
+	go trackEventMetrics(fleet)
+	for _, pool := range fleet.Pools() {
+		updateAvailableMetric(pool)
+	}
+	return &DeviceServiceServer{fleet: fleet, leases: leases}
 }
 
 func NewDeviceServiceServerWithPool(pool *device.DevicePool) *DeviceServiceServer {
-	return &DeviceServiceServer{pool: pool}
+	return NewDeviceServiceServerWithFleet(device.NewFleet(pool))
+}
+
+func NewDeviceServiceServerWithFleet(fleet *device.Fleet) *DeviceServiceServer {
+	return &DeviceServiceServer{fleet: fleet, leases: device.DefaultLeasePolicy()}
+}
+
+// SetLeasePolicy replaces the lease policy. Current reservations keep the
+// lease they were given.
+func (s *DeviceServiceServer) SetLeasePolicy(policy device.LeasePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.leases = policy
+}
+
+func (s *DeviceServiceServer) leasePolicy() device.LeasePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.leases
+}
+
+// ApplyFleet reconciles the live fleet with a reloaded device inventory
+// without disturbing current reservations.
+func (s *DeviceServiceServer) ApplyFleet(devices []*device.Device) (device.FleetChanges, error) {
+	changes, err := s.fleet.Apply(devices)
+	if err != nil {
+		slog.Error("Fleet reload rejected", "err", err)
+		return changes, err
+	}
+
+	currentlyAvailable.Reset()
+	for _, pool := range s.fleet.Pools() {
+		updateAvailableMetric(pool)
+	}
+	slog.Info("Fleet reloaded",
+		"added", changes.Added,
+		"updated", changes.Updated,
+		"retiring", changes.Retiring,
+		"removed", changes.Removed,
+	)
+	return changes, nil
+}
+
+// SetQuotas replaces the reservation quotas without disturbing current
+// reservations.
+func (s *DeviceServiceServer) SetQuotas(q device.Quotas) {
+	s.fleet.SetQuotas(q)
+	updateQuotaMetrics(s.fleet)
+	slog.Info("Quotas updated", "per_user", q.PerUser, "per_team", q.PerTeam, "per_type", q.PerType)
+}
+
+// SetQuarantineAfter changes how many reported failures quarantine a device.
+func (s *DeviceServiceServer) SetQuarantineAfter(failures int) {
+	s.fleet.SetQuarantineAfter(failures)
+	slog.Info("Quarantine threshold updated", "quarantine_after", failures)
+}
+
+// trackEventMetrics refreshes the quota and health gauges as the fleet
+// changes and counts preemptions and health changes, including those no
+// handler sees.
+func trackEventMetrics(fleet *device.Fleet) {
+	for {
+		sub := fleet.Events().Subscribe(watchBuffer)
+		updateQuotaMetrics(fleet)
+		updateHealthMetrics(fleet)
+		for event := range sub.C {
+			updateQuotaMetrics(fleet)
+			if outcome, ok := preemptionOutcomes[event.Kind]; ok {
+				preemptions.WithLabelValues(event.Device.Type, outcome).Inc()
+			}
+			switch event.Kind {
+			case device.EventHealthChanged:
+				healthChanges.WithLabelValues(event.Device.Type, event.Device.Health.String()).Inc()
+				if pool, err := fleet.Pool(event.Device.Type); err == nil {
+					updateAvailableMetric(pool)
+				}
+				updateHealthMetrics(fleet)
+			case device.EventAdded, device.EventRemoved:
+				updateHealthMetrics(fleet)
+			}
+		}
+	}
+}
+
+func updateHealthMetrics(fleet *device.Fleet) {
+	devicesByHealth.Reset()
+	for _, d := range fleet.Snapshot() {
+		devicesByHealth.WithLabelValues(d.Type, d.Health.String()).Inc()
+	}
+}
+
+var preemptionOutcomes = map[device.EventKind]string{
+	device.EventPreempting:          "scheduled",
+	device.EventPreempted:           "completed",
+	device.EventPreemptionCancelled: "cancelled",
+}
+
+func updateQuotaMetrics(fleet *device.Fleet) {
+	quotaInUse.Reset()
+	quotaLimit.Reset()
+	for _, u := range fleet.QuotaUsage("") {
+		quotaInUse.WithLabelValues(u.Scope, u.Subject, u.DeviceType).Set(float64(u.InUse))
+		quotaLimit.WithLabelValues(u.Scope, u.Subject, u.DeviceType).Set(float64(u.Limit))
+	}
 }
 
 func updateAvailableMetric(pool *device.DevicePool) {
@@ -47,67 +195,426 @@ func updateAvailableMetric(pool *device.DevicePool) {
 			count++
 		}
 	}
-	currentlyAvailable.Set(float64(count))
+	currentlyAvailable.WithLabelValues(pool.Type()).Set(float64(count))
 }
 
 func (s *DeviceServiceServer) ReserveDevice(ctx context.Context, req *connect.Request[proto.ReserveRequest]) (*connect.Response[proto.ReserveResponse], error) {
-	deviceType := req.Msg.DeviceType
+	dev, err := s.reserve(ctx, reserveParams{
+		user:       s.callerUser(ctx, req.Msg.User),
+		deviceType: req.Msg.DeviceType,
+		selector:   req.Msg.Selector,
+		ttl:        req.Msg.Ttl.AsDuration(),
+		priority:   req.Msg.Priority,
+		preempt:    req.Msg.Preempt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(reserveResponse(dev)), nil
+}
+
+// reserveParams are the ReserveRequest fields shared by every API version.
+type reserveParams struct {
+	user       string
+	deviceType string
+	selector   string
+	ttl        time.Duration
+	priority   int32
+	preempt    bool
+}
+
+// request validates params and resolves them to the pool to reserve from and
+// a device.Request with the TTL clamped to the lease policy and the devices
+// limited to those the caller may reserve.
+func (s *DeviceServiceServer) request(ctx context.Context, params reserveParams) (*device.DevicePool, device.Request, error) {
+	deviceType := params.deviceType
 	if deviceType == "" {
-		deviceType = "iphone"
+		deviceType = defaultDeviceType
+	}
+	if params.user == "" {
+		return nil, device.Request{}, invalidArgument("user", "user is required")
+	}
+	leases := s.leasePolicy()
+	if params.preempt && leases.PreemptGrace <= 0 {
+		return nil, device.Request{}, invalidArgument("preempt", "preemption is disabled on this server")
 	}
 
-	dev, ok := s.pool.Reserve(req.Msg.User, deviceType, 2*time.Minute)
-	if !ok {
+	pool, err := s.fleet.Pool(deviceType)
+	if err != nil {
+		totalReservations.WithLabelValues("failure").Inc()
+		return nil, device.Request{}, poolError(err, &proto.ErrorDetail{DeviceType: deviceType})
+	}
+	sel, err := matchableSelector(pool, deviceType, params.selector)
+	if err != nil {
+		totalReservations.WithLabelValues("failure").Inc()
+		return nil, device.Request{}, err
+	}
+	scope, err := reserveScope(ctx, pool, params.user, deviceType, sel)
+	if err != nil {
 		totalReservations.WithLabelValues("failure").Inc()
-		slog.Info("ReserveDevice failed", "user", req.Msg.User, "type", deviceType, "reason", "no devices available")
-		return connect.NewResponse(&proto.ReserveResponse{
-			Status: "no devices available",
-		}), nil
+		return nil, device.Request{}, poolError(err, &proto.ErrorDetail{DeviceType: deviceType})
+	}
+
+	r := device.Request{
+		User:     params.user,
+		Type:     deviceType,
+		Selector: sel,
+		TTL:      leases.ClampTTL(params.ttl),
+		Priority: int(params.priority),
+		Scope:    scope,
+	}
+	if params.preempt {
+		r.PreemptAfter = leases.PreemptGrace
+	}
+	return pool, r, nil
+}
+
+func (s *DeviceServiceServer) reserve(ctx context.Context, params reserveParams) (device.Device, error) {
+	if params.preempt {
+		return device.Device{}, invalidArgument("preempt", "preempt requires ReserveAndWait")
+	}
+	pool, r, err := s.request(ctx, params)
+	if err != nil {
+		return device.Device{}, err
+	}
+
+	dev, err := pool.ReserveMatching(r)
+	if err != nil {
+		totalReservations.WithLabelValues("failure").Inc()
+		slog.Info("ReserveDevice failed", "user", r.User, "type", r.Type, "selector", r.Selector.String(), "priority", r.Priority, "reason", err)
+		return device.Device{}, poolError(err, &proto.ErrorDetail{
+			DeviceType:  r.Type,
+			QueueLength: int32(pool.QueueLength(r.Type)),
+		})
 	}
 
 	totalReservations.WithLabelValues("success").Inc()
-	updateAvailableMetric(s.pool)
-	slog.Info("ReserveDevice success", "user", req.Msg.User, "type", deviceType, "device_id", dev.ID)
-	return connect.NewResponse(&proto.ReserveResponse{
-		DeviceId: dev.ID,
-		Status:   "reserved",
-	}), nil
+	updateAvailableMetric(pool)
+	slog.Info("ReserveDevice success", "user", r.User, "type", r.Type, "device_id", dev.ID, "ttl", r.TTL, "priority", r.Priority)
+	return dev, nil
 }
 
-func (s *DeviceServiceServer) ReleaseDevice(ctx context.Context, req *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error) {
-	success := s.pool.Release(req.Msg.DeviceId)
-	status := "released"
-	if !success {
-		status = "not found or already available"
+// reserveScope returns the devices of deviceType the caller may reserve. It
+// fails when none of them match sel.
+func reserveScope(ctx context.Context, pool *device.DevicePool, user, deviceType string, sel device.Selector) (device.Scope, error) {
+	scope, err := scopeFor(ctx, rbac.Reserve, deviceType)
+	if err != nil {
+		return nil, err
+	}
+	if scope != nil && !pool.CanMatch(deviceType, sel, scope) {
+		return nil, &rbac.DeniedError{User: user, Permission: rbac.Reserve, On: fmt.Sprintf("%s devices matching %q", deviceType, sel)}
 	}
-	updateAvailableMetric(s.pool)
-	slog.Info("ReleaseDevice", "device_id", req.Msg.DeviceId, "status", status)
-	return connect.NewResponse(&proto.ReleaseResponse{Status: status}), nil
+	return scope, nil
 }
 
-func (s *DeviceServiceServer) WatchDevices(ctx context.Context, req *connect.Request[proto.WatchRequest], stream *connect.ServerStream[proto.DeviceStatus]) error {
+// matchableSelector parses selector and rejects it when no device of
+// deviceType could ever satisfy it, so callers do not wait forever.
+func matchableSelector(pool *device.DevicePool, deviceType, selector string) (device.Selector, error) {
+	sel, err := device.ParseSelector(selector)
+	if err != nil {
+		return sel, invalidArgument("selector", "invalid selector: "+err.Error())
+	}
+	if !pool.CanMatch(deviceType, sel, nil) {
+		return sel, withDetail(
+			connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("no %s device matches selector %q", deviceType, sel)),
+			&proto.ErrorDetail{Reason: proto.ErrorReason_ERROR_REASON_INVALID_ARGUMENT, Field: "selector", DeviceType: deviceType},
+		)
+	}
+	return sel, nil
+}
+
+func (s *DeviceServiceServer) ReserveAndWait(ctx context.Context, req *connect.Request[proto.ReserveRequest], stream *connect.ServerStream[proto.ReserveUpdate]) error {
+	params := reserveParams{
+		user:       s.callerUser(ctx, req.Msg.User),
+		deviceType: req.Msg.DeviceType,
+		selector:   req.Msg.Selector,
+		ttl:        req.Msg.Ttl.AsDuration(),
+		priority:   req.Msg.Priority,
+		preempt:    req.Msg.Preempt,
+	}
+	return s.reserveAndWait(ctx, params,
+		func(position int) error {
+			return stream.Send(&proto.ReserveUpdate{QueuePosition: int32(position)})
+		},
+		func(dev device.Device) error {
+			return stream.Send(&proto.ReserveUpdate{Reservation: reserveResponse(dev)})
+		},
+	)
+}
+
+// reserveAndWait queues for a device, calling queued whenever the caller's
+// position changes and granted once with the reservation. A reservation that
+// cannot be delivered is released again.
+func (s *DeviceServiceServer) reserveAndWait(ctx context.Context, params reserveParams, queued func(int) error, granted func(device.Device) error) error {
+	pool, r, err := s.request(ctx, params)
+	if err != nil {
+		return err
+	}
+	user, deviceType, ttl := r.User, r.Type, r.TTL
+
+	if err := s.fleet.CheckQuota(user, deviceType); err != nil {
+		totalReservations.WithLabelValues("failure").Inc()
+		slog.Info("ReserveAndWait rejected", "user", user, "type", deviceType, "reason", err)
+		return poolError(err, &proto.ErrorDetail{DeviceType: deviceType})
+	}
+
+	w := pool.EnqueueMatching(r)
+	defer pool.Cancel(w)
+
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	slog.Info("WatchDevices started", "client", req.Peer().Addr)
+	lastPosition := 0
+	for {
+		if position := pool.Position(w); position > 0 && position != lastPosition {
+			lastPosition = position
+			if err := queued(position); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case dev, ok := <-w.Granted():
+			if !ok {
+				totalReservations.WithLabelValues("failure").Inc()
+				slog.Info("ReserveAndWait failed", "user", user, "type", deviceType, "reason", w.Err())
+				return poolError(w.Err(), &proto.ErrorDetail{DeviceType: deviceType})
+			}
+			totalReservations.WithLabelValues("success").Inc()
+			updateAvailableMetric(pool)
+			slog.Info("ReserveAndWait granted", "user", user, "type", deviceType, "device_id", dev.ID, "ttl", ttl, "priority", r.Priority)
+			if err := granted(dev); err != nil {
+				pool.Release(dev.ID, "", dev.LeaseToken)
+				updateAvailableMetric(pool)
+				return err
+			}
+			return nil
+		case <-ctx.Done():
+			totalReservations.WithLabelValues("failure").Inc()
+			slog.Info("ReserveAndWait gave up", "user", user, "type", deviceType, "reason", ctx.Err())
+			code := connect.CodeCanceled
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				code = connect.CodeDeadlineExceeded
+			}
+			return connect.NewError(code, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func reserveResponse(dev device.Device) *proto.ReserveResponse {
+	return &proto.ReserveResponse{
+		DeviceId:   dev.ID,
+		Status:     "reserved",
+		LeaseToken: dev.LeaseToken,
+		ExpiresAt:  timestamppb.New(dev.ExpiresAt),
+	}
+}
+
+func (s *DeviceServiceServer) ReleaseDevice(ctx context.Context, req *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error) {
+	if _, err := s.release(ctx, req.Msg.DeviceId, s.callerUser(ctx, req.Msg.User), req.Msg.LeaseToken); err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&proto.ReleaseResponse{Status: "released"}), nil
+}
+
+// release frees deviceID for its holder and returns the device as it was
+// just before the release.
+func (s *DeviceServiceServer) release(ctx context.Context, deviceID, user, token string) (device.Device, error) {
+	if deviceID == "" {
+		return device.Device{}, invalidArgument("device_id", "device_id is required")
+	}
+	if token == "" && user == "" {
+		return device.Device{}, invalidArgument("lease_token", "lease_token or user is required")
+	}
+	if err := s.authorizeDevice(ctx, rbac.Release, deviceID); err != nil {
+		return device.Device{}, err
+	}
+
+	pool, err := s.fleet.PoolFor(deviceID)
+	if err != nil {
+		return device.Device{}, poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+
+	held, _ := pool.Get(deviceID)
+	if err := pool.Release(deviceID, user, token); err != nil {
+		slog.Warn("ReleaseDevice failed", "device_id", deviceID, "user", user, "err", err)
+		return device.Device{}, leaseError(pool, deviceID, err)
+	}
+
+	updateAvailableMetric(pool)
+	slog.Info("ReleaseDevice", "device_id", deviceID, "status", "released")
+	return held, nil
+}
+
+// authorizeDevice checks that the caller may use perm on deviceID.
+func (s *DeviceServiceServer) authorizeDevice(ctx context.Context, perm rbac.Permission, deviceID string) error {
+	if _, _, ok := callerPolicy(ctx); !ok {
+		return nil
+	}
+	pool, err := s.fleet.PoolFor(deviceID)
+	if err != nil {
+		return poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	dev, ok := pool.Get(deviceID)
+	if !ok {
+		return poolError(device.ErrUnknownDevice, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	if err := checkDevices(ctx, perm, dev); err != nil {
+		return poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	return nil
+}
+
+// leaseError reports a failed lease operation on deviceID, naming the
+// current holder when the caller is not it.
+func leaseError(pool *device.DevicePool, deviceID string, err error) *connect.Error {
+	detail := &proto.ErrorDetail{DeviceId: deviceID, DeviceType: pool.Type()}
+	if errors.Is(err, device.ErrLeaseMismatch) {
+		if dev, ok := pool.Get(deviceID); ok {
+			detail.CurrentHolder = dev.ReservedBy
+		}
+	}
+	return poolError(err, detail)
+}
+
+func (s *DeviceServiceServer) ExtendReservation(ctx context.Context, req *connect.Request[proto.ExtendRequest]) (*connect.Response[proto.ExtendResponse], error) {
+	dev, err := s.extend(ctx, req.Msg.DeviceId, s.callerUser(ctx, req.Msg.User), req.Msg.LeaseToken, req.Msg.Extension)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&proto.ExtendResponse{
+		Status:    "extended",
+		ExpiresAt: timestamppb.New(dev.ExpiresAt),
+	}), nil
+}
+
+func (s *DeviceServiceServer) extend(ctx context.Context, deviceID, user, token string, requested *durationpb.Duration) (device.Device, error) {
+	leases := s.leasePolicy()
+	extension := leases.DefaultTTL
+	if requested != nil {
+		extension = requested.AsDuration()
+	}
+	if extension <= 0 {
+		return device.Device{}, invalidArgument("extension", "extension must be positive")
+	}
+	if deviceID == "" {
+		return device.Device{}, invalidArgument("device_id", "device_id is required")
+	}
+	if token == "" && user == "" {
+		return device.Device{}, invalidArgument("lease_token", "lease_token or user is required")
+	}
+	if err := s.authorizeDevice(ctx, rbac.Extend, deviceID); err != nil {
+		return device.Device{}, err
+	}
+
+	pool, err := s.fleet.PoolFor(deviceID)
+	if err != nil {
+		return device.Device{}, poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+
+	dev, err := pool.Extend(deviceID, user, token, extension, leases.MaxLease)
+	if err != nil {
+		slog.Warn("ExtendReservation failed", "device_id", deviceID, "user", user, "err", err)
+		return device.Device{}, leaseError(pool, deviceID, err)
+	}
+
+	slog.Info("ExtendReservation", "device_id", dev.ID, "user", dev.ReservedBy, "expires_at", dev.ExpiresAt)
+	return dev, nil
+}
+
+func (s *DeviceServiceServer) WatchDevices(ctx context.Context, req *connect.Request[proto.WatchRequest], stream *connect.ServerStream[proto.DeviceStatus]) error {
+	filter := device.Filter{
+		Types:      req.Msg.Types,
+		DeviceIDs:  req.Msg.DeviceIds,
+		Labels:     req.Msg.Labels,
+		ReservedBy: req.Msg.ReservedBy,
+	}
+	return s.watch(ctx, req.Peer().Addr, req.Msg.Epoch, req.Msg.SinceRevision, filter, func(event device.Event) error {
+		if event.Kind == eventResync {
+			return stream.Send(&proto.DeviceStatus{Event: string(eventResync), Revision: event.Revision, Epoch: event.Epoch})
+		}
+		status := deviceStatus(event.Device, event.Kind, event.Revision)
+		status.Epoch = event.Epoch
+		return stream.Send(status)
+	})
+}
+
+// watch sends matching devices to send: buffered events after since of
+// epoch when they are still available, otherwise a snapshot (preceded by an
+// eventResync marker if the caller asked to resume), then live events.
+func (s *DeviceServiceServer) watch(ctx context.Context, client, epoch string, since uint64, filter device.Filter, send func(device.Event) error) error {
+	events := s.fleet.Events()
+	sub, missed, ok := events.SubscribeSince(epoch, since, watchBuffer)
+	defer events.Unsubscribe(sub)
+
+	slog.Info("WatchDevices started", "client", client, "epoch", epoch, "since_revision", since, "resumed", since > 0 && ok)
+
+	// Devices the caller may not view are left out.
+	matches := func(event device.Event) bool {
+		return filter.MatchesEvent(event) && checkDevices(ctx, rbac.View, event.Device) == nil
+	}
+
+	var initial []device.Event
+	switch {
+	case since > 0 && ok:
+		for _, event := range missed {
+			if matches(event) {
+				initial = append(initial, event)
+			}
+		}
+	default:
+		revision := events.Revision()
+		if since > 0 {
+			initial = append(initial, device.Event{Kind: eventResync, Epoch: events.Epoch(), Revision: revision})
+		}
+		for _, dev := range viewable(ctx, s.fleet.Snapshot()) {
+			if filter.Matches(dev) {
+				initial = append(initial, device.Event{Kind: device.EventSnapshot, Epoch: events.Epoch(), Revision: revision, Device: dev})
+			}
+		}
+	}
+
+	for _, event := range initial {
+		if err := send(event); err != nil {
+			slog.Error("WatchDevices stream error", "client", client, "err", err)
+			return err
+		}
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			slog.Info("WatchDevices ended", "client", req.Peer().Addr, "reason", ctx.Err())
+			slog.Info("WatchDevices ended", "client", client, "reason", ctx.Err())
 			return nil
-		case <-ticker.C:
-			for _, dev := range s.pool.All() {
-				err := stream.Send(&proto.DeviceStatus{
-					DeviceId:   dev.ID,
-					ReservedBy: dev.ReservedBy,
-					Available:  device.IsAvailable(dev),
-				})
-				if err != nil {
-					slog.Error("WatchDevices stream error", "client", req.Peer().Addr, "err", err)
-					return err
-				}
+		case event, ok := <-sub.C:
+			if !ok {
+				slog.Warn("WatchDevices dropped slow watcher", "client", client)
+				return connect.NewError(connect.CodeResourceExhausted, errors.New("watcher fell behind; reconnect with since_revision to catch up"))
+			}
+			if !matches(event) {
+				continue
+			}
+			if err := send(event); err != nil {
+				slog.Error("WatchDevices stream error", "client", client, "err", err)
+				return err
 			}
 		}
 	}
 }
+
+func deviceStatus(dev device.Device, kind device.EventKind, revision uint64) *proto.DeviceStatus {
+	return &proto.DeviceStatus{
+		DeviceId:   dev.ID,
+		ReservedBy: dev.ReservedBy,
+		Available:  device.IsAvailable(&dev),
+		Retiring:   dev.Retiring,
+		Event:      string(kind),
+		Revision:   revision,
+		PreemptAt:  optionalTimestamp(dev.PreemptAt),
+
+		Health:       dev.Health.String(),
+		HealthReason: dev.HealthReason,
+		Draining:     dev.Draining,
+	}
+}
@@ -0,0 +1,587 @@
+package protoconnect
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// DeviceServiceV2Server serves devicefleet.v2 from the same fleet as a v1
+// DeviceServiceServer, so v1 and v2 clients see one set of reservations.
+type DeviceServiceV2Server struct {
+	v1 *DeviceServiceServer
+}
+
+func NewDeviceServiceV2Server(v1 *DeviceServiceServer) *DeviceServiceV2Server {
+	return &DeviceServiceV2Server{v1: v1}
+}
+
+func (s *DeviceServiceV2Server) ReserveDevice(ctx context.Context, req *connect.Request[protov2.ReserveRequest]) (*connect.Response[protov2.ReserveResponse], error) {
+	dev, err := s.v1.reserve(ctx, s.reserveParams(ctx, req.Msg))
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&protov2.ReserveResponse{
+		Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RESERVED),
+	}), nil
+}
+
+func (s *DeviceServiceV2Server) ReserveAndWait(ctx context.Context, req *connect.Request[protov2.ReserveRequest], stream *connect.ServerStream[protov2.ReserveUpdate]) error {
+	return s.v1.reserveAndWait(ctx, s.reserveParams(ctx, req.Msg),
+		func(position int) error {
+			return stream.Send(&protov2.ReserveUpdate{QueuePosition: int32(position)})
+		},
+		func(dev device.Device) error {
+			return stream.Send(&protov2.ReserveUpdate{
+				Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RESERVED),
+			})
+		},
+	)
+}
+
+func (s *DeviceServiceV2Server) reserveParams(ctx context.Context, req *protov2.ReserveRequest) reserveParams {
+	return reserveParams{
+		user:       s.v1.callerUser(ctx, req.User),
+		deviceType: req.DeviceType,
+		selector:   req.Selector,
+		ttl:        req.Ttl.AsDuration(),
+		priority:   req.Priority,
+		preempt:    req.Preempt,
+	}
+}
+
+func (s *DeviceServiceV2Server) ReleaseDevice(ctx context.Context, req *connect.Request[protov2.ReleaseRequest]) (*connect.Response[protov2.ReleaseResponse], error) {
+	dev, err := s.v1.release(ctx, req.Msg.DeviceId, s.v1.callerUser(ctx, req.Msg.User), req.Msg.LeaseToken)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&protov2.ReleaseResponse{
+		Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RELEASED),
+	}), nil
+}
+
+func (s *DeviceServiceV2Server) ExtendReservation(ctx context.Context, req *connect.Request[protov2.ExtendRequest]) (*connect.Response[protov2.ExtendResponse], error) {
+	dev, err := s.v1.extend(ctx, req.Msg.DeviceId, s.v1.callerUser(ctx, req.Msg.User), req.Msg.LeaseToken, req.Msg.Extension)
+	if err != nil {
+		return nil, err
+	}
+	return connect.NewResponse(&protov2.ExtendResponse{
+		Reservation: reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_EXTENDED),
+	}), nil
+}
+
+func (s *DeviceServiceV2Server) WatchDevices(ctx context.Context, req *connect.Request[protov2.WatchRequest], stream *connect.ServerStream[protov2.DeviceEvent]) error {
+	filter := device.Filter{
+		Types:      req.Msg.Types,
+		DeviceIDs:  req.Msg.DeviceIds,
+		Labels:     req.Msg.Labels,
+		ReservedBy: req.Msg.ReservedBy,
+	}
+	return s.v1.watch(ctx, req.Peer().Addr, req.Msg.Epoch, req.Msg.SinceRevision, filter, func(event device.Event) error {
+		msg := &protov2.DeviceEvent{
+			Type:     eventTypesV2[event.Kind],
+			Revision: event.Revision,
+			Epoch:    event.Epoch,
+		}
+		if event.Kind != eventResync {
+			msg.Device = deviceV2(event.Device, event.Kind)
+		}
+		return stream.Send(msg)
+	})
+}
+
+func (s *DeviceServiceV2Server) ListDevices(ctx context.Context, req *connect.Request[protov2.ListDevicesRequest]) (*connect.Response[protov2.ListDevicesResponse], error) {
+	pageSize := int(req.Msg.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, invalidArgument("page_size", "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	after, err := decodePageToken(req.Msg.PageToken)
+	if err != nil {
+		return nil, invalidArgument("page_token", "page_token is not a token returned by ListDevices")
+	}
+
+	filter := device.Filter{Types: req.Msg.Types, Labels: req.Msg.Labels}
+	devices := viewable(ctx, s.v1.fleet.Snapshot())
+	slices.SortFunc(devices, func(a, b device.Device) int { return strings.Compare(a.ID, b.ID) })
+
+	resp := &protov2.ListDevicesResponse{}
+	for _, dev := range devices {
+		if dev.ID <= after || !filter.Matches(dev) {
+			continue
+		}
+		msg := deviceV2(dev, "")
+		if len(req.Msg.States) > 0 && !slices.Contains(req.Msg.States, msg.State) {
+			continue
+		}
+		if len(req.Msg.Health) > 0 && !slices.Contains(req.Msg.Health, msg.Health) {
+			continue
+		}
+		if len(resp.Devices) == pageSize {
+			resp.NextPageToken = encodePageToken(resp.Devices[pageSize-1].Id)
+			break
+		}
+		resp.Devices = append(resp.Devices, msg)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *DeviceServiceV2Server) GetDevice(ctx context.Context, req *connect.Request[protov2.GetDeviceRequest]) (*connect.Response[protov2.GetDeviceResponse], error) {
+	deviceID := req.Msg.DeviceId
+	if deviceID == "" {
+		return nil, invalidArgument("device_id", "device_id is required")
+	}
+	if err := s.v1.authorizeDevice(ctx, rbac.View, deviceID); err != nil {
+		return nil, err
+	}
+
+	pool, err := s.v1.fleet.PoolFor(deviceID)
+	if err != nil {
+		return nil, poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	dev, ok := pool.Get(deviceID)
+	if !ok {
+		// Removed between the two lookups.
+		return nil, poolError(device.ErrUnknownDevice, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	return connect.NewResponse(&protov2.GetDeviceResponse{Device: deviceV2(dev, "")}), nil
+}
+
+func (s *DeviceServiceV2Server) ReserveBatch(ctx context.Context, req *connect.Request[protov2.ReserveBatchRequest]) (*connect.Response[protov2.ReserveBatchResponse], error) {
+	user := s.v1.callerUser(ctx, req.Msg.User)
+	if user == "" {
+		return nil, invalidArgument("user", "user is required")
+	}
+	if len(req.Msg.Devices) == 0 {
+		return nil, invalidArgument("devices", "at least one device is required")
+	}
+
+	reqs := make([]device.Requirement, len(req.Msg.Devices))
+	for i, d := range req.Msg.Devices {
+		if d.DeviceType == "" {
+			return nil, invalidArgument(fmt.Sprintf("devices[%d].device_type", i), "device_type is required")
+		}
+		sel, err := device.ParseSelector(d.Selector)
+		if err != nil {
+			return nil, invalidArgument(fmt.Sprintf("devices[%d].selector", i), "invalid selector: "+err.Error())
+		}
+		var scope device.Scope
+		if pool, err := s.v1.fleet.Pool(d.DeviceType); err == nil {
+			if scope, err = reserveScope(ctx, pool, user, d.DeviceType, sel); err != nil {
+				return nil, poolError(err, &proto.ErrorDetail{DeviceType: d.DeviceType})
+			}
+		}
+		reqs[i] = device.Requirement{Type: d.DeviceType, Selector: sel, Scope: scope}
+	}
+
+	ttl := s.v1.leasePolicy().ClampTTL(req.Msg.Ttl.AsDuration())
+	devices, err := s.v1.fleet.ReserveGroup(user, reqs, ttl)
+	if err != nil {
+		totalReservations.WithLabelValues("failure").Inc()
+		slog.Info("ReserveBatch failed", "user", user, "devices", len(reqs), "err", err)
+		detail := &proto.ErrorDetail{}
+		if errors.Is(err, device.ErrUnsatisfiable) {
+			detail.Field = "devices"
+		}
+		return nil, poolError(err, detail)
+	}
+
+	resp := &protov2.ReserveBatchResponse{GroupLease: devices[0].LeaseToken}
+	ids := make([]string, len(devices))
+	for i, dev := range devices {
+		resp.Reservations = append(resp.Reservations, reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RESERVED))
+		ids[i] = dev.ID
+	}
+	totalReservations.WithLabelValues("success").Add(float64(len(devices)))
+	s.updateAvailableMetrics()
+	slog.Info("ReserveBatch success", "user", user, "device_ids", ids, "ttl", ttl)
+	return connect.NewResponse(resp), nil
+}
+
+func (s *DeviceServiceV2Server) ReleaseBatch(ctx context.Context, req *connect.Request[protov2.ReleaseBatchRequest]) (*connect.Response[protov2.ReleaseBatchResponse], error) {
+	if req.Msg.GroupLease == "" {
+		return nil, invalidArgument("group_lease", "group_lease is required")
+	}
+	if err := checkGroup(ctx, rbac.Release, s.v1.fleet.Group(req.Msg.GroupLease)); err != nil {
+		return nil, err
+	}
+
+	devices, err := s.v1.fleet.ReleaseGroup(req.Msg.GroupLease)
+	if err != nil {
+		slog.Warn("ReleaseBatch failed", "err", err)
+		return nil, poolError(err, &proto.ErrorDetail{})
+	}
+
+	resp := &protov2.ReleaseBatchResponse{}
+	for _, dev := range devices {
+		resp.Reservations = append(resp.Reservations, reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_RELEASED))
+	}
+	s.updateAvailableMetrics()
+	slog.Info("ReleaseBatch", "user", devices[0].ReservedBy, "devices", len(devices))
+	return connect.NewResponse(resp), nil
+}
+
+func (s *DeviceServiceV2Server) ExtendBatch(ctx context.Context, req *connect.Request[protov2.ExtendBatchRequest]) (*connect.Response[protov2.ExtendBatchResponse], error) {
+	leases := s.v1.leasePolicy()
+	extension := leases.DefaultTTL
+	if req.Msg.Extension != nil {
+		extension = req.Msg.Extension.AsDuration()
+	}
+	if extension <= 0 {
+		return nil, invalidArgument("extension", "extension must be positive")
+	}
+	if req.Msg.GroupLease == "" {
+		return nil, invalidArgument("group_lease", "group_lease is required")
+	}
+	if err := checkGroup(ctx, rbac.Extend, s.v1.fleet.Group(req.Msg.GroupLease)); err != nil {
+		return nil, err
+	}
+
+	devices, err := s.v1.fleet.ExtendGroup(req.Msg.GroupLease, extension, leases.MaxLease)
+	if err != nil {
+		slog.Warn("ExtendBatch failed", "err", err)
+		return nil, poolError(err, &proto.ErrorDetail{})
+	}
+
+	resp := &protov2.ExtendBatchResponse{}
+	for _, dev := range devices {
+		resp.Reservations = append(resp.Reservations, reservationV2(dev, protov2.ReservationState_RESERVATION_STATE_EXTENDED))
+	}
+	slog.Info("ExtendBatch", "user", devices[0].ReservedBy, "devices", len(devices), "expires_at", devices[0].ExpiresAt)
+	return connect.NewResponse(resp), nil
+}
+
+// checkGroup checks that the caller may use perm on every device of a group.
+// Devices only ever leave a group, so the check holds for the operation that
+// follows it.
+func checkGroup(ctx context.Context, perm rbac.Permission, devices []device.Device) error {
+	for _, dev := range devices {
+		if err := checkDevices(ctx, perm, dev); err != nil {
+			return poolError(err, &proto.ErrorDetail{DeviceId: dev.ID})
+		}
+	}
+	return nil
+}
+
+func (s *DeviceServiceV2Server) GetQuotaUsage(ctx context.Context, req *connect.Request[protov2.GetQuotaUsageRequest]) (*connect.Response[protov2.GetQuotaUsageResponse], error) {
+	resp := &protov2.GetQuotaUsageResponse{}
+	for _, u := range s.v1.fleet.QuotaUsage(req.Msg.User) {
+		resp.Usage = append(resp.Usage, &protov2.QuotaUsage{
+			Scope:      u.Scope,
+			Subject:    u.Subject,
+			DeviceType: u.DeviceType,
+			InUse:      int32(u.InUse),
+			Limit:      int32(u.Limit),
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *DeviceServiceV2Server) CreateBooking(ctx context.Context, req *connect.Request[protov2.CreateBookingRequest]) (*connect.Response[protov2.CreateBookingResponse], error) {
+	deviceID, user := req.Msg.DeviceId, s.v1.callerUser(ctx, req.Msg.User)
+	if deviceID == "" {
+		return nil, invalidArgument("device_id", "device_id is required")
+	}
+	if user == "" {
+		return nil, invalidArgument("user", "user is required")
+	}
+	if req.Msg.StartTime == nil {
+		return nil, invalidArgument("start_time", "start_time is required")
+	}
+	if req.Msg.EndTime == nil {
+		return nil, invalidArgument("end_time", "end_time is required")
+	}
+	start, end := req.Msg.StartTime.AsTime(), req.Msg.EndTime.AsTime()
+	if !start.After(time.Now()) {
+		return nil, invalidArgument("start_time", "start_time must be in the future")
+	}
+	if !end.After(start) {
+		return nil, invalidArgument("end_time", "end_time must be after start_time")
+	}
+	if maxLease := s.v1.leasePolicy().MaxLease; end.Sub(start) > maxLease {
+		return nil, invalidArgument("end_time", fmt.Sprintf("bookings may last at most %s", maxLease))
+	}
+
+	if err := s.v1.authorizeDevice(ctx, rbac.Book, deviceID); err != nil {
+		return nil, err
+	}
+	b, err := s.v1.fleet.Book(deviceID, user, start, end)
+	if err != nil {
+		slog.Info("CreateBooking failed", "device_id", deviceID, "user", user, "err", err)
+		return nil, poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	booking := bookingV2(b)
+	booking.LeaseToken = b.LeaseToken
+	return connect.NewResponse(&protov2.CreateBookingResponse{Booking: booking}), nil
+}
+
+func (s *DeviceServiceV2Server) ListBookings(ctx context.Context, req *connect.Request[protov2.ListBookingsRequest]) (*connect.Response[protov2.ListBookingsResponse], error) {
+	visible := make(map[string]bool)
+	for _, dev := range viewable(ctx, s.v1.fleet.Snapshot()) {
+		visible[dev.ID] = true
+	}
+
+	resp := &protov2.ListBookingsResponse{}
+	for _, b := range s.v1.fleet.Bookings() {
+		if !visible[b.DeviceID] || req.Msg.DeviceId != "" && b.DeviceID != req.Msg.DeviceId {
+			continue
+		}
+		if req.Msg.User != "" && b.User != req.Msg.User {
+			continue
+		}
+		resp.Bookings = append(resp.Bookings, bookingV2(b))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *DeviceServiceV2Server) CancelBooking(ctx context.Context, req *connect.Request[protov2.CancelBookingRequest]) (*connect.Response[protov2.CancelBookingResponse], error) {
+	id := req.Msg.BookingId
+	if id == "" {
+		return nil, invalidArgument("booking_id", "booking_id is required")
+	}
+	user := s.v1.callerUser(ctx, req.Msg.User)
+	if req.Msg.LeaseToken == "" && user == "" {
+		return nil, invalidArgument("lease_token", "lease_token or user is required")
+	}
+	for _, b := range s.v1.fleet.Bookings() {
+		if b.ID == id {
+			if err := s.v1.authorizeDevice(ctx, rbac.Book, b.DeviceID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b, err := s.v1.fleet.CancelBooking(id, user, req.Msg.LeaseToken)
+	if err != nil {
+		slog.Warn("CancelBooking failed", "booking_id", id, "err", err)
+		return nil, poolError(err, &proto.ErrorDetail{BookingId: id})
+	}
+	return connect.NewResponse(&protov2.CancelBookingResponse{Booking: bookingV2(b)}), nil
+}
+
+func (s *DeviceServiceV2Server) ReportDeviceFailure(ctx context.Context, req *connect.Request[protov2.ReportDeviceFailureRequest]) (*connect.Response[protov2.ReportDeviceFailureResponse], error) {
+	deviceID := req.Msg.DeviceId
+	if deviceID == "" {
+		return nil, invalidArgument("device_id", "device_id is required")
+	}
+	user := s.v1.callerUser(ctx, req.Msg.User)
+	if req.Msg.LeaseToken == "" && user == "" {
+		return nil, invalidArgument("lease_token", "lease_token or user is required")
+	}
+	if err := s.v1.authorizeDevice(ctx, rbac.ReportFailure, deviceID); err != nil {
+		return nil, err
+	}
+
+	dev, err := s.v1.fleet.ReportFailure(deviceID, user, req.Msg.LeaseToken, req.Msg.Reason)
+	if err != nil {
+		return nil, poolError(err, &proto.ErrorDetail{DeviceId: deviceID})
+	}
+	return connect.NewResponse(&protov2.ReportDeviceFailureResponse{Device: deviceV2(dev, "")}), nil
+}
+
+func (s *DeviceServiceV2Server) GetDrainStatus(ctx context.Context, req *connect.Request[protov2.GetDrainStatusRequest]) (*connect.Response[protov2.GetDrainStatusResponse], error) {
+	if err := drainTarget(req.Msg.DeviceId, req.Msg.DeviceType); err != nil {
+		return nil, err
+	}
+
+	var devices []device.Device
+	if req.Msg.DeviceId != "" {
+		if err := s.v1.authorizeDevice(ctx, rbac.View, req.Msg.DeviceId); err != nil {
+			return nil, err
+		}
+		pool, err := s.v1.fleet.PoolFor(req.Msg.DeviceId)
+		if err != nil {
+			return nil, poolError(err, &proto.ErrorDetail{DeviceId: req.Msg.DeviceId})
+		}
+		dev, ok := pool.Get(req.Msg.DeviceId)
+		if !ok {
+			return nil, poolError(device.ErrUnknownDevice, &proto.ErrorDetail{DeviceId: req.Msg.DeviceId})
+		}
+		devices = []device.Device{dev}
+	} else {
+		pool, err := s.v1.fleet.Pool(req.Msg.DeviceType)
+		if err != nil {
+			return nil, poolError(err, &proto.ErrorDetail{DeviceType: req.Msg.DeviceType})
+		}
+		devices = viewable(ctx, pool.Snapshot())
+	}
+	return connect.NewResponse(&protov2.GetDrainStatusResponse{Devices: devicesV2(devices), Drained: allDrained(devices)}), nil
+}
+
+func (s *DeviceServiceV2Server) WhoAmI(ctx context.Context, req *connect.Request[protov2.WhoAmIRequest]) (*connect.Response[protov2.WhoAmIResponse], error) {
+	id, ok := auth.FromContext(ctx)
+	return connect.NewResponse(&protov2.WhoAmIResponse{Authenticated: ok, User: id.User, Groups: id.Groups}), nil
+}
+
+func drainTarget(deviceID, deviceType string) *connect.Error {
+	if (deviceID == "") == (deviceType == "") {
+		return invalidArgument("device_id", "exactly one of device_id and device_type is required")
+	}
+	return nil
+}
+
+func allDrained(devices []device.Device) bool {
+	for i := range devices {
+		if !device.IsDrained(&devices[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func devicesV2(devices []device.Device) []*protov2.Device {
+	result := make([]*protov2.Device, len(devices))
+	for i, dev := range devices {
+		result[i] = deviceV2(dev, "")
+	}
+	return result
+}
+
+func (s *DeviceServiceV2Server) updateAvailableMetrics() {
+	for _, pool := range s.v1.fleet.Pools() {
+		updateAvailableMetric(pool)
+	}
+}
+
+// Page tokens are the last device ID of the previous page, so a page stays
+// stable when devices before it are reserved, added or removed.
+func encodePageToken(lastID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastID))
+}
+
+func decodePageToken(token string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(token)
+	return string(id), err
+}
+
+var eventTypesV2 = map[device.EventKind]protov2.EventType{
+	device.EventSnapshot: protov2.EventType_EVENT_TYPE_SNAPSHOT,
+	eventResync:          protov2.EventType_EVENT_TYPE_RESYNC,
+	device.EventReserved: protov2.EventType_EVENT_TYPE_RESERVED,
+	device.EventReleased: protov2.EventType_EVENT_TYPE_RELEASED,
+	device.EventExpired:  protov2.EventType_EVENT_TYPE_EXPIRED,
+	device.EventExtended: protov2.EventType_EVENT_TYPE_EXTENDED,
+	device.EventAdded:    protov2.EventType_EVENT_TYPE_ADDED,
+	device.EventUpdated:  protov2.EventType_EVENT_TYPE_UPDATED,
+	device.EventRetiring: protov2.EventType_EVENT_TYPE_RETIRING,
+	device.EventRemoved:  protov2.EventType_EVENT_TYPE_REMOVED,
+
+	device.EventPreempting:          protov2.EventType_EVENT_TYPE_PREEMPTING,
+	device.EventPreempted:           protov2.EventType_EVENT_TYPE_PREEMPTED,
+	device.EventPreemptionCancelled: protov2.EventType_EVENT_TYPE_PREEMPTION_CANCELLED,
+	device.EventHealthChanged:       protov2.EventType_EVENT_TYPE_HEALTH_CHANGED,
+	device.EventDraining:            protov2.EventType_EVENT_TYPE_DRAINING,
+	device.EventDrained:             protov2.EventType_EVENT_TYPE_DRAINED,
+	device.EventUndrained:           protov2.EventType_EVENT_TYPE_UNDRAINED,
+	device.EventForceReleased:       protov2.EventType_EVENT_TYPE_FORCE_RELEASED,
+	device.EventTransferred:         protov2.EventType_EVENT_TYPE_TRANSFERRED,
+}
+
+func reservationV2(dev device.Device, state protov2.ReservationState) *protov2.Reservation {
+	return &protov2.Reservation{
+		DeviceId:   dev.ID,
+		DeviceType: dev.Type,
+		User:       dev.ReservedBy,
+		LeaseToken: dev.LeaseToken,
+		ReservedAt: timestamppb.New(dev.ReservedAt),
+		ExpiresAt:  timestamppb.New(dev.ExpiresAt),
+		State:      state,
+		Priority:   int32(dev.Priority),
+	}
+}
+
+func deviceV2(dev device.Device, kind device.EventKind) *protov2.Device {
+	msg := &protov2.Device{
+		Id:       dev.ID,
+		Type:     dev.Type,
+		Labels:   dev.Labels,
+		Location: dev.Location,
+		State:    deviceStateV2(dev, kind),
+
+		Health:       healthV2(dev.Health),
+		HealthReason: dev.HealthReason,
+		Failures:     int32(dev.Failures),
+		Draining:     dev.Draining,
+	}
+	if device.IsReserved(&dev) {
+		msg.ReservedBy = dev.ReservedBy
+		msg.ReservedAt = optionalTimestamp(dev.ReservedAt)
+		msg.ExpiresAt = optionalTimestamp(dev.ExpiresAt)
+		msg.Priority = int32(dev.Priority)
+		msg.PreemptAt = optionalTimestamp(dev.PreemptAt)
+	}
+	return msg
+}
+
+// bookingV2 leaves out the lease token, which only the booking's creator gets.
+func bookingV2(b device.Booking) *protov2.Booking {
+	return &protov2.Booking{
+		Id:         b.ID,
+		DeviceId:   b.DeviceID,
+		DeviceType: b.DeviceType,
+		User:       b.User,
+		StartTime:  timestamppb.New(b.Start),
+		EndTime:    timestamppb.New(b.End),
+	}
+}
+
+func deviceStateV2(dev device.Device, kind device.EventKind) protov2.DeviceState {
+	switch {
+	case kind == device.EventRemoved:
+		return protov2.DeviceState_DEVICE_STATE_REMOVED
+	case dev.Retiring:
+		return protov2.DeviceState_DEVICE_STATE_RETIRING
+	case device.IsDrained(&dev):
+		return protov2.DeviceState_DEVICE_STATE_DRAINED
+	case dev.Draining:
+		return protov2.DeviceState_DEVICE_STATE_DRAINING
+	case device.IsReserved(&dev):
+		return protov2.DeviceState_DEVICE_STATE_RESERVED
+	case dev.Health != device.Healthy:
+		return protov2.DeviceState_DEVICE_STATE_UNAVAILABLE
+	default:
+		return protov2.DeviceState_DEVICE_STATE_AVAILABLE
+	}
+}
+
+// HealthState values follow device.Health, shifted past UNSPECIFIED.
+func healthV2(h device.Health) protov2.HealthState {
+	return protov2.HealthState(h + 1)
+}
+
+func healthFromV2(h protov2.HealthState) (device.Health, bool) {
+	if _, ok := protov2.HealthState_name[int32(h)]; !ok || h == protov2.HealthState_HEALTH_STATE_UNSPECIFIED {
+		return device.Healthy, false
+	}
+	return device.Health(h - 1), true
+}
+
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,76 @@
+package protoconnect
+
+import (
+	"errors"
+
+	connect "connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+// poolError maps a device package error to a Connect error whose code and
+// ErrorDetail reason clients can switch on instead of parsing messages.
+func poolError(err error, detail *proto.ErrorDetail) *connect.Error {
+	code := connect.CodeInternal
+	switch {
+	case errors.Is(err, device.ErrUnknownType):
+		code, detail.Reason = connect.CodeNotFound, proto.ErrorReason_ERROR_REASON_UNKNOWN_DEVICE_TYPE
+	case errors.Is(err, device.ErrUnknownDevice):
+		code, detail.Reason = connect.CodeNotFound, proto.ErrorReason_ERROR_REASON_UNKNOWN_DEVICE
+	case errors.Is(err, device.ErrNoDevices):
+		code, detail.Reason = connect.CodeResourceExhausted, proto.ErrorReason_ERROR_REASON_NO_DEVICES_AVAILABLE
+	case errors.Is(err, device.ErrNotReserved):
+		code, detail.Reason = connect.CodeFailedPrecondition, proto.ErrorReason_ERROR_REASON_NOT_RESERVED
+	case errors.Is(err, device.ErrLeaseMismatch):
+		code, detail.Reason = connect.CodePermissionDenied, proto.ErrorReason_ERROR_REASON_LEASE_MISMATCH
+	case errors.Is(err, device.ErrUnsatisfiable):
+		code, detail.Reason = connect.CodeInvalidArgument, proto.ErrorReason_ERROR_REASON_INVALID_ARGUMENT
+	case errors.Is(err, device.ErrMaxLease):
+		code, detail.Reason = connect.CodeFailedPrecondition, proto.ErrorReason_ERROR_REASON_MAX_LEASE_REACHED
+	case errors.Is(err, device.ErrQuotaExceeded):
+		code, detail.Reason = connect.CodeResourceExhausted, proto.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED
+		var quotaErr *device.QuotaError
+		if errors.As(err, &quotaErr) {
+			detail.Quota = &proto.Quota{
+				Scope:      quotaErr.Scope,
+				Subject:    quotaErr.Subject,
+				DeviceType: quotaErr.DeviceType,
+				InUse:      int32(quotaErr.InUse),
+				Limit:      int32(quotaErr.Limit),
+			}
+		}
+	case errors.Is(err, device.ErrBookingConflict):
+		code, detail.Reason = connect.CodeFailedPrecondition, proto.ErrorReason_ERROR_REASON_BOOKING_CONFLICT
+		var conflict *device.BookingConflict
+		if errors.As(err, &conflict) {
+			detail.DeviceId, detail.BookingId = conflict.DeviceID, conflict.BookingID
+		}
+	case errors.Is(err, device.ErrUnknownBooking):
+		code, detail.Reason = connect.CodeNotFound, proto.ErrorReason_ERROR_REASON_UNKNOWN_BOOKING
+	case errors.Is(err, device.ErrDeviceExists):
+		code, detail.Reason = connect.CodeAlreadyExists, proto.ErrorReason_ERROR_REASON_DEVICE_EXISTS
+	case errors.Is(err, rbac.ErrPermissionDenied):
+		code, detail.Reason = connect.CodePermissionDenied, proto.ErrorReason_ERROR_REASON_PERMISSION_DENIED
+		var denied *rbac.DeniedError
+		if errors.As(err, &denied) {
+			detail.Permission = string(denied.Permission)
+		}
+	}
+	return withDetail(connect.NewError(code, err), detail)
+}
+
+func invalidArgument(field, message string) *connect.Error {
+	return withDetail(connect.NewError(connect.CodeInvalidArgument, errors.New(message)), &proto.ErrorDetail{
+		Reason: proto.ErrorReason_ERROR_REASON_INVALID_ARGUMENT,
+		Field:  field,
+	})
+}
+
+func withDetail(err *connect.Error, detail *proto.ErrorDetail) *connect.Error {
+	if d, detailErr := connect.NewErrorDetail(detail); detailErr == nil {
+		err.AddDetail(d)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package protoconnect
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
+)
+
+// procedurePermissions is the permission each DeviceService RPC needs.
+// WhoAmI needs none; procedures missing here are refused.
+var procedurePermissions = map[string]rbac.Permission{
+	DeviceServiceReserveDeviceProcedure:     rbac.Reserve,
+	DeviceServiceReserveAndWaitProcedure:    rbac.Reserve,
+	DeviceServiceReleaseDeviceProcedure:     rbac.Release,
+	DeviceServiceExtendReservationProcedure: rbac.Extend,
+	DeviceServiceWatchDevicesProcedure:      rbac.View,
+
+	protov2connect.DeviceServiceReserveDeviceProcedure:       rbac.Reserve,
+	protov2connect.DeviceServiceReserveAndWaitProcedure:      rbac.Reserve,
+	protov2connect.DeviceServiceReleaseDeviceProcedure:       rbac.Release,
+	protov2connect.DeviceServiceExtendReservationProcedure:   rbac.Extend,
+	protov2connect.DeviceServiceWatchDevicesProcedure:        rbac.View,
+	protov2connect.DeviceServiceListDevicesProcedure:         rbac.View,
+	protov2connect.DeviceServiceGetDeviceProcedure:           rbac.View,
+	protov2connect.DeviceServiceReserveBatchProcedure:        rbac.Reserve,
+	protov2connect.DeviceServiceReleaseBatchProcedure:        rbac.Release,
+	protov2connect.DeviceServiceExtendBatchProcedure:         rbac.Extend,
+	protov2connect.DeviceServiceGetQuotaUsageProcedure:       rbac.View,
+	protov2connect.DeviceServiceCreateBookingProcedure:       rbac.Book,
+	protov2connect.DeviceServiceListBookingsProcedure:        rbac.View,
+	protov2connect.DeviceServiceCancelBookingProcedure:       rbac.Book,
+	protov2connect.DeviceServiceReportDeviceFailureProcedure: rbac.ReportFailure,
+	protov2connect.DeviceServiceGetDrainStatusProcedure:      rbac.View,
+	protov2connect.DeviceServiceWhoAmIProcedure:              "",
+}
+
+type policyKey struct{}
+
+func withPolicy(ctx context.Context, policy *rbac.Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// callerPolicy returns the policy the call is subject to and who made it.
+// ok is false when the call is not access controlled.
+func callerPolicy(ctx context.Context) (policy *rbac.Policy, id auth.Identity, ok bool) {
+	policy, _ = ctx.Value(policyKey{}).(*rbac.Policy)
+	id, authenticated := auth.FromContext(ctx)
+	return policy, id, policy != nil && authenticated
+}
+
+// scopeFor returns the devices of deviceType the caller may use perm on,
+// nil meaning all of them.
+func scopeFor(ctx context.Context, perm rbac.Permission, deviceType string) (device.Scope, error) {
+	policy, id, ok := callerPolicy(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return policy.Scope(id, perm, deviceType)
+}
+
+// checkDevices reports whether the caller may use perm on every one of
+// devices.
+func checkDevices(ctx context.Context, perm rbac.Permission, devices ...device.Device) error {
+	policy, id, ok := callerPolicy(ctx)
+	if !ok {
+		return nil
+	}
+	for _, d := range devices {
+		if err := policy.CheckDevice(id, perm, &d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// viewable drops the devices the caller may not view.
+func viewable(ctx context.Context, devices []device.Device) []device.Device {
+	return slices.DeleteFunc(devices, func(d device.Device) bool {
+		return checkDevices(ctx, rbac.View, d) != nil
+	})
+}
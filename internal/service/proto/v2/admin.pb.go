@@ -0,0 +1,1005 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/v2/admin.proto
+
+package protov2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ForceReleaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceReleaseRequest) Reset() {
+	*x = ForceReleaseRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceReleaseRequest) ProtoMessage() {}
+
+func (x *ForceReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ForceReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ForceReleaseRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ForceReleaseRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ForceReleaseResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The reservation that was ended.
+	Reservation   *Reservation `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceReleaseResponse) Reset() {
+	*x = ForceReleaseResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceReleaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceReleaseResponse) ProtoMessage() {}
+
+func (x *ForceReleaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceReleaseResponse.ProtoReflect.Descriptor instead.
+func (*ForceReleaseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ForceReleaseResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type TransferReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	ToUser        string                 `protobuf:"bytes,2,opt,name=to_user,json=toUser,proto3" json:"to_user,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferReservationRequest) Reset() {
+	*x = TransferReservationRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferReservationRequest) ProtoMessage() {}
+
+func (x *TransferReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferReservationRequest.ProtoReflect.Descriptor instead.
+func (*TransferReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TransferReservationRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *TransferReservationRequest) GetToUser() string {
+	if x != nil {
+		return x.ToUser
+	}
+	return ""
+}
+
+func (x *TransferReservationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type TransferReservationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The reservation under to_user, with a new lease token and the same expiry.
+	Reservation   *Reservation `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferReservationResponse) Reset() {
+	*x = TransferReservationResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferReservationResponse) ProtoMessage() {}
+
+func (x *TransferReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferReservationResponse.ProtoReflect.Descriptor instead.
+func (*TransferReservationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransferReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ReleaseUserReservationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseUserReservationsRequest) Reset() {
+	*x = ReleaseUserReservationsRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseUserReservationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseUserReservationsRequest) ProtoMessage() {}
+
+func (x *ReleaseUserReservationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseUserReservationsRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseUserReservationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReleaseUserReservationsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ReleaseUserReservationsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReleaseUserReservationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservations  []*Reservation         `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseUserReservationsResponse) Reset() {
+	*x = ReleaseUserReservationsResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseUserReservationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseUserReservationsResponse) ProtoMessage() {}
+
+func (x *ReleaseUserReservationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseUserReservationsResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseUserReservationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReleaseUserReservationsResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+type AddDeviceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id and type are required; state and reservation fields are ignored. The
+	// device is kept across fleet file reloads and, with a state directory,
+	// restarts.
+	Device        *Device `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Reason        string  `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDeviceRequest) Reset() {
+	*x = AddDeviceRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDeviceRequest) ProtoMessage() {}
+
+func (x *AddDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDeviceRequest.ProtoReflect.Descriptor instead.
+func (*AddDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddDeviceRequest) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *AddDeviceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type AddDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddDeviceResponse) Reset() {
+	*x = AddDeviceResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddDeviceResponse) ProtoMessage() {}
+
+func (x *AddDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddDeviceResponse.ProtoReflect.Descriptor instead.
+func (*AddDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AddDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type RemoveDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDeviceRequest) Reset() {
+	*x = RemoveDeviceRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDeviceRequest) ProtoMessage() {}
+
+func (x *RemoveDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDeviceRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RemoveDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *RemoveDeviceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RemoveDeviceResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Device *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	// False when the device is reserved and was retired instead; it is removed
+	// when the reservation ends.
+	Removed       bool `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDeviceResponse) Reset() {
+	*x = RemoveDeviceResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDeviceResponse) ProtoMessage() {}
+
+func (x *RemoveDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDeviceResponse.ProtoReflect.Descriptor instead.
+func (*RemoveDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RemoveDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *RemoveDeviceResponse) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
+type SetDeviceHealthRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Health   HealthState            `protobuf:"varint,2,opt,name=health,proto3,enum=devicefleet.v2.HealthState" json:"health,omitempty"`
+	// Required; shown alongside the state.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeviceHealthRequest) Reset() {
+	*x = SetDeviceHealthRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeviceHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeviceHealthRequest) ProtoMessage() {}
+
+func (x *SetDeviceHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeviceHealthRequest.ProtoReflect.Descriptor instead.
+func (*SetDeviceHealthRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetDeviceHealthRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *SetDeviceHealthRequest) GetHealth() HealthState {
+	if x != nil {
+		return x.Health
+	}
+	return HealthState_HEALTH_STATE_UNSPECIFIED
+}
+
+func (x *SetDeviceHealthRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetDeviceHealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeviceHealthResponse) Reset() {
+	*x = SetDeviceHealthResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeviceHealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeviceHealthResponse) ProtoMessage() {}
+
+func (x *SetDeviceHealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeviceHealthResponse.ProtoReflect.Descriptor instead.
+func (*SetDeviceHealthResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetDeviceHealthResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+// Drain requests name either one device or a whole device type.
+type DrainRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// Also drains devices of the type added later, until UndrainDevices.
+	DeviceType    string `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DrainRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DrainRequest) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *DrainRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DrainResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Devices []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// True once every device is free.
+	Drained       bool `protobuf:"varint,2,opt,name=drained,proto3" json:"drained,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainResponse) Reset() {
+	*x = DrainResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainResponse) ProtoMessage() {}
+
+func (x *DrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainResponse.ProtoReflect.Descriptor instead.
+func (*DrainResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DrainResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *DrainResponse) GetDrained() bool {
+	if x != nil {
+		return x.Drained
+	}
+	return false
+}
+
+type UndrainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndrainRequest) Reset() {
+	*x = UndrainRequest{}
+	mi := &file_proto_v2_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndrainRequest) ProtoMessage() {}
+
+func (x *UndrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndrainRequest.ProtoReflect.Descriptor instead.
+func (*UndrainRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UndrainRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *UndrainRequest) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *UndrainRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UndrainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndrainResponse) Reset() {
+	*x = UndrainResponse{}
+	mi := &file_proto_v2_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndrainResponse) ProtoMessage() {}
+
+func (x *UndrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndrainResponse.ProtoReflect.Descriptor instead.
+func (*UndrainResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UndrainResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+var File_proto_v2_admin_proto protoreflect.FileDescriptor
+
+const file_proto_v2_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/v2/admin.proto\x12\x0edevicefleet.v2\x1a\x15proto/v2/device.proto\"J\n" +
+	"\x13ForceReleaseRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"U\n" +
+	"\x14ForceReleaseResponse\x12=\n" +
+	"\vreservation\x18\x01 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"j\n" +
+	"\x1aTransferReservationRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x17\n" +
+	"\ato_user\x18\x02 \x01(\tR\x06toUser\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\\\n" +
+	"\x1bTransferReservationResponse\x12=\n" +
+	"\vreservation\x18\x01 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"L\n" +
+	"\x1eReleaseUserReservationsRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"b\n" +
+	"\x1fReleaseUserReservationsResponse\x12?\n" +
+	"\freservations\x18\x01 \x03(\v2\x1b.devicefleet.v2.ReservationR\freservations\"Z\n" +
+	"\x10AddDeviceRequest\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"C\n" +
+	"\x11AddDeviceResponse\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\"J\n" +
+	"\x13RemoveDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"`\n" +
+	"\x14RemoveDeviceResponse\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\x12\x18\n" +
+	"\aremoved\x18\x02 \x01(\bR\aremoved\"\x82\x01\n" +
+	"\x16SetDeviceHealthRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x123\n" +
+	"\x06health\x18\x02 \x01(\x0e2\x1b.devicefleet.v2.HealthStateR\x06health\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"I\n" +
+	"\x17SetDeviceHealthResponse\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\"d\n" +
+	"\fDrainRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"[\n" +
+	"\rDrainResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.devicefleet.v2.DeviceR\adevices\x12\x18\n" +
+	"\adrained\x18\x02 \x01(\bR\adrained\"f\n" +
+	"\x0eUndrainRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"C\n" +
+	"\x0fUndrainResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.devicefleet.v2.DeviceR\adevices2\x86\x06\n" +
+	"\fAdminService\x12Y\n" +
+	"\fForceRelease\x12#.devicefleet.v2.ForceReleaseRequest\x1a$.devicefleet.v2.ForceReleaseResponse\x12n\n" +
+	"\x13TransferReservation\x12*.devicefleet.v2.TransferReservationRequest\x1a+.devicefleet.v2.TransferReservationResponse\x12z\n" +
+	"\x17ReleaseUserReservations\x12..devicefleet.v2.ReleaseUserReservationsRequest\x1a/.devicefleet.v2.ReleaseUserReservationsResponse\x12P\n" +
+	"\tAddDevice\x12 .devicefleet.v2.AddDeviceRequest\x1a!.devicefleet.v2.AddDeviceResponse\x12Y\n" +
+	"\fRemoveDevice\x12#.devicefleet.v2.RemoveDeviceRequest\x1a$.devicefleet.v2.RemoveDeviceResponse\x12b\n" +
+	"\x0fSetDeviceHealth\x12&.devicefleet.v2.SetDeviceHealthRequest\x1a'.devicefleet.v2.SetDeviceHealthResponse\x12K\n" +
+	"\fDrainDevices\x12\x1c.devicefleet.v2.DrainRequest\x1a\x1d.devicefleet.v2.DrainResponse\x12Q\n" +
+	"\x0eUndrainDevices\x12\x1e.devicefleet.v2.UndrainRequest\x1a\x1f.devicefleet.v2.UndrainResponseBBZ@github.com/gitRasheed/FleetRPC/internal/service/proto/v2;protov2b\x06proto3"
+
+var (
+	file_proto_v2_admin_proto_rawDescOnce sync.Once
+	file_proto_v2_admin_proto_rawDescData []byte
+)
+
+func file_proto_v2_admin_proto_rawDescGZIP() []byte {
+	file_proto_v2_admin_proto_rawDescOnce.Do(func() {
+		file_proto_v2_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_v2_admin_proto_rawDesc), len(file_proto_v2_admin_proto_rawDesc)))
+	})
+	return file_proto_v2_admin_proto_rawDescData
+}
+
+var file_proto_v2_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_proto_v2_admin_proto_goTypes = []any{
+	(*ForceReleaseRequest)(nil),             // 0: devicefleet.v2.ForceReleaseRequest
+	(*ForceReleaseResponse)(nil),            // 1: devicefleet.v2.ForceReleaseResponse
+	(*TransferReservationRequest)(nil),      // 2: devicefleet.v2.TransferReservationRequest
+	(*TransferReservationResponse)(nil),     // 3: devicefleet.v2.TransferReservationResponse
+	(*ReleaseUserReservationsRequest)(nil),  // 4: devicefleet.v2.ReleaseUserReservationsRequest
+	(*ReleaseUserReservationsResponse)(nil), // 5: devicefleet.v2.ReleaseUserReservationsResponse
+	(*AddDeviceRequest)(nil),                // 6: devicefleet.v2.AddDeviceRequest
+	(*AddDeviceResponse)(nil),               // 7: devicefleet.v2.AddDeviceResponse
+	(*RemoveDeviceRequest)(nil),             // 8: devicefleet.v2.RemoveDeviceRequest
+	(*RemoveDeviceResponse)(nil),            // 9: devicefleet.v2.RemoveDeviceResponse
+	(*SetDeviceHealthRequest)(nil),          // 10: devicefleet.v2.SetDeviceHealthRequest
+	(*SetDeviceHealthResponse)(nil),         // 11: devicefleet.v2.SetDeviceHealthResponse
+	(*DrainRequest)(nil),                    // 12: devicefleet.v2.DrainRequest
+	(*DrainResponse)(nil),                   // 13: devicefleet.v2.DrainResponse
+	(*UndrainRequest)(nil),                  // 14: devicefleet.v2.UndrainRequest
+	(*UndrainResponse)(nil),                 // 15: devicefleet.v2.UndrainResponse
+	(*Reservation)(nil),                     // 16: devicefleet.v2.Reservation
+	(*Device)(nil),                          // 17: devicefleet.v2.Device
+	(HealthState)(0),                        // 18: devicefleet.v2.HealthState
+}
+var file_proto_v2_admin_proto_depIdxs = []int32{
+	16, // 0: devicefleet.v2.ForceReleaseResponse.reservation:type_name -> devicefleet.v2.Reservation
+	16, // 1: devicefleet.v2.TransferReservationResponse.reservation:type_name -> devicefleet.v2.Reservation
+	16, // 2: devicefleet.v2.ReleaseUserReservationsResponse.reservations:type_name -> devicefleet.v2.Reservation
+	17, // 3: devicefleet.v2.AddDeviceRequest.device:type_name -> devicefleet.v2.Device
+	17, // 4: devicefleet.v2.AddDeviceResponse.device:type_name -> devicefleet.v2.Device
+	17, // 5: devicefleet.v2.RemoveDeviceResponse.device:type_name -> devicefleet.v2.Device
+	18, // 6: devicefleet.v2.SetDeviceHealthRequest.health:type_name -> devicefleet.v2.HealthState
+	17, // 7: devicefleet.v2.SetDeviceHealthResponse.device:type_name -> devicefleet.v2.Device
+	17, // 8: devicefleet.v2.DrainResponse.devices:type_name -> devicefleet.v2.Device
+	17, // 9: devicefleet.v2.UndrainResponse.devices:type_name -> devicefleet.v2.Device
+	0,  // 10: devicefleet.v2.AdminService.ForceRelease:input_type -> devicefleet.v2.ForceReleaseRequest
+	2,  // 11: devicefleet.v2.AdminService.TransferReservation:input_type -> devicefleet.v2.TransferReservationRequest
+	4,  // 12: devicefleet.v2.AdminService.ReleaseUserReservations:input_type -> devicefleet.v2.ReleaseUserReservationsRequest
+	6,  // 13: devicefleet.v2.AdminService.AddDevice:input_type -> devicefleet.v2.AddDeviceRequest
+	8,  // 14: devicefleet.v2.AdminService.RemoveDevice:input_type -> devicefleet.v2.RemoveDeviceRequest
+	10, // 15: devicefleet.v2.AdminService.SetDeviceHealth:input_type -> devicefleet.v2.SetDeviceHealthRequest
+	12, // 16: devicefleet.v2.AdminService.DrainDevices:input_type -> devicefleet.v2.DrainRequest
+	14, // 17: devicefleet.v2.AdminService.UndrainDevices:input_type -> devicefleet.v2.UndrainRequest
+	1,  // 18: devicefleet.v2.AdminService.ForceRelease:output_type -> devicefleet.v2.ForceReleaseResponse
+	3,  // 19: devicefleet.v2.AdminService.TransferReservation:output_type -> devicefleet.v2.TransferReservationResponse
+	5,  // 20: devicefleet.v2.AdminService.ReleaseUserReservations:output_type -> devicefleet.v2.ReleaseUserReservationsResponse
+	7,  // 21: devicefleet.v2.AdminService.AddDevice:output_type -> devicefleet.v2.AddDeviceResponse
+	9,  // 22: devicefleet.v2.AdminService.RemoveDevice:output_type -> devicefleet.v2.RemoveDeviceResponse
+	11, // 23: devicefleet.v2.AdminService.SetDeviceHealth:output_type -> devicefleet.v2.SetDeviceHealthResponse
+	13, // 24: devicefleet.v2.AdminService.DrainDevices:output_type -> devicefleet.v2.DrainResponse
+	15, // 25: devicefleet.v2.AdminService.UndrainDevices:output_type -> devicefleet.v2.UndrainResponse
+	18, // [18:26] is the sub-list for method output_type
+	10, // [10:18] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_proto_v2_admin_proto_init() }
+func file_proto_v2_admin_proto_init() {
+	if File_proto_v2_admin_proto != nil {
+		return
+	}
+	file_proto_v2_device_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v2_admin_proto_rawDesc), len(file_proto_v2_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_v2_admin_proto_goTypes,
+		DependencyIndexes: file_proto_v2_admin_proto_depIdxs,
+		MessageInfos:      file_proto_v2_admin_proto_msgTypes,
+	}.Build()
+	File_proto_v2_admin_proto = out.File
+	file_proto_v2_admin_proto_goTypes = nil
+	file_proto_v2_admin_proto_depIdxs = nil
+}
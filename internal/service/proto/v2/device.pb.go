@@ -0,0 +1,3011 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/v2/device.proto
+
+package protov2
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReservationState int32
+
+const (
+	ReservationState_RESERVATION_STATE_UNSPECIFIED ReservationState = 0
+	ReservationState_RESERVATION_STATE_RESERVED    ReservationState = 1
+	ReservationState_RESERVATION_STATE_EXTENDED    ReservationState = 2
+	ReservationState_RESERVATION_STATE_RELEASED    ReservationState = 3
+)
+
+// Enum value maps for ReservationState.
+var (
+	ReservationState_name = map[int32]string{
+		0: "RESERVATION_STATE_UNSPECIFIED",
+		1: "RESERVATION_STATE_RESERVED",
+		2: "RESERVATION_STATE_EXTENDED",
+		3: "RESERVATION_STATE_RELEASED",
+	}
+	ReservationState_value = map[string]int32{
+		"RESERVATION_STATE_UNSPECIFIED": 0,
+		"RESERVATION_STATE_RESERVED":    1,
+		"RESERVATION_STATE_EXTENDED":    2,
+		"RESERVATION_STATE_RELEASED":    3,
+	}
+)
+
+func (x ReservationState) Enum() *ReservationState {
+	p := new(ReservationState)
+	*p = x
+	return p
+}
+
+func (x ReservationState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReservationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_device_proto_enumTypes[0].Descriptor()
+}
+
+func (ReservationState) Type() protoreflect.EnumType {
+	return &file_proto_v2_device_proto_enumTypes[0]
+}
+
+func (x ReservationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReservationState.Descriptor instead.
+func (ReservationState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{0}
+}
+
+type DeviceState int32
+
+const (
+	DeviceState_DEVICE_STATE_UNSPECIFIED DeviceState = 0
+	DeviceState_DEVICE_STATE_AVAILABLE   DeviceState = 1
+	DeviceState_DEVICE_STATE_RESERVED    DeviceState = 2
+	// Removed from the fleet file; taken out of the fleet once free.
+	DeviceState_DEVICE_STATE_RETIRING DeviceState = 3
+	DeviceState_DEVICE_STATE_REMOVED  DeviceState = 4
+	// Free but not healthy; see Device.health.
+	DeviceState_DEVICE_STATE_UNAVAILABLE DeviceState = 5
+	// Draining while its holder finishes; see Device.draining.
+	DeviceState_DEVICE_STATE_DRAINING DeviceState = 6
+	// Draining and free.
+	DeviceState_DEVICE_STATE_DRAINED DeviceState = 7
+)
+
+// Enum value maps for DeviceState.
+var (
+	DeviceState_name = map[int32]string{
+		0: "DEVICE_STATE_UNSPECIFIED",
+		1: "DEVICE_STATE_AVAILABLE",
+		2: "DEVICE_STATE_RESERVED",
+		3: "DEVICE_STATE_RETIRING",
+		4: "DEVICE_STATE_REMOVED",
+		5: "DEVICE_STATE_UNAVAILABLE",
+		6: "DEVICE_STATE_DRAINING",
+		7: "DEVICE_STATE_DRAINED",
+	}
+	DeviceState_value = map[string]int32{
+		"DEVICE_STATE_UNSPECIFIED": 0,
+		"DEVICE_STATE_AVAILABLE":   1,
+		"DEVICE_STATE_RESERVED":    2,
+		"DEVICE_STATE_RETIRING":    3,
+		"DEVICE_STATE_REMOVED":     4,
+		"DEVICE_STATE_UNAVAILABLE": 5,
+		"DEVICE_STATE_DRAINING":    6,
+		"DEVICE_STATE_DRAINED":     7,
+	}
+)
+
+func (x DeviceState) Enum() *DeviceState {
+	p := new(DeviceState)
+	*p = x
+	return p
+}
+
+func (x DeviceState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeviceState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_device_proto_enumTypes[1].Descriptor()
+}
+
+func (DeviceState) Type() protoreflect.EnumType {
+	return &file_proto_v2_device_proto_enumTypes[1]
+}
+
+func (x DeviceState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeviceState.Descriptor instead.
+func (DeviceState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{1}
+}
+
+type HealthState int32
+
+const (
+	HealthState_HEALTH_STATE_UNSPECIFIED HealthState = 0
+	HealthState_HEALTH_STATE_HEALTHY     HealthState = 1
+	HealthState_HEALTH_STATE_MAINTENANCE HealthState = 2
+	HealthState_HEALTH_STATE_OFFLINE     HealthState = 3
+	HealthState_HEALTH_STATE_QUARANTINED HealthState = 4
+)
+
+// Enum value maps for HealthState.
+var (
+	HealthState_name = map[int32]string{
+		0: "HEALTH_STATE_UNSPECIFIED",
+		1: "HEALTH_STATE_HEALTHY",
+		2: "HEALTH_STATE_MAINTENANCE",
+		3: "HEALTH_STATE_OFFLINE",
+		4: "HEALTH_STATE_QUARANTINED",
+	}
+	HealthState_value = map[string]int32{
+		"HEALTH_STATE_UNSPECIFIED": 0,
+		"HEALTH_STATE_HEALTHY":     1,
+		"HEALTH_STATE_MAINTENANCE": 2,
+		"HEALTH_STATE_OFFLINE":     3,
+		"HEALTH_STATE_QUARANTINED": 4,
+	}
+)
+
+func (x HealthState) Enum() *HealthState {
+	p := new(HealthState)
+	*p = x
+	return p
+}
+
+func (x HealthState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_device_proto_enumTypes[2].Descriptor()
+}
+
+func (HealthState) Type() protoreflect.EnumType {
+	return &file_proto_v2_device_proto_enumTypes[2]
+}
+
+func (x HealthState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthState.Descriptor instead.
+func (HealthState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{2}
+}
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	EventType_EVENT_TYPE_SNAPSHOT    EventType = 1
+	// Discard local state; a snapshot follows.
+	EventType_EVENT_TYPE_RESYNC   EventType = 2
+	EventType_EVENT_TYPE_RESERVED EventType = 3
+	EventType_EVENT_TYPE_RELEASED EventType = 4
+	EventType_EVENT_TYPE_EXPIRED  EventType = 5
+	EventType_EVENT_TYPE_EXTENDED EventType = 6
+	EventType_EVENT_TYPE_ADDED    EventType = 7
+	EventType_EVENT_TYPE_UPDATED  EventType = 8
+	EventType_EVENT_TYPE_RETIRING EventType = 9
+	EventType_EVENT_TYPE_REMOVED  EventType = 10
+	// The holder loses the device at Device.preempt_at.
+	EventType_EVENT_TYPE_PREEMPTING           EventType = 11
+	EventType_EVENT_TYPE_PREEMPTED            EventType = 12
+	EventType_EVENT_TYPE_PREEMPTION_CANCELLED EventType = 13
+	EventType_EVENT_TYPE_HEALTH_CHANGED       EventType = 14
+	// The device is reserved and will be drained when the holder is done.
+	EventType_EVENT_TYPE_DRAINING EventType = 15
+	// The device is draining and free.
+	EventType_EVENT_TYPE_DRAINED   EventType = 16
+	EventType_EVENT_TYPE_UNDRAINED EventType = 17
+	// An admin ended the reservation.
+	EventType_EVENT_TYPE_FORCE_RELEASED EventType = 18
+	// An admin gave the reservation to another user.
+	EventType_EVENT_TYPE_TRANSFERRED EventType = 19
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0:  "EVENT_TYPE_UNSPECIFIED",
+		1:  "EVENT_TYPE_SNAPSHOT",
+		2:  "EVENT_TYPE_RESYNC",
+		3:  "EVENT_TYPE_RESERVED",
+		4:  "EVENT_TYPE_RELEASED",
+		5:  "EVENT_TYPE_EXPIRED",
+		6:  "EVENT_TYPE_EXTENDED",
+		7:  "EVENT_TYPE_ADDED",
+		8:  "EVENT_TYPE_UPDATED",
+		9:  "EVENT_TYPE_RETIRING",
+		10: "EVENT_TYPE_REMOVED",
+		11: "EVENT_TYPE_PREEMPTING",
+		12: "EVENT_TYPE_PREEMPTED",
+		13: "EVENT_TYPE_PREEMPTION_CANCELLED",
+		14: "EVENT_TYPE_HEALTH_CHANGED",
+		15: "EVENT_TYPE_DRAINING",
+		16: "EVENT_TYPE_DRAINED",
+		17: "EVENT_TYPE_UNDRAINED",
+		18: "EVENT_TYPE_FORCE_RELEASED",
+		19: "EVENT_TYPE_TRANSFERRED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":          0,
+		"EVENT_TYPE_SNAPSHOT":             1,
+		"EVENT_TYPE_RESYNC":               2,
+		"EVENT_TYPE_RESERVED":             3,
+		"EVENT_TYPE_RELEASED":             4,
+		"EVENT_TYPE_EXPIRED":              5,
+		"EVENT_TYPE_EXTENDED":             6,
+		"EVENT_TYPE_ADDED":                7,
+		"EVENT_TYPE_UPDATED":              8,
+		"EVENT_TYPE_RETIRING":             9,
+		"EVENT_TYPE_REMOVED":              10,
+		"EVENT_TYPE_PREEMPTING":           11,
+		"EVENT_TYPE_PREEMPTED":            12,
+		"EVENT_TYPE_PREEMPTION_CANCELLED": 13,
+		"EVENT_TYPE_HEALTH_CHANGED":       14,
+		"EVENT_TYPE_DRAINING":             15,
+		"EVENT_TYPE_DRAINED":              16,
+		"EVENT_TYPE_UNDRAINED":            17,
+		"EVENT_TYPE_FORCE_RELEASED":       18,
+		"EVENT_TYPE_TRANSFERRED":          19,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_v2_device_proto_enumTypes[3].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_proto_v2_device_proto_enumTypes[3]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{3}
+}
+
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,4,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	ReservedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=reserved_at,json=reservedAt,proto3" json:"reserved_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	State         ReservationState       `protobuf:"varint,7,opt,name=state,proto3,enum=devicefleet.v2.ReservationState" json:"state,omitempty"`
+	Priority      int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_proto_v2_device_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Reservation) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Reservation) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Reservation) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Reservation) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *Reservation) GetReservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReservedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetState() ReservationState {
+	if x != nil {
+		return x.State
+	}
+	return ReservationState_RESERVATION_STATE_UNSPECIFIED
+}
+
+func (x *Reservation) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type Device struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type       string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Labels     map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Location   string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	State      DeviceState            `protobuf:"varint,5,opt,name=state,proto3,enum=devicefleet.v2.DeviceState" json:"state,omitempty"`
+	ReservedBy string                 `protobuf:"bytes,6,opt,name=reserved_by,json=reservedBy,proto3" json:"reserved_by,omitempty"`
+	ReservedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=reserved_at,json=reservedAt,proto3" json:"reserved_at,omitempty"`
+	ExpiresAt  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Priority   int32                  `protobuf:"varint,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	PreemptAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=preempt_at,json=preemptAt,proto3" json:"preempt_at,omitempty"`
+	// Only healthy devices are handed out.
+	Health       HealthState `protobuf:"varint,11,opt,name=health,proto3,enum=devicefleet.v2.HealthState" json:"health,omitempty"`
+	HealthReason string      `protobuf:"bytes,12,opt,name=health_reason,json=healthReason,proto3" json:"health_reason,omitempty"`
+	// Reservations reported as failed since the device was last healthy.
+	Failures int32 `protobuf:"varint,13,opt,name=failures,proto3" json:"failures,omitempty"`
+	// Not handed out; state shows whether the holder has finished.
+	Draining      bool `protobuf:"varint,14,opt,name=draining,proto3" json:"draining,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_proto_v2_device_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Device) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Device) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Device) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Device) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Device) GetState() DeviceState {
+	if x != nil {
+		return x.State
+	}
+	return DeviceState_DEVICE_STATE_UNSPECIFIED
+}
+
+func (x *Device) GetReservedBy() string {
+	if x != nil {
+		return x.ReservedBy
+	}
+	return ""
+}
+
+func (x *Device) GetReservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReservedAt
+	}
+	return nil
+}
+
+func (x *Device) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Device) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Device) GetPreemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreemptAt
+	}
+	return nil
+}
+
+func (x *Device) GetHealth() HealthState {
+	if x != nil {
+		return x.Health
+	}
+	return HealthState_HEALTH_STATE_UNSPECIFIED
+}
+
+func (x *Device) GetHealthReason() string {
+	if x != nil {
+		return x.HealthReason
+	}
+	return ""
+}
+
+func (x *Device) GetFailures() int32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *Device) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+type ReserveRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ignored when the server authenticates callers; the reservation is made
+	// for the authenticated user instead.
+	User       string               `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	DeviceType string               `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	Ttl        *durationpb.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Label selector such as "os=17, sim in (esim, physical), api>=33"; see
+	// README. A selector no device of the type can match is rejected.
+	Selector string `protobuf:"bytes,4,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Higher priorities are served first; equal priorities in arrival order.
+	Priority int32 `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	// ReserveAndWait only: reclaim a device from a lower-priority holder after
+	// the server's preemption grace period if none frees up first.
+	Preempt       bool `protobuf:"varint,6,opt,name=preempt,proto3" json:"preempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveRequest) Reset() {
+	*x = ReserveRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRequest) ProtoMessage() {}
+
+func (x *ReserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRequest.ProtoReflect.Descriptor instead.
+func (*ReserveRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReserveRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *ReserveRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ReserveRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *ReserveRequest) GetPreempt() bool {
+	if x != nil {
+		return x.Preempt
+	}
+	return false
+}
+
+type ReserveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveResponse) Reset() {
+	*x = ReserveResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveResponse) ProtoMessage() {}
+
+func (x *ReserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveResponse.ProtoReflect.Descriptor instead.
+func (*ReserveResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReserveResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ReserveUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueuePosition int32                  `protobuf:"varint,1,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
+	Reservation   *Reservation           `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveUpdate) Reset() {
+	*x = ReserveUpdate{}
+	mi := &file_proto_v2_device_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveUpdate) ProtoMessage() {}
+
+func (x *ReserveUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveUpdate.ProtoReflect.Descriptor instead.
+func (*ReserveUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReserveUpdate) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *ReserveUpdate) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ReleaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseRequest) Reset() {
+	*x = ReleaseRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRequest) ProtoMessage() {}
+
+func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReleaseRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ReleaseRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type ReleaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseResponse) Reset() {
+	*x = ReleaseResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseResponse) ProtoMessage() {}
+
+func (x *ReleaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReleaseResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ExtendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LeaseToken    string                 `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Extension     *durationpb.Duration   `protobuf:"bytes,4,opt,name=extension,proto3" json:"extension,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendRequest) Reset() {
+	*x = ExtendRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendRequest) ProtoMessage() {}
+
+func (x *ExtendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendRequest.ProtoReflect.Descriptor instead.
+func (*ExtendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExtendRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ExtendRequest) GetExtension() *durationpb.Duration {
+	if x != nil {
+		return x.Extension
+	}
+	return nil
+}
+
+type ExtendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendResponse) Reset() {
+	*x = ExtendResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendResponse) ProtoMessage() {}
+
+func (x *ExtendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendResponse.ProtoReflect.Descriptor instead.
+func (*ExtendResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExtendResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SinceRevision uint64                 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision,proto3" json:"since_revision,omitempty"`
+	Types         []string               `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	DeviceIds     []string               `protobuf:"bytes,3,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ReservedBy    string                 `protobuf:"bytes,5,opt,name=reserved_by,json=reservedBy,proto3" json:"reserved_by,omitempty"`
+	// The epoch since_revision was sent with; resuming from another epoch
+	// resyncs from a snapshot.
+	Epoch         string `protobuf:"bytes,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchRequest) GetSinceRevision() uint64 {
+	if x != nil {
+		return x.SinceRevision
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetDeviceIds() []string {
+	if x != nil {
+		return x.DeviceIds
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetReservedBy() string {
+	if x != nil {
+		return x.ReservedBy
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+type DeviceEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=devicefleet.v2.EventType" json:"type,omitempty"`
+	Revision uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Device   *Device                `protobuf:"bytes,3,opt,name=device,proto3" json:"device,omitempty"`
+	// Identifies the server run that numbered revision.
+	Epoch         string `protobuf:"bytes,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceEvent) Reset() {
+	*x = DeviceEvent{}
+	mi := &file_proto_v2_device_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceEvent) ProtoMessage() {}
+
+func (x *DeviceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceEvent.ProtoReflect.Descriptor instead.
+func (*DeviceEvent) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeviceEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *DeviceEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *DeviceEvent) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+type ListDevicesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; empty fields match every device and set fields must all match.
+	Types  []string          `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	States []DeviceState     `protobuf:"varint,2,rep,packed,name=states,proto3,enum=devicefleet.v2.DeviceState" json:"states,omitempty"`
+	Labels map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Health []HealthState     `protobuf:"varint,6,rep,packed,name=health,proto3,enum=devicefleet.v2.HealthState" json:"health,omitempty"`
+	// At most 500; zero means 50.
+	PageSize int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from the previous page.
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListDevicesRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *ListDevicesRequest) GetStates() []DeviceState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ListDevicesRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListDevicesRequest) GetHealth() []HealthState {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *ListDevicesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDevicesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDevicesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ordered by device ID.
+	Devices []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ListDevicesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceRequest) Reset() {
+	*x = GetDeviceRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceRequest) ProtoMessage() {}
+
+func (x *GetDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceRequest.ProtoReflect.Descriptor instead.
+func (*GetDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetDeviceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type GetDeviceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeviceResponse) Reset() {
+	*x = GetDeviceResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeviceResponse) ProtoMessage() {}
+
+func (x *GetDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeviceResponse.ProtoReflect.Descriptor instead.
+func (*GetDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetDeviceResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type DeviceRequirement struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeviceType string                 `protobuf:"bytes,1,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	// Same syntax as ReserveRequest.selector.
+	Selector      string `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceRequirement) Reset() {
+	*x = DeviceRequirement{}
+	mi := &file_proto_v2_device_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceRequirement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceRequirement) ProtoMessage() {}
+
+func (x *DeviceRequirement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceRequirement.ProtoReflect.Descriptor instead.
+func (*DeviceRequirement) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeviceRequirement) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *DeviceRequirement) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+type ReserveBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// One device per requirement, reserved all together or not at all.
+	Devices       []*DeviceRequirement `protobuf:"bytes,2,rep,name=devices,proto3" json:"devices,omitempty"`
+	Ttl           *durationpb.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveBatchRequest) Reset() {
+	*x = ReserveBatchRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveBatchRequest) ProtoMessage() {}
+
+func (x *ReserveBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveBatchRequest.ProtoReflect.Descriptor instead.
+func (*ReserveBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReserveBatchRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ReserveBatchRequest) GetDevices() []*DeviceRequirement {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ReserveBatchRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+type ReserveBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Shared by every reservation in the group; releases or extends them together.
+	GroupLease string `protobuf:"bytes,1,opt,name=group_lease,json=groupLease,proto3" json:"group_lease,omitempty"`
+	// In requirement order.
+	Reservations  []*Reservation `protobuf:"bytes,2,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveBatchResponse) Reset() {
+	*x = ReserveBatchResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveBatchResponse) ProtoMessage() {}
+
+func (x *ReserveBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveBatchResponse.ProtoReflect.Descriptor instead.
+func (*ReserveBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReserveBatchResponse) GetGroupLease() string {
+	if x != nil {
+		return x.GroupLease
+	}
+	return ""
+}
+
+func (x *ReserveBatchResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+type ReleaseBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupLease    string                 `protobuf:"bytes,1,opt,name=group_lease,json=groupLease,proto3" json:"group_lease,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseBatchRequest) Reset() {
+	*x = ReleaseBatchRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseBatchRequest) ProtoMessage() {}
+
+func (x *ReleaseBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseBatchRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReleaseBatchRequest) GetGroupLease() string {
+	if x != nil {
+		return x.GroupLease
+	}
+	return ""
+}
+
+type ReleaseBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservations  []*Reservation         `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseBatchResponse) Reset() {
+	*x = ReleaseBatchResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseBatchResponse) ProtoMessage() {}
+
+func (x *ReleaseBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseBatchResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReleaseBatchResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+type ExtendBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupLease    string                 `protobuf:"bytes,1,opt,name=group_lease,json=groupLease,proto3" json:"group_lease,omitempty"`
+	Extension     *durationpb.Duration   `protobuf:"bytes,2,opt,name=extension,proto3" json:"extension,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendBatchRequest) Reset() {
+	*x = ExtendBatchRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendBatchRequest) ProtoMessage() {}
+
+func (x *ExtendBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendBatchRequest.ProtoReflect.Descriptor instead.
+func (*ExtendBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExtendBatchRequest) GetGroupLease() string {
+	if x != nil {
+		return x.GroupLease
+	}
+	return ""
+}
+
+func (x *ExtendBatchRequest) GetExtension() *durationpb.Duration {
+	if x != nil {
+		return x.Extension
+	}
+	return nil
+}
+
+type ExtendBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservations  []*Reservation         `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendBatchResponse) Reset() {
+	*x = ExtendBatchResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendBatchResponse) ProtoMessage() {}
+
+func (x *ExtendBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendBatchResponse.ProtoReflect.Descriptor instead.
+func (*ExtendBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExtendBatchResponse) GetReservations() []*Reservation {
+	if x != nil {
+		return x.Reservations
+	}
+	return nil
+}
+
+type QuotaUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "user", "team" or "type" (a user's devices of one type).
+	Scope string `protobuf:"bytes,1,opt,name=scope,proto3" json:"scope,omitempty"`
+	// The user or team the quota applies to.
+	Subject    string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	DeviceType string `protobuf:"bytes,3,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	InUse      int32  `protobuf:"varint,4,opt,name=in_use,json=inUse,proto3" json:"in_use,omitempty"`
+	// Zero when unlimited.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuotaUsage) Reset() {
+	*x = QuotaUsage{}
+	mi := &file_proto_v2_device_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuotaUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaUsage) ProtoMessage() {}
+
+func (x *QuotaUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaUsage.ProtoReflect.Descriptor instead.
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *QuotaUsage) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *QuotaUsage) GetInUse() int32 {
+	if x != nil {
+		return x.InUse
+	}
+	return 0
+}
+
+func (x *QuotaUsage) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetQuotaUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists every user and team holding devices or with a team quota.
+	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuotaUsageRequest) Reset() {
+	*x = GetQuotaUsageRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuotaUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuotaUsageRequest) ProtoMessage() {}
+
+func (x *GetQuotaUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuotaUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetQuotaUsageRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type GetQuotaUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usage         []*QuotaUsage          `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuotaUsageResponse) Reset() {
+	*x = GetQuotaUsageResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuotaUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuotaUsageResponse) ProtoMessage() {}
+
+func (x *GetQuotaUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuotaUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetQuotaUsageResponse) GetUsage() []*QuotaUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// Booking holds a device for a future window. When the window starts it
+// becomes an ordinary reservation under lease_token, ending at end_time.
+type Booking struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeviceId   string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType string                 `protobuf:"bytes,3,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	User       string                 `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	StartTime  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// Only set in CreateBookingResponse.
+	LeaseToken    string `protobuf:"bytes,7,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Booking) Reset() {
+	*x = Booking{}
+	mi := &file_proto_v2_device_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Booking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Booking) ProtoMessage() {}
+
+func (x *Booking) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Booking.ProtoReflect.Descriptor instead.
+func (*Booking) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Booking) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Booking) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Booking) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Booking) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Booking) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Booking) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Booking) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+type CreateBookingRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	User     string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// Must be in the future; the window may be at most the server's max lease.
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBookingRequest) Reset() {
+	*x = CreateBookingRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBookingRequest) ProtoMessage() {}
+
+func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBookingRequest.ProtoReflect.Descriptor instead.
+func (*CreateBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateBookingRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *CreateBookingRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *CreateBookingRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CreateBookingRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type CreateBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Booking       *Booking               `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBookingResponse) Reset() {
+	*x = CreateBookingResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBookingResponse) ProtoMessage() {}
+
+func (x *CreateBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBookingResponse.ProtoReflect.Descriptor instead.
+func (*CreateBookingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateBookingResponse) GetBooking() *Booking {
+	if x != nil {
+		return x.Booking
+	}
+	return nil
+}
+
+type ListBookingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; empty fields match every booking.
+	DeviceId      string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	User          string `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingsRequest) Reset() {
+	*x = ListBookingsRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingsRequest) ProtoMessage() {}
+
+func (x *ListBookingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingsRequest.ProtoReflect.Descriptor instead.
+func (*ListBookingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListBookingsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ListBookingsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type ListBookingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ordered by start time.
+	Bookings      []*Booking `protobuf:"bytes,1,rep,name=bookings,proto3" json:"bookings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBookingsResponse) Reset() {
+	*x = ListBookingsResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBookingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBookingsResponse) ProtoMessage() {}
+
+func (x *ListBookingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBookingsResponse.ProtoReflect.Descriptor instead.
+func (*ListBookingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListBookingsResponse) GetBookings() []*Booking {
+	if x != nil {
+		return x.Bookings
+	}
+	return nil
+}
+
+type CancelBookingRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId string                 `protobuf:"bytes,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	// Either the booking's lease token or its user.
+	LeaseToken    string `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingRequest) Reset() {
+	*x = CancelBookingRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingRequest) ProtoMessage() {}
+
+func (x *CancelBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingRequest.ProtoReflect.Descriptor instead.
+func (*CancelBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CancelBookingRequest) GetBookingId() string {
+	if x != nil {
+		return x.BookingId
+	}
+	return ""
+}
+
+func (x *CancelBookingRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *CancelBookingRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type CancelBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Booking       *Booking               `protobuf:"bytes,1,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingResponse) Reset() {
+	*x = CancelBookingResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingResponse) ProtoMessage() {}
+
+func (x *CancelBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingResponse.ProtoReflect.Descriptor instead.
+func (*CancelBookingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CancelBookingResponse) GetBooking() *Booking {
+	if x != nil {
+		return x.Booking
+	}
+	return nil
+}
+
+type ReportDeviceFailureRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The device must be reserved by the reporter, identified by lease_token
+	// or user. Each reservation counts once towards automatic quarantine.
+	DeviceId      string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LeaseToken    string `protobuf:"bytes,2,opt,name=lease_token,json=leaseToken,proto3" json:"lease_token,omitempty"`
+	User          string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportDeviceFailureRequest) Reset() {
+	*x = ReportDeviceFailureRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportDeviceFailureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportDeviceFailureRequest) ProtoMessage() {}
+
+func (x *ReportDeviceFailureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportDeviceFailureRequest.ProtoReflect.Descriptor instead.
+func (*ReportDeviceFailureRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReportDeviceFailureRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ReportDeviceFailureRequest) GetLeaseToken() string {
+	if x != nil {
+		return x.LeaseToken
+	}
+	return ""
+}
+
+func (x *ReportDeviceFailureRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ReportDeviceFailureRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReportDeviceFailureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportDeviceFailureResponse) Reset() {
+	*x = ReportDeviceFailureResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportDeviceFailureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportDeviceFailureResponse) ProtoMessage() {}
+
+func (x *ReportDeviceFailureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportDeviceFailureResponse.ProtoReflect.Descriptor instead.
+func (*ReportDeviceFailureResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReportDeviceFailureResponse) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type GetDrainStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,2,opt,name=device_type,json=deviceType,proto3" json:"device_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDrainStatusRequest) Reset() {
+	*x = GetDrainStatusRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDrainStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDrainStatusRequest) ProtoMessage() {}
+
+func (x *GetDrainStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDrainStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDrainStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetDrainStatusRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetDrainStatusRequest) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+type GetDrainStatusResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Devices []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// True when every device is draining and free.
+	Drained       bool `protobuf:"varint,2,opt,name=drained,proto3" json:"drained,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDrainStatusResponse) Reset() {
+	*x = GetDrainStatusResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDrainStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDrainStatusResponse) ProtoMessage() {}
+
+func (x *GetDrainStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDrainStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDrainStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetDrainStatusResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *GetDrainStatusResponse) GetDrained() bool {
+	if x != nil {
+		return x.Drained
+	}
+	return false
+}
+
+type WhoAmIRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WhoAmIRequest) Reset() {
+	*x = WhoAmIRequest{}
+	mi := &file_proto_v2_device_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhoAmIRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhoAmIRequest) ProtoMessage() {}
+
+func (x *WhoAmIRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhoAmIRequest.ProtoReflect.Descriptor instead.
+func (*WhoAmIRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{36}
+}
+
+type WhoAmIResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the server does not authenticate callers.
+	Authenticated bool     `protobuf:"varint,1,opt,name=authenticated,proto3" json:"authenticated,omitempty"`
+	User          string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Groups        []string `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WhoAmIResponse) Reset() {
+	*x = WhoAmIResponse{}
+	mi := &file_proto_v2_device_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhoAmIResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhoAmIResponse) ProtoMessage() {}
+
+func (x *WhoAmIResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v2_device_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhoAmIResponse.ProtoReflect.Descriptor instead.
+func (*WhoAmIResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v2_device_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *WhoAmIResponse) GetAuthenticated() bool {
+	if x != nil {
+		return x.Authenticated
+	}
+	return false
+}
+
+func (x *WhoAmIResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *WhoAmIResponse) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+var File_proto_v2_device_proto protoreflect.FileDescriptor
+
+const file_proto_v2_device_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/v2/device.proto\x12\x0edevicefleet.v2\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcc\x02\n" +
+	"\vReservation\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x1f\n" +
+	"\vlease_token\x18\x04 \x01(\tR\n" +
+	"leaseToken\x12;\n" +
+	"\vreserved_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reservedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x126\n" +
+	"\x05state\x18\a \x01(\x0e2 .devicefleet.v2.ReservationStateR\x05state\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\"\xf4\x04\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12:\n" +
+	"\x06labels\x18\x03 \x03(\v2\".devicefleet.v2.Device.LabelsEntryR\x06labels\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x121\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x1b.devicefleet.v2.DeviceStateR\x05state\x12\x1f\n" +
+	"\vreserved_by\x18\x06 \x01(\tR\n" +
+	"reservedBy\x12;\n" +
+	"\vreserved_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"reservedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1a\n" +
+	"\bpriority\x18\t \x01(\x05R\bpriority\x129\n" +
+	"\n" +
+	"preempt_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tpreemptAt\x123\n" +
+	"\x06health\x18\v \x01(\x0e2\x1b.devicefleet.v2.HealthStateR\x06health\x12#\n" +
+	"\rhealth_reason\x18\f \x01(\tR\fhealthReason\x12\x1a\n" +
+	"\bfailures\x18\r \x01(\x05R\bfailures\x12\x1a\n" +
+	"\bdraining\x18\x0e \x01(\bR\bdraining\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc4\x01\n" +
+	"\x0eReserveRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1a\n" +
+	"\bselector\x18\x04 \x01(\tR\bselector\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x18\n" +
+	"\apreempt\x18\x06 \x01(\bR\apreempt\"P\n" +
+	"\x0fReserveResponse\x12=\n" +
+	"\vreservation\x18\x01 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"u\n" +
+	"\rReserveUpdate\x12%\n" +
+	"\x0equeue_position\x18\x01 \x01(\x05R\rqueuePosition\x12=\n" +
+	"\vreservation\x18\x02 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"b\n" +
+	"\x0eReleaseRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\"P\n" +
+	"\x0fReleaseResponse\x12=\n" +
+	"\vreservation\x18\x01 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"\x9a\x01\n" +
+	"\rExtendRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x127\n" +
+	"\textension\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\textension\"O\n" +
+	"\x0eExtendResponse\x12=\n" +
+	"\vreservation\x18\x01 \x01(\v2\x1b.devicefleet.v2.ReservationR\vreservation\"\x9e\x02\n" +
+	"\fWatchRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x04R\rsinceRevision\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05types\x12\x1d\n" +
+	"\n" +
+	"device_ids\x18\x03 \x03(\tR\tdeviceIds\x12@\n" +
+	"\x06labels\x18\x04 \x03(\v2(.devicefleet.v2.WatchRequest.LabelsEntryR\x06labels\x12\x1f\n" +
+	"\vreserved_by\x18\x05 \x01(\tR\n" +
+	"reservedBy\x12\x14\n" +
+	"\x05epoch\x18\x06 \x01(\tR\x05epoch\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9e\x01\n" +
+	"\vDeviceEvent\x12-\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x19.devicefleet.v2.EventTypeR\x04type\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12.\n" +
+	"\x06device\x18\x03 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\x12\x14\n" +
+	"\x05epoch\x18\x04 \x01(\tR\x05epoch\"\xd3\x02\n" +
+	"\x12ListDevicesRequest\x12\x14\n" +
+	"\x05types\x18\x01 \x03(\tR\x05types\x123\n" +
+	"\x06states\x18\x02 \x03(\x0e2\x1b.devicefleet.v2.DeviceStateR\x06states\x12F\n" +
+	"\x06labels\x18\x03 \x03(\v2..devicefleet.v2.ListDevicesRequest.LabelsEntryR\x06labels\x123\n" +
+	"\x06health\x18\x06 \x03(\x0e2\x1b.devicefleet.v2.HealthStateR\x06health\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"o\n" +
+	"\x13ListDevicesResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.devicefleet.v2.DeviceR\adevices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"/\n" +
+	"\x10GetDeviceRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"C\n" +
+	"\x11GetDeviceResponse\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\"P\n" +
+	"\x11DeviceRequirement\x12\x1f\n" +
+	"\vdevice_type\x18\x01 \x01(\tR\n" +
+	"deviceType\x12\x1a\n" +
+	"\bselector\x18\x02 \x01(\tR\bselector\"\x93\x01\n" +
+	"\x13ReserveBatchRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12;\n" +
+	"\adevices\x18\x02 \x03(\v2!.devicefleet.v2.DeviceRequirementR\adevices\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\"x\n" +
+	"\x14ReserveBatchResponse\x12\x1f\n" +
+	"\vgroup_lease\x18\x01 \x01(\tR\n" +
+	"groupLease\x12?\n" +
+	"\freservations\x18\x02 \x03(\v2\x1b.devicefleet.v2.ReservationR\freservations\"6\n" +
+	"\x13ReleaseBatchRequest\x12\x1f\n" +
+	"\vgroup_lease\x18\x01 \x01(\tR\n" +
+	"groupLease\"W\n" +
+	"\x14ReleaseBatchResponse\x12?\n" +
+	"\freservations\x18\x01 \x03(\v2\x1b.devicefleet.v2.ReservationR\freservations\"n\n" +
+	"\x12ExtendBatchRequest\x12\x1f\n" +
+	"\vgroup_lease\x18\x01 \x01(\tR\n" +
+	"groupLease\x127\n" +
+	"\textension\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\textension\"V\n" +
+	"\x13ExtendBatchResponse\x12?\n" +
+	"\freservations\x18\x01 \x03(\v2\x1b.devicefleet.v2.ReservationR\freservations\"\x8a\x01\n" +
+	"\n" +
+	"QuotaUsage\x12\x14\n" +
+	"\x05scope\x18\x01 \x01(\tR\x05scope\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x12\x1f\n" +
+	"\vdevice_type\x18\x03 \x01(\tR\n" +
+	"deviceType\x12\x15\n" +
+	"\x06in_use\x18\x04 \x01(\x05R\x05inUse\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"*\n" +
+	"\x14GetQuotaUsageRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\"I\n" +
+	"\x15GetQuotaUsageResponse\x120\n" +
+	"\x05usage\x18\x01 \x03(\v2\x1a.devicefleet.v2.QuotaUsageR\x05usage\"\xfe\x01\n" +
+	"\aBooking\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x03 \x01(\tR\n" +
+	"deviceType\x12\x12\n" +
+	"\x04user\x18\x04 \x01(\tR\x04user\x129\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1f\n" +
+	"\vlease_token\x18\a \x01(\tR\n" +
+	"leaseToken\"\xb9\x01\n" +
+	"\x14CreateBookingRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x129\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"J\n" +
+	"\x15CreateBookingResponse\x121\n" +
+	"\abooking\x18\x01 \x01(\v2\x17.devicefleet.v2.BookingR\abooking\"F\n" +
+	"\x13ListBookingsRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"K\n" +
+	"\x14ListBookingsResponse\x123\n" +
+	"\bbookings\x18\x01 \x03(\v2\x17.devicefleet.v2.BookingR\bbookings\"j\n" +
+	"\x14CancelBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\tR\tbookingId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\"J\n" +
+	"\x15CancelBookingResponse\x121\n" +
+	"\abooking\x18\x01 \x01(\v2\x17.devicefleet.v2.BookingR\abooking\"\x86\x01\n" +
+	"\x1aReportDeviceFailureRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vlease_token\x18\x02 \x01(\tR\n" +
+	"leaseToken\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"M\n" +
+	"\x1bReportDeviceFailureResponse\x12.\n" +
+	"\x06device\x18\x01 \x01(\v2\x16.devicefleet.v2.DeviceR\x06device\"U\n" +
+	"\x15GetDrainStatusRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vdevice_type\x18\x02 \x01(\tR\n" +
+	"deviceType\"d\n" +
+	"\x16GetDrainStatusResponse\x120\n" +
+	"\adevices\x18\x01 \x03(\v2\x16.devicefleet.v2.DeviceR\adevices\x12\x18\n" +
+	"\adrained\x18\x02 \x01(\bR\adrained\"\x0f\n" +
+	"\rWhoAmIRequest\"b\n" +
+	"\x0eWhoAmIResponse\x12$\n" +
+	"\rauthenticated\x18\x01 \x01(\bR\rauthenticated\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x16\n" +
+	"\x06groups\x18\x03 \x03(\tR\x06groups*\x95\x01\n" +
+	"\x10ReservationState\x12!\n" +
+	"\x1dRESERVATION_STATE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aRESERVATION_STATE_RESERVED\x10\x01\x12\x1e\n" +
+	"\x1aRESERVATION_STATE_EXTENDED\x10\x02\x12\x1e\n" +
+	"\x1aRESERVATION_STATE_RELEASED\x10\x03*\xea\x01\n" +
+	"\vDeviceState\x12\x1c\n" +
+	"\x18DEVICE_STATE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DEVICE_STATE_AVAILABLE\x10\x01\x12\x19\n" +
+	"\x15DEVICE_STATE_RESERVED\x10\x02\x12\x19\n" +
+	"\x15DEVICE_STATE_RETIRING\x10\x03\x12\x18\n" +
+	"\x14DEVICE_STATE_REMOVED\x10\x04\x12\x1c\n" +
+	"\x18DEVICE_STATE_UNAVAILABLE\x10\x05\x12\x19\n" +
+	"\x15DEVICE_STATE_DRAINING\x10\x06\x12\x18\n" +
+	"\x14DEVICE_STATE_DRAINED\x10\a*\x9b\x01\n" +
+	"\vHealthState\x12\x1c\n" +
+	"\x18HEALTH_STATE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14HEALTH_STATE_HEALTHY\x10\x01\x12\x1c\n" +
+	"\x18HEALTH_STATE_MAINTENANCE\x10\x02\x12\x18\n" +
+	"\x14HEALTH_STATE_OFFLINE\x10\x03\x12\x1c\n" +
+	"\x18HEALTH_STATE_QUARANTINED\x10\x04*\x98\x04\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13EVENT_TYPE_SNAPSHOT\x10\x01\x12\x15\n" +
+	"\x11EVENT_TYPE_RESYNC\x10\x02\x12\x17\n" +
+	"\x13EVENT_TYPE_RESERVED\x10\x03\x12\x17\n" +
+	"\x13EVENT_TYPE_RELEASED\x10\x04\x12\x16\n" +
+	"\x12EVENT_TYPE_EXPIRED\x10\x05\x12\x17\n" +
+	"\x13EVENT_TYPE_EXTENDED\x10\x06\x12\x14\n" +
+	"\x10EVENT_TYPE_ADDED\x10\a\x12\x16\n" +
+	"\x12EVENT_TYPE_UPDATED\x10\b\x12\x17\n" +
+	"\x13EVENT_TYPE_RETIRING\x10\t\x12\x16\n" +
+	"\x12EVENT_TYPE_REMOVED\x10\n" +
+	"\x12\x19\n" +
+	"\x15EVENT_TYPE_PREEMPTING\x10\v\x12\x18\n" +
+	"\x14EVENT_TYPE_PREEMPTED\x10\f\x12#\n" +
+	"\x1fEVENT_TYPE_PREEMPTION_CANCELLED\x10\r\x12\x1d\n" +
+	"\x19EVENT_TYPE_HEALTH_CHANGED\x10\x0e\x12\x17\n" +
+	"\x13EVENT_TYPE_DRAINING\x10\x0f\x12\x16\n" +
+	"\x12EVENT_TYPE_DRAINED\x10\x10\x12\x18\n" +
+	"\x14EVENT_TYPE_UNDRAINED\x10\x11\x12\x1d\n" +
+	"\x19EVENT_TYPE_FORCE_RELEASED\x10\x12\x12\x1a\n" +
+	"\x16EVENT_TYPE_TRANSFERRED\x10\x132\xee\v\n" +
+	"\rDeviceService\x12P\n" +
+	"\rReserveDevice\x12\x1e.devicefleet.v2.ReserveRequest\x1a\x1f.devicefleet.v2.ReserveResponse\x12Q\n" +
+	"\x0eReserveAndWait\x12\x1e.devicefleet.v2.ReserveRequest\x1a\x1d.devicefleet.v2.ReserveUpdate0\x01\x12P\n" +
+	"\rReleaseDevice\x12\x1e.devicefleet.v2.ReleaseRequest\x1a\x1f.devicefleet.v2.ReleaseResponse\x12R\n" +
+	"\x11ExtendReservation\x12\x1d.devicefleet.v2.ExtendRequest\x1a\x1e.devicefleet.v2.ExtendResponse\x12K\n" +
+	"\fWatchDevices\x12\x1c.devicefleet.v2.WatchRequest\x1a\x1b.devicefleet.v2.DeviceEvent0\x01\x12V\n" +
+	"\vListDevices\x12\".devicefleet.v2.ListDevicesRequest\x1a#.devicefleet.v2.ListDevicesResponse\x12P\n" +
+	"\tGetDevice\x12 .devicefleet.v2.GetDeviceRequest\x1a!.devicefleet.v2.GetDeviceResponse\x12Y\n" +
+	"\fReserveBatch\x12#.devicefleet.v2.ReserveBatchRequest\x1a$.devicefleet.v2.ReserveBatchResponse\x12Y\n" +
+	"\fReleaseBatch\x12#.devicefleet.v2.ReleaseBatchRequest\x1a$.devicefleet.v2.ReleaseBatchResponse\x12V\n" +
+	"\vExtendBatch\x12\".devicefleet.v2.ExtendBatchRequest\x1a#.devicefleet.v2.ExtendBatchResponse\x12\\\n" +
+	"\rGetQuotaUsage\x12$.devicefleet.v2.GetQuotaUsageRequest\x1a%.devicefleet.v2.GetQuotaUsageResponse\x12\\\n" +
+	"\rCreateBooking\x12$.devicefleet.v2.CreateBookingRequest\x1a%.devicefleet.v2.CreateBookingResponse\x12Y\n" +
+	"\fListBookings\x12#.devicefleet.v2.ListBookingsRequest\x1a$.devicefleet.v2.ListBookingsResponse\x12\\\n" +
+	"\rCancelBooking\x12$.devicefleet.v2.CancelBookingRequest\x1a%.devicefleet.v2.CancelBookingResponse\x12n\n" +
+	"\x13ReportDeviceFailure\x12*.devicefleet.v2.ReportDeviceFailureRequest\x1a+.devicefleet.v2.ReportDeviceFailureResponse\x12_\n" +
+	"\x0eGetDrainStatus\x12%.devicefleet.v2.GetDrainStatusRequest\x1a&.devicefleet.v2.GetDrainStatusResponse\x12G\n" +
+	"\x06WhoAmI\x12\x1d.devicefleet.v2.WhoAmIRequest\x1a\x1e.devicefleet.v2.WhoAmIResponseBBZ@github.com/gitRasheed/FleetRPC/internal/service/proto/v2;protov2b\x06proto3"
+
+var (
+	file_proto_v2_device_proto_rawDescOnce sync.Once
+	file_proto_v2_device_proto_rawDescData []byte
+)
+
+func file_proto_v2_device_proto_rawDescGZIP() []byte {
+	file_proto_v2_device_proto_rawDescOnce.Do(func() {
+		file_proto_v2_device_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_v2_device_proto_rawDesc), len(file_proto_v2_device_proto_rawDesc)))
+	})
+	return file_proto_v2_device_proto_rawDescData
+}
+
+var file_proto_v2_device_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_v2_device_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_proto_v2_device_proto_goTypes = []any{
+	(ReservationState)(0),               // 0: devicefleet.v2.ReservationState
+	(DeviceState)(0),                    // 1: devicefleet.v2.DeviceState
+	(HealthState)(0),                    // 2: devicefleet.v2.HealthState
+	(EventType)(0),                      // 3: devicefleet.v2.EventType
+	(*Reservation)(nil),                 // 4: devicefleet.v2.Reservation
+	(*Device)(nil),                      // 5: devicefleet.v2.Device
+	(*ReserveRequest)(nil),              // 6: devicefleet.v2.ReserveRequest
+	(*ReserveResponse)(nil),             // 7: devicefleet.v2.ReserveResponse
+	(*ReserveUpdate)(nil),               // 8: devicefleet.v2.ReserveUpdate
+	(*ReleaseRequest)(nil),              // 9: devicefleet.v2.ReleaseRequest
+	(*ReleaseResponse)(nil),             // 10: devicefleet.v2.ReleaseResponse
+	(*ExtendRequest)(nil),               // 11: devicefleet.v2.ExtendRequest
+	(*ExtendResponse)(nil),              // 12: devicefleet.v2.ExtendResponse
+	(*WatchRequest)(nil),                // 13: devicefleet.v2.WatchRequest
+	(*DeviceEvent)(nil),                 // 14: devicefleet.v2.DeviceEvent
+	(*ListDevicesRequest)(nil),          // 15: devicefleet.v2.ListDevicesRequest
+	(*ListDevicesResponse)(nil),         // 16: devicefleet.v2.ListDevicesResponse
+	(*GetDeviceRequest)(nil),            // 17: devicefleet.v2.GetDeviceRequest
+	(*GetDeviceResponse)(nil),           // 18: devicefleet.v2.GetDeviceResponse
+	(*DeviceRequirement)(nil),           // 19: devicefleet.v2.DeviceRequirement
+	(*ReserveBatchRequest)(nil),         // 20: devicefleet.v2.ReserveBatchRequest
+	(*ReserveBatchResponse)(nil),        // 21: devicefleet.v2.ReserveBatchResponse
+	(*ReleaseBatchRequest)(nil),         // 22: devicefleet.v2.ReleaseBatchRequest
+	(*ReleaseBatchResponse)(nil),        // 23: devicefleet.v2.ReleaseBatchResponse
+	(*ExtendBatchRequest)(nil),          // 24: devicefleet.v2.ExtendBatchRequest
+	(*ExtendBatchResponse)(nil),         // 25: devicefleet.v2.ExtendBatchResponse
+	(*QuotaUsage)(nil),                  // 26: devicefleet.v2.QuotaUsage
+	(*GetQuotaUsageRequest)(nil),        // 27: devicefleet.v2.GetQuotaUsageRequest
+	(*GetQuotaUsageResponse)(nil),       // 28: devicefleet.v2.GetQuotaUsageResponse
+	(*Booking)(nil),                     // 29: devicefleet.v2.Booking
+	(*CreateBookingRequest)(nil),        // 30: devicefleet.v2.CreateBookingRequest
+	(*CreateBookingResponse)(nil),       // 31: devicefleet.v2.CreateBookingResponse
+	(*ListBookingsRequest)(nil),         // 32: devicefleet.v2.ListBookingsRequest
+	(*ListBookingsResponse)(nil),        // 33: devicefleet.v2.ListBookingsResponse
+	(*CancelBookingRequest)(nil),        // 34: devicefleet.v2.CancelBookingRequest
+	(*CancelBookingResponse)(nil),       // 35: devicefleet.v2.CancelBookingResponse
+	(*ReportDeviceFailureRequest)(nil),  // 36: devicefleet.v2.ReportDeviceFailureRequest
+	(*ReportDeviceFailureResponse)(nil), // 37: devicefleet.v2.ReportDeviceFailureResponse
+	(*GetDrainStatusRequest)(nil),       // 38: devicefleet.v2.GetDrainStatusRequest
+	(*GetDrainStatusResponse)(nil),      // 39: devicefleet.v2.GetDrainStatusResponse
+	(*WhoAmIRequest)(nil),               // 40: devicefleet.v2.WhoAmIRequest
+	(*WhoAmIResponse)(nil),              // 41: devicefleet.v2.WhoAmIResponse
+	nil,                                 // 42: devicefleet.v2.Device.LabelsEntry
+	nil,                                 // 43: devicefleet.v2.WatchRequest.LabelsEntry
+	nil,                                 // 44: devicefleet.v2.ListDevicesRequest.LabelsEntry
+	(*timestamppb.Timestamp)(nil),       // 45: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),         // 46: google.protobuf.Duration
+}
+var file_proto_v2_device_proto_depIdxs = []int32{
+	45, // 0: devicefleet.v2.Reservation.reserved_at:type_name -> google.protobuf.Timestamp
+	45, // 1: devicefleet.v2.Reservation.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: devicefleet.v2.Reservation.state:type_name -> devicefleet.v2.ReservationState
+	42, // 3: devicefleet.v2.Device.labels:type_name -> devicefleet.v2.Device.LabelsEntry
+	1,  // 4: devicefleet.v2.Device.state:type_name -> devicefleet.v2.DeviceState
+	45, // 5: devicefleet.v2.Device.reserved_at:type_name -> google.protobuf.Timestamp
+	45, // 6: devicefleet.v2.Device.expires_at:type_name -> google.protobuf.Timestamp
+	45, // 7: devicefleet.v2.Device.preempt_at:type_name -> google.protobuf.Timestamp
+	2,  // 8: devicefleet.v2.Device.health:type_name -> devicefleet.v2.HealthState
+	46, // 9: devicefleet.v2.ReserveRequest.ttl:type_name -> google.protobuf.Duration
+	4,  // 10: devicefleet.v2.ReserveResponse.reservation:type_name -> devicefleet.v2.Reservation
+	4,  // 11: devicefleet.v2.ReserveUpdate.reservation:type_name -> devicefleet.v2.Reservation
+	4,  // 12: devicefleet.v2.ReleaseResponse.reservation:type_name -> devicefleet.v2.Reservation
+	46, // 13: devicefleet.v2.ExtendRequest.extension:type_name -> google.protobuf.Duration
+	4,  // 14: devicefleet.v2.ExtendResponse.reservation:type_name -> devicefleet.v2.Reservation
+	43, // 15: devicefleet.v2.WatchRequest.labels:type_name -> devicefleet.v2.WatchRequest.LabelsEntry
+	3,  // 16: devicefleet.v2.DeviceEvent.type:type_name -> devicefleet.v2.EventType
+	5,  // 17: devicefleet.v2.DeviceEvent.device:type_name -> devicefleet.v2.Device
+	1,  // 18: devicefleet.v2.ListDevicesRequest.states:type_name -> devicefleet.v2.DeviceState
+	44, // 19: devicefleet.v2.ListDevicesRequest.labels:type_name -> devicefleet.v2.ListDevicesRequest.LabelsEntry
+	2,  // 20: devicefleet.v2.ListDevicesRequest.health:type_name -> devicefleet.v2.HealthState
+	5,  // 21: devicefleet.v2.ListDevicesResponse.devices:type_name -> devicefleet.v2.Device
+	5,  // 22: devicefleet.v2.GetDeviceResponse.device:type_name -> devicefleet.v2.Device
+	19, // 23: devicefleet.v2.ReserveBatchRequest.devices:type_name -> devicefleet.v2.DeviceRequirement
+	46, // 24: devicefleet.v2.ReserveBatchRequest.ttl:type_name -> google.protobuf.Duration
+	4,  // 25: devicefleet.v2.ReserveBatchResponse.reservations:type_name -> devicefleet.v2.Reservation
+	4,  // 26: devicefleet.v2.ReleaseBatchResponse.reservations:type_name -> devicefleet.v2.Reservation
+	46, // 27: devicefleet.v2.ExtendBatchRequest.extension:type_name -> google.protobuf.Duration
+	4,  // 28: devicefleet.v2.ExtendBatchResponse.reservations:type_name -> devicefleet.v2.Reservation
+	26, // 29: devicefleet.v2.GetQuotaUsageResponse.usage:type_name -> devicefleet.v2.QuotaUsage
+	45, // 30: devicefleet.v2.Booking.start_time:type_name -> google.protobuf.Timestamp
+	45, // 31: devicefleet.v2.Booking.end_time:type_name -> google.protobuf.Timestamp
+	45, // 32: devicefleet.v2.CreateBookingRequest.start_time:type_name -> google.protobuf.Timestamp
+	45, // 33: devicefleet.v2.CreateBookingRequest.end_time:type_name -> google.protobuf.Timestamp
+	29, // 34: devicefleet.v2.CreateBookingResponse.booking:type_name -> devicefleet.v2.Booking
+	29, // 35: devicefleet.v2.ListBookingsResponse.bookings:type_name -> devicefleet.v2.Booking
+	29, // 36: devicefleet.v2.CancelBookingResponse.booking:type_name -> devicefleet.v2.Booking
+	5,  // 37: devicefleet.v2.ReportDeviceFailureResponse.device:type_name -> devicefleet.v2.Device
+	5,  // 38: devicefleet.v2.GetDrainStatusResponse.devices:type_name -> devicefleet.v2.Device
+	6,  // 39: devicefleet.v2.DeviceService.ReserveDevice:input_type -> devicefleet.v2.ReserveRequest
+	6,  // 40: devicefleet.v2.DeviceService.ReserveAndWait:input_type -> devicefleet.v2.ReserveRequest
+	9,  // 41: devicefleet.v2.DeviceService.ReleaseDevice:input_type -> devicefleet.v2.ReleaseRequest
+	11, // 42: devicefleet.v2.DeviceService.ExtendReservation:input_type -> devicefleet.v2.ExtendRequest
+	13, // 43: devicefleet.v2.DeviceService.WatchDevices:input_type -> devicefleet.v2.WatchRequest
+	15, // 44: devicefleet.v2.DeviceService.ListDevices:input_type -> devicefleet.v2.ListDevicesRequest
+	17, // 45: devicefleet.v2.DeviceService.GetDevice:input_type -> devicefleet.v2.GetDeviceRequest
+	20, // 46: devicefleet.v2.DeviceService.ReserveBatch:input_type -> devicefleet.v2.ReserveBatchRequest
+	22, // 47: devicefleet.v2.DeviceService.ReleaseBatch:input_type -> devicefleet.v2.ReleaseBatchRequest
+	24, // 48: devicefleet.v2.DeviceService.ExtendBatch:input_type -> devicefleet.v2.ExtendBatchRequest
+	27, // 49: devicefleet.v2.DeviceService.GetQuotaUsage:input_type -> devicefleet.v2.GetQuotaUsageRequest
+	30, // 50: devicefleet.v2.DeviceService.CreateBooking:input_type -> devicefleet.v2.CreateBookingRequest
+	32, // 51: devicefleet.v2.DeviceService.ListBookings:input_type -> devicefleet.v2.ListBookingsRequest
+	34, // 52: devicefleet.v2.DeviceService.CancelBooking:input_type -> devicefleet.v2.CancelBookingRequest
+	36, // 53: devicefleet.v2.DeviceService.ReportDeviceFailure:input_type -> devicefleet.v2.ReportDeviceFailureRequest
+	38, // 54: devicefleet.v2.DeviceService.GetDrainStatus:input_type -> devicefleet.v2.GetDrainStatusRequest
+	40, // 55: devicefleet.v2.DeviceService.WhoAmI:input_type -> devicefleet.v2.WhoAmIRequest
+	7,  // 56: devicefleet.v2.DeviceService.ReserveDevice:output_type -> devicefleet.v2.ReserveResponse
+	8,  // 57: devicefleet.v2.DeviceService.ReserveAndWait:output_type -> devicefleet.v2.ReserveUpdate
+	10, // 58: devicefleet.v2.DeviceService.ReleaseDevice:output_type -> devicefleet.v2.ReleaseResponse
+	12, // 59: devicefleet.v2.DeviceService.ExtendReservation:output_type -> devicefleet.v2.ExtendResponse
+	14, // 60: devicefleet.v2.DeviceService.WatchDevices:output_type -> devicefleet.v2.DeviceEvent
+	16, // 61: devicefleet.v2.DeviceService.ListDevices:output_type -> devicefleet.v2.ListDevicesResponse
+	18, // 62: devicefleet.v2.DeviceService.GetDevice:output_type -> devicefleet.v2.GetDeviceResponse
+	21, // 63: devicefleet.v2.DeviceService.ReserveBatch:output_type -> devicefleet.v2.ReserveBatchResponse
+	23, // 64: devicefleet.v2.DeviceService.ReleaseBatch:output_type -> devicefleet.v2.ReleaseBatchResponse
+	25, // 65: devicefleet.v2.DeviceService.ExtendBatch:output_type -> devicefleet.v2.ExtendBatchResponse
+	28, // 66: devicefleet.v2.DeviceService.GetQuotaUsage:output_type -> devicefleet.v2.GetQuotaUsageResponse
+	31, // 67: devicefleet.v2.DeviceService.CreateBooking:output_type -> devicefleet.v2.CreateBookingResponse
+	33, // 68: devicefleet.v2.DeviceService.ListBookings:output_type -> devicefleet.v2.ListBookingsResponse
+	35, // 69: devicefleet.v2.DeviceService.CancelBooking:output_type -> devicefleet.v2.CancelBookingResponse
+	37, // 70: devicefleet.v2.DeviceService.ReportDeviceFailure:output_type -> devicefleet.v2.ReportDeviceFailureResponse
+	39, // 71: devicefleet.v2.DeviceService.GetDrainStatus:output_type -> devicefleet.v2.GetDrainStatusResponse
+	41, // 72: devicefleet.v2.DeviceService.WhoAmI:output_type -> devicefleet.v2.WhoAmIResponse
+	56, // [56:73] is the sub-list for method output_type
+	39, // [39:56] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
+}
+
+func init() { file_proto_v2_device_proto_init() }
+func file_proto_v2_device_proto_init() {
+	if File_proto_v2_device_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v2_device_proto_rawDesc), len(file_proto_v2_device_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   41,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_v2_device_proto_goTypes,
+		DependencyIndexes: file_proto_v2_device_proto_depIdxs,
+		EnumInfos:         file_proto_v2_device_proto_enumTypes,
+		MessageInfos:      file_proto_v2_device_proto_msgTypes,
+	}.Build()
+	File_proto_v2_device_proto = out.File
+	file_proto_v2_device_proto_goTypes = nil
+	file_proto_v2_device_proto_depIdxs = nil
+}
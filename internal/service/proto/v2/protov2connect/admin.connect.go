@@ -0,0 +1,311 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/v2/admin.proto
+
+package protov2connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "devicefleet.v2.AdminService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AdminServiceForceReleaseProcedure is the fully-qualified name of the AdminService's ForceRelease
+	// RPC.
+	AdminServiceForceReleaseProcedure = "/devicefleet.v2.AdminService/ForceRelease"
+	// AdminServiceTransferReservationProcedure is the fully-qualified name of the AdminService's
+	// TransferReservation RPC.
+	AdminServiceTransferReservationProcedure = "/devicefleet.v2.AdminService/TransferReservation"
+	// AdminServiceReleaseUserReservationsProcedure is the fully-qualified name of the AdminService's
+	// ReleaseUserReservations RPC.
+	AdminServiceReleaseUserReservationsProcedure = "/devicefleet.v2.AdminService/ReleaseUserReservations"
+	// AdminServiceAddDeviceProcedure is the fully-qualified name of the AdminService's AddDevice RPC.
+	AdminServiceAddDeviceProcedure = "/devicefleet.v2.AdminService/AddDevice"
+	// AdminServiceRemoveDeviceProcedure is the fully-qualified name of the AdminService's RemoveDevice
+	// RPC.
+	AdminServiceRemoveDeviceProcedure = "/devicefleet.v2.AdminService/RemoveDevice"
+	// AdminServiceSetDeviceHealthProcedure is the fully-qualified name of the AdminService's
+	// SetDeviceHealth RPC.
+	AdminServiceSetDeviceHealthProcedure = "/devicefleet.v2.AdminService/SetDeviceHealth"
+	// AdminServiceDrainDevicesProcedure is the fully-qualified name of the AdminService's DrainDevices
+	// RPC.
+	AdminServiceDrainDevicesProcedure = "/devicefleet.v2.AdminService/DrainDevices"
+	// AdminServiceUndrainDevicesProcedure is the fully-qualified name of the AdminService's
+	// UndrainDevices RPC.
+	AdminServiceUndrainDevicesProcedure = "/devicefleet.v2.AdminService/UndrainDevices"
+)
+
+// AdminServiceClient is a client for the devicefleet.v2.AdminService service.
+type AdminServiceClient interface {
+	ForceRelease(context.Context, *connect.Request[v2.ForceReleaseRequest]) (*connect.Response[v2.ForceReleaseResponse], error)
+	TransferReservation(context.Context, *connect.Request[v2.TransferReservationRequest]) (*connect.Response[v2.TransferReservationResponse], error)
+	ReleaseUserReservations(context.Context, *connect.Request[v2.ReleaseUserReservationsRequest]) (*connect.Response[v2.ReleaseUserReservationsResponse], error)
+	AddDevice(context.Context, *connect.Request[v2.AddDeviceRequest]) (*connect.Response[v2.AddDeviceResponse], error)
+	RemoveDevice(context.Context, *connect.Request[v2.RemoveDeviceRequest]) (*connect.Response[v2.RemoveDeviceResponse], error)
+	SetDeviceHealth(context.Context, *connect.Request[v2.SetDeviceHealthRequest]) (*connect.Response[v2.SetDeviceHealthResponse], error)
+	DrainDevices(context.Context, *connect.Request[v2.DrainRequest]) (*connect.Response[v2.DrainResponse], error)
+	UndrainDevices(context.Context, *connect.Request[v2.UndrainRequest]) (*connect.Response[v2.UndrainResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the devicefleet.v2.AdminService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminServiceMethods := v2.File_proto_v2_admin_proto.Services().ByName("AdminService").Methods()
+	return &adminServiceClient{
+		forceRelease: connect.NewClient[v2.ForceReleaseRequest, v2.ForceReleaseResponse](
+			httpClient,
+			baseURL+AdminServiceForceReleaseProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ForceRelease")),
+			connect.WithClientOptions(opts...),
+		),
+		transferReservation: connect.NewClient[v2.TransferReservationRequest, v2.TransferReservationResponse](
+			httpClient,
+			baseURL+AdminServiceTransferReservationProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("TransferReservation")),
+			connect.WithClientOptions(opts...),
+		),
+		releaseUserReservations: connect.NewClient[v2.ReleaseUserReservationsRequest, v2.ReleaseUserReservationsResponse](
+			httpClient,
+			baseURL+AdminServiceReleaseUserReservationsProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ReleaseUserReservations")),
+			connect.WithClientOptions(opts...),
+		),
+		addDevice: connect.NewClient[v2.AddDeviceRequest, v2.AddDeviceResponse](
+			httpClient,
+			baseURL+AdminServiceAddDeviceProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("AddDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		removeDevice: connect.NewClient[v2.RemoveDeviceRequest, v2.RemoveDeviceResponse](
+			httpClient,
+			baseURL+AdminServiceRemoveDeviceProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("RemoveDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		setDeviceHealth: connect.NewClient[v2.SetDeviceHealthRequest, v2.SetDeviceHealthResponse](
+			httpClient,
+			baseURL+AdminServiceSetDeviceHealthProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("SetDeviceHealth")),
+			connect.WithClientOptions(opts...),
+		),
+		drainDevices: connect.NewClient[v2.DrainRequest, v2.DrainResponse](
+			httpClient,
+			baseURL+AdminServiceDrainDevicesProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("DrainDevices")),
+			connect.WithClientOptions(opts...),
+		),
+		undrainDevices: connect.NewClient[v2.UndrainRequest, v2.UndrainResponse](
+			httpClient,
+			baseURL+AdminServiceUndrainDevicesProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("UndrainDevices")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	forceRelease            *connect.Client[v2.ForceReleaseRequest, v2.ForceReleaseResponse]
+	transferReservation     *connect.Client[v2.TransferReservationRequest, v2.TransferReservationResponse]
+	releaseUserReservations *connect.Client[v2.ReleaseUserReservationsRequest, v2.ReleaseUserReservationsResponse]
+	addDevice               *connect.Client[v2.AddDeviceRequest, v2.AddDeviceResponse]
+	removeDevice            *connect.Client[v2.RemoveDeviceRequest, v2.RemoveDeviceResponse]
+	setDeviceHealth         *connect.Client[v2.SetDeviceHealthRequest, v2.SetDeviceHealthResponse]
+	drainDevices            *connect.Client[v2.DrainRequest, v2.DrainResponse]
+	undrainDevices          *connect.Client[v2.UndrainRequest, v2.UndrainResponse]
+}
+
+// ForceRelease calls devicefleet.v2.AdminService.ForceRelease.
+func (c *adminServiceClient) ForceRelease(ctx context.Context, req *connect.Request[v2.ForceReleaseRequest]) (*connect.Response[v2.ForceReleaseResponse], error) {
+	return c.forceRelease.CallUnary(ctx, req)
+}
+
+// TransferReservation calls devicefleet.v2.AdminService.TransferReservation.
+func (c *adminServiceClient) TransferReservation(ctx context.Context, req *connect.Request[v2.TransferReservationRequest]) (*connect.Response[v2.TransferReservationResponse], error) {
+	return c.transferReservation.CallUnary(ctx, req)
+}
+
+// ReleaseUserReservations calls devicefleet.v2.AdminService.ReleaseUserReservations.
+func (c *adminServiceClient) ReleaseUserReservations(ctx context.Context, req *connect.Request[v2.ReleaseUserReservationsRequest]) (*connect.Response[v2.ReleaseUserReservationsResponse], error) {
+	return c.releaseUserReservations.CallUnary(ctx, req)
+}
+
+// AddDevice calls devicefleet.v2.AdminService.AddDevice.
+func (c *adminServiceClient) AddDevice(ctx context.Context, req *connect.Request[v2.AddDeviceRequest]) (*connect.Response[v2.AddDeviceResponse], error) {
+	return c.addDevice.CallUnary(ctx, req)
+}
+
+// RemoveDevice calls devicefleet.v2.AdminService.RemoveDevice.
+func (c *adminServiceClient) RemoveDevice(ctx context.Context, req *connect.Request[v2.RemoveDeviceRequest]) (*connect.Response[v2.RemoveDeviceResponse], error) {
+	return c.removeDevice.CallUnary(ctx, req)
+}
+
+// SetDeviceHealth calls devicefleet.v2.AdminService.SetDeviceHealth.
+func (c *adminServiceClient) SetDeviceHealth(ctx context.Context, req *connect.Request[v2.SetDeviceHealthRequest]) (*connect.Response[v2.SetDeviceHealthResponse], error) {
+	return c.setDeviceHealth.CallUnary(ctx, req)
+}
+
+// DrainDevices calls devicefleet.v2.AdminService.DrainDevices.
+func (c *adminServiceClient) DrainDevices(ctx context.Context, req *connect.Request[v2.DrainRequest]) (*connect.Response[v2.DrainResponse], error) {
+	return c.drainDevices.CallUnary(ctx, req)
+}
+
+// UndrainDevices calls devicefleet.v2.AdminService.UndrainDevices.
+func (c *adminServiceClient) UndrainDevices(ctx context.Context, req *connect.Request[v2.UndrainRequest]) (*connect.Response[v2.UndrainResponse], error) {
+	return c.undrainDevices.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the devicefleet.v2.AdminService service.
+type AdminServiceHandler interface {
+	ForceRelease(context.Context, *connect.Request[v2.ForceReleaseRequest]) (*connect.Response[v2.ForceReleaseResponse], error)
+	TransferReservation(context.Context, *connect.Request[v2.TransferReservationRequest]) (*connect.Response[v2.TransferReservationResponse], error)
+	ReleaseUserReservations(context.Context, *connect.Request[v2.ReleaseUserReservationsRequest]) (*connect.Response[v2.ReleaseUserReservationsResponse], error)
+	AddDevice(context.Context, *connect.Request[v2.AddDeviceRequest]) (*connect.Response[v2.AddDeviceResponse], error)
+	RemoveDevice(context.Context, *connect.Request[v2.RemoveDeviceRequest]) (*connect.Response[v2.RemoveDeviceResponse], error)
+	SetDeviceHealth(context.Context, *connect.Request[v2.SetDeviceHealthRequest]) (*connect.Response[v2.SetDeviceHealthResponse], error)
+	DrainDevices(context.Context, *connect.Request[v2.DrainRequest]) (*connect.Response[v2.DrainResponse], error)
+	UndrainDevices(context.Context, *connect.Request[v2.UndrainRequest]) (*connect.Response[v2.UndrainResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminServiceMethods := v2.File_proto_v2_admin_proto.Services().ByName("AdminService").Methods()
+	adminServiceForceReleaseHandler := connect.NewUnaryHandler(
+		AdminServiceForceReleaseProcedure,
+		svc.ForceRelease,
+		connect.WithSchema(adminServiceMethods.ByName("ForceRelease")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceTransferReservationHandler := connect.NewUnaryHandler(
+		AdminServiceTransferReservationProcedure,
+		svc.TransferReservation,
+		connect.WithSchema(adminServiceMethods.ByName("TransferReservation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceReleaseUserReservationsHandler := connect.NewUnaryHandler(
+		AdminServiceReleaseUserReservationsProcedure,
+		svc.ReleaseUserReservations,
+		connect.WithSchema(adminServiceMethods.ByName("ReleaseUserReservations")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceAddDeviceHandler := connect.NewUnaryHandler(
+		AdminServiceAddDeviceProcedure,
+		svc.AddDevice,
+		connect.WithSchema(adminServiceMethods.ByName("AddDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRemoveDeviceHandler := connect.NewUnaryHandler(
+		AdminServiceRemoveDeviceProcedure,
+		svc.RemoveDevice,
+		connect.WithSchema(adminServiceMethods.ByName("RemoveDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceSetDeviceHealthHandler := connect.NewUnaryHandler(
+		AdminServiceSetDeviceHealthProcedure,
+		svc.SetDeviceHealth,
+		connect.WithSchema(adminServiceMethods.ByName("SetDeviceHealth")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceDrainDevicesHandler := connect.NewUnaryHandler(
+		AdminServiceDrainDevicesProcedure,
+		svc.DrainDevices,
+		connect.WithSchema(adminServiceMethods.ByName("DrainDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceUndrainDevicesHandler := connect.NewUnaryHandler(
+		AdminServiceUndrainDevicesProcedure,
+		svc.UndrainDevices,
+		connect.WithSchema(adminServiceMethods.ByName("UndrainDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/devicefleet.v2.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceForceReleaseProcedure:
+			adminServiceForceReleaseHandler.ServeHTTP(w, r)
+		case AdminServiceTransferReservationProcedure:
+			adminServiceTransferReservationHandler.ServeHTTP(w, r)
+		case AdminServiceReleaseUserReservationsProcedure:
+			adminServiceReleaseUserReservationsHandler.ServeHTTP(w, r)
+		case AdminServiceAddDeviceProcedure:
+			adminServiceAddDeviceHandler.ServeHTTP(w, r)
+		case AdminServiceRemoveDeviceProcedure:
+			adminServiceRemoveDeviceHandler.ServeHTTP(w, r)
+		case AdminServiceSetDeviceHealthProcedure:
+			adminServiceSetDeviceHealthHandler.ServeHTTP(w, r)
+		case AdminServiceDrainDevicesProcedure:
+			adminServiceDrainDevicesHandler.ServeHTTP(w, r)
+		case AdminServiceUndrainDevicesProcedure:
+			adminServiceUndrainDevicesHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) ForceRelease(context.Context, *connect.Request[v2.ForceReleaseRequest]) (*connect.Response[v2.ForceReleaseResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.ForceRelease is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) TransferReservation(context.Context, *connect.Request[v2.TransferReservationRequest]) (*connect.Response[v2.TransferReservationResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.TransferReservation is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) ReleaseUserReservations(context.Context, *connect.Request[v2.ReleaseUserReservationsRequest]) (*connect.Response[v2.ReleaseUserReservationsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.ReleaseUserReservations is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) AddDevice(context.Context, *connect.Request[v2.AddDeviceRequest]) (*connect.Response[v2.AddDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.AddDevice is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RemoveDevice(context.Context, *connect.Request[v2.RemoveDeviceRequest]) (*connect.Response[v2.RemoveDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.RemoveDevice is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) SetDeviceHealth(context.Context, *connect.Request[v2.SetDeviceHealthRequest]) (*connect.Response[v2.SetDeviceHealthResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.SetDeviceHealth is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) DrainDevices(context.Context, *connect.Request[v2.DrainRequest]) (*connect.Response[v2.DrainResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.DrainDevices is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) UndrainDevices(context.Context, *connect.Request[v2.UndrainRequest]) (*connect.Response[v2.UndrainResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.AdminService.UndrainDevices is not implemented"))
+}
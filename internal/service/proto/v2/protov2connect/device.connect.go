@@ -0,0 +1,571 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: proto/v2/device.proto
+
+package protov2connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// DeviceServiceName is the fully-qualified name of the DeviceService service.
+	DeviceServiceName = "devicefleet.v2.DeviceService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DeviceServiceReserveDeviceProcedure is the fully-qualified name of the DeviceService's
+	// ReserveDevice RPC.
+	DeviceServiceReserveDeviceProcedure = "/devicefleet.v2.DeviceService/ReserveDevice"
+	// DeviceServiceReserveAndWaitProcedure is the fully-qualified name of the DeviceService's
+	// ReserveAndWait RPC.
+	DeviceServiceReserveAndWaitProcedure = "/devicefleet.v2.DeviceService/ReserveAndWait"
+	// DeviceServiceReleaseDeviceProcedure is the fully-qualified name of the DeviceService's
+	// ReleaseDevice RPC.
+	DeviceServiceReleaseDeviceProcedure = "/devicefleet.v2.DeviceService/ReleaseDevice"
+	// DeviceServiceExtendReservationProcedure is the fully-qualified name of the DeviceService's
+	// ExtendReservation RPC.
+	DeviceServiceExtendReservationProcedure = "/devicefleet.v2.DeviceService/ExtendReservation"
+	// DeviceServiceWatchDevicesProcedure is the fully-qualified name of the DeviceService's
+	// WatchDevices RPC.
+	DeviceServiceWatchDevicesProcedure = "/devicefleet.v2.DeviceService/WatchDevices"
+	// DeviceServiceListDevicesProcedure is the fully-qualified name of the DeviceService's ListDevices
+	// RPC.
+	DeviceServiceListDevicesProcedure = "/devicefleet.v2.DeviceService/ListDevices"
+	// DeviceServiceGetDeviceProcedure is the fully-qualified name of the DeviceService's GetDevice RPC.
+	DeviceServiceGetDeviceProcedure = "/devicefleet.v2.DeviceService/GetDevice"
+	// DeviceServiceReserveBatchProcedure is the fully-qualified name of the DeviceService's
+	// ReserveBatch RPC.
+	DeviceServiceReserveBatchProcedure = "/devicefleet.v2.DeviceService/ReserveBatch"
+	// DeviceServiceReleaseBatchProcedure is the fully-qualified name of the DeviceService's
+	// ReleaseBatch RPC.
+	DeviceServiceReleaseBatchProcedure = "/devicefleet.v2.DeviceService/ReleaseBatch"
+	// DeviceServiceExtendBatchProcedure is the fully-qualified name of the DeviceService's ExtendBatch
+	// RPC.
+	DeviceServiceExtendBatchProcedure = "/devicefleet.v2.DeviceService/ExtendBatch"
+	// DeviceServiceGetQuotaUsageProcedure is the fully-qualified name of the DeviceService's
+	// GetQuotaUsage RPC.
+	DeviceServiceGetQuotaUsageProcedure = "/devicefleet.v2.DeviceService/GetQuotaUsage"
+	// DeviceServiceCreateBookingProcedure is the fully-qualified name of the DeviceService's
+	// CreateBooking RPC.
+	DeviceServiceCreateBookingProcedure = "/devicefleet.v2.DeviceService/CreateBooking"
+	// DeviceServiceListBookingsProcedure is the fully-qualified name of the DeviceService's
+	// ListBookings RPC.
+	DeviceServiceListBookingsProcedure = "/devicefleet.v2.DeviceService/ListBookings"
+	// DeviceServiceCancelBookingProcedure is the fully-qualified name of the DeviceService's
+	// CancelBooking RPC.
+	DeviceServiceCancelBookingProcedure = "/devicefleet.v2.DeviceService/CancelBooking"
+	// DeviceServiceReportDeviceFailureProcedure is the fully-qualified name of the DeviceService's
+	// ReportDeviceFailure RPC.
+	DeviceServiceReportDeviceFailureProcedure = "/devicefleet.v2.DeviceService/ReportDeviceFailure"
+	// DeviceServiceGetDrainStatusProcedure is the fully-qualified name of the DeviceService's
+	// GetDrainStatus RPC.
+	DeviceServiceGetDrainStatusProcedure = "/devicefleet.v2.DeviceService/GetDrainStatus"
+	// DeviceServiceWhoAmIProcedure is the fully-qualified name of the DeviceService's WhoAmI RPC.
+	DeviceServiceWhoAmIProcedure = "/devicefleet.v2.DeviceService/WhoAmI"
+)
+
+// DeviceServiceClient is a client for the devicefleet.v2.DeviceService service.
+type DeviceServiceClient interface {
+	ReserveDevice(context.Context, *connect.Request[v2.ReserveRequest]) (*connect.Response[v2.ReserveResponse], error)
+	ReserveAndWait(context.Context, *connect.Request[v2.ReserveRequest]) (*connect.ServerStreamForClient[v2.ReserveUpdate], error)
+	ReleaseDevice(context.Context, *connect.Request[v2.ReleaseRequest]) (*connect.Response[v2.ReleaseResponse], error)
+	ExtendReservation(context.Context, *connect.Request[v2.ExtendRequest]) (*connect.Response[v2.ExtendResponse], error)
+	WatchDevices(context.Context, *connect.Request[v2.WatchRequest]) (*connect.ServerStreamForClient[v2.DeviceEvent], error)
+	ListDevices(context.Context, *connect.Request[v2.ListDevicesRequest]) (*connect.Response[v2.ListDevicesResponse], error)
+	GetDevice(context.Context, *connect.Request[v2.GetDeviceRequest]) (*connect.Response[v2.GetDeviceResponse], error)
+	ReserveBatch(context.Context, *connect.Request[v2.ReserveBatchRequest]) (*connect.Response[v2.ReserveBatchResponse], error)
+	ReleaseBatch(context.Context, *connect.Request[v2.ReleaseBatchRequest]) (*connect.Response[v2.ReleaseBatchResponse], error)
+	ExtendBatch(context.Context, *connect.Request[v2.ExtendBatchRequest]) (*connect.Response[v2.ExtendBatchResponse], error)
+	GetQuotaUsage(context.Context, *connect.Request[v2.GetQuotaUsageRequest]) (*connect.Response[v2.GetQuotaUsageResponse], error)
+	CreateBooking(context.Context, *connect.Request[v2.CreateBookingRequest]) (*connect.Response[v2.CreateBookingResponse], error)
+	ListBookings(context.Context, *connect.Request[v2.ListBookingsRequest]) (*connect.Response[v2.ListBookingsResponse], error)
+	CancelBooking(context.Context, *connect.Request[v2.CancelBookingRequest]) (*connect.Response[v2.CancelBookingResponse], error)
+	ReportDeviceFailure(context.Context, *connect.Request[v2.ReportDeviceFailureRequest]) (*connect.Response[v2.ReportDeviceFailureResponse], error)
+	GetDrainStatus(context.Context, *connect.Request[v2.GetDrainStatusRequest]) (*connect.Response[v2.GetDrainStatusResponse], error)
+	WhoAmI(context.Context, *connect.Request[v2.WhoAmIRequest]) (*connect.Response[v2.WhoAmIResponse], error)
+}
+
+// NewDeviceServiceClient constructs a client for the devicefleet.v2.DeviceService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDeviceServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) DeviceServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	deviceServiceMethods := v2.File_proto_v2_device_proto.Services().ByName("DeviceService").Methods()
+	return &deviceServiceClient{
+		reserveDevice: connect.NewClient[v2.ReserveRequest, v2.ReserveResponse](
+			httpClient,
+			baseURL+DeviceServiceReserveDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReserveDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		reserveAndWait: connect.NewClient[v2.ReserveRequest, v2.ReserveUpdate](
+			httpClient,
+			baseURL+DeviceServiceReserveAndWaitProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReserveAndWait")),
+			connect.WithClientOptions(opts...),
+		),
+		releaseDevice: connect.NewClient[v2.ReleaseRequest, v2.ReleaseResponse](
+			httpClient,
+			baseURL+DeviceServiceReleaseDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReleaseDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		extendReservation: connect.NewClient[v2.ExtendRequest, v2.ExtendResponse](
+			httpClient,
+			baseURL+DeviceServiceExtendReservationProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ExtendReservation")),
+			connect.WithClientOptions(opts...),
+		),
+		watchDevices: connect.NewClient[v2.WatchRequest, v2.DeviceEvent](
+			httpClient,
+			baseURL+DeviceServiceWatchDevicesProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("WatchDevices")),
+			connect.WithClientOptions(opts...),
+		),
+		listDevices: connect.NewClient[v2.ListDevicesRequest, v2.ListDevicesResponse](
+			httpClient,
+			baseURL+DeviceServiceListDevicesProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListDevices")),
+			connect.WithClientOptions(opts...),
+		),
+		getDevice: connect.NewClient[v2.GetDeviceRequest, v2.GetDeviceResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDeviceProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDevice")),
+			connect.WithClientOptions(opts...),
+		),
+		reserveBatch: connect.NewClient[v2.ReserveBatchRequest, v2.ReserveBatchResponse](
+			httpClient,
+			baseURL+DeviceServiceReserveBatchProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReserveBatch")),
+			connect.WithClientOptions(opts...),
+		),
+		releaseBatch: connect.NewClient[v2.ReleaseBatchRequest, v2.ReleaseBatchResponse](
+			httpClient,
+			baseURL+DeviceServiceReleaseBatchProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReleaseBatch")),
+			connect.WithClientOptions(opts...),
+		),
+		extendBatch: connect.NewClient[v2.ExtendBatchRequest, v2.ExtendBatchResponse](
+			httpClient,
+			baseURL+DeviceServiceExtendBatchProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ExtendBatch")),
+			connect.WithClientOptions(opts...),
+		),
+		getQuotaUsage: connect.NewClient[v2.GetQuotaUsageRequest, v2.GetQuotaUsageResponse](
+			httpClient,
+			baseURL+DeviceServiceGetQuotaUsageProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetQuotaUsage")),
+			connect.WithClientOptions(opts...),
+		),
+		createBooking: connect.NewClient[v2.CreateBookingRequest, v2.CreateBookingResponse](
+			httpClient,
+			baseURL+DeviceServiceCreateBookingProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("CreateBooking")),
+			connect.WithClientOptions(opts...),
+		),
+		listBookings: connect.NewClient[v2.ListBookingsRequest, v2.ListBookingsResponse](
+			httpClient,
+			baseURL+DeviceServiceListBookingsProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ListBookings")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelBooking: connect.NewClient[v2.CancelBookingRequest, v2.CancelBookingResponse](
+			httpClient,
+			baseURL+DeviceServiceCancelBookingProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("CancelBooking")),
+			connect.WithClientOptions(opts...),
+		),
+		reportDeviceFailure: connect.NewClient[v2.ReportDeviceFailureRequest, v2.ReportDeviceFailureResponse](
+			httpClient,
+			baseURL+DeviceServiceReportDeviceFailureProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("ReportDeviceFailure")),
+			connect.WithClientOptions(opts...),
+		),
+		getDrainStatus: connect.NewClient[v2.GetDrainStatusRequest, v2.GetDrainStatusResponse](
+			httpClient,
+			baseURL+DeviceServiceGetDrainStatusProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("GetDrainStatus")),
+			connect.WithClientOptions(opts...),
+		),
+		whoAmI: connect.NewClient[v2.WhoAmIRequest, v2.WhoAmIResponse](
+			httpClient,
+			baseURL+DeviceServiceWhoAmIProcedure,
+			connect.WithSchema(deviceServiceMethods.ByName("WhoAmI")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// deviceServiceClient implements DeviceServiceClient.
+type deviceServiceClient struct {
+	reserveDevice       *connect.Client[v2.ReserveRequest, v2.ReserveResponse]
+	reserveAndWait      *connect.Client[v2.ReserveRequest, v2.ReserveUpdate]
+	releaseDevice       *connect.Client[v2.ReleaseRequest, v2.ReleaseResponse]
+	extendReservation   *connect.Client[v2.ExtendRequest, v2.ExtendResponse]
+	watchDevices        *connect.Client[v2.WatchRequest, v2.DeviceEvent]
+	listDevices         *connect.Client[v2.ListDevicesRequest, v2.ListDevicesResponse]
+	getDevice           *connect.Client[v2.GetDeviceRequest, v2.GetDeviceResponse]
+	reserveBatch        *connect.Client[v2.ReserveBatchRequest, v2.ReserveBatchResponse]
+	releaseBatch        *connect.Client[v2.ReleaseBatchRequest, v2.ReleaseBatchResponse]
+	extendBatch         *connect.Client[v2.ExtendBatchRequest, v2.ExtendBatchResponse]
+	getQuotaUsage       *connect.Client[v2.GetQuotaUsageRequest, v2.GetQuotaUsageResponse]
+	createBooking       *connect.Client[v2.CreateBookingRequest, v2.CreateBookingResponse]
+	listBookings        *connect.Client[v2.ListBookingsRequest, v2.ListBookingsResponse]
+	cancelBooking       *connect.Client[v2.CancelBookingRequest, v2.CancelBookingResponse]
+	reportDeviceFailure *connect.Client[v2.ReportDeviceFailureRequest, v2.ReportDeviceFailureResponse]
+	getDrainStatus      *connect.Client[v2.GetDrainStatusRequest, v2.GetDrainStatusResponse]
+	whoAmI              *connect.Client[v2.WhoAmIRequest, v2.WhoAmIResponse]
+}
+
+// ReserveDevice calls devicefleet.v2.DeviceService.ReserveDevice.
+func (c *deviceServiceClient) ReserveDevice(ctx context.Context, req *connect.Request[v2.ReserveRequest]) (*connect.Response[v2.ReserveResponse], error) {
+	return c.reserveDevice.CallUnary(ctx, req)
+}
+
+// ReserveAndWait calls devicefleet.v2.DeviceService.ReserveAndWait.
+func (c *deviceServiceClient) ReserveAndWait(ctx context.Context, req *connect.Request[v2.ReserveRequest]) (*connect.ServerStreamForClient[v2.ReserveUpdate], error) {
+	return c.reserveAndWait.CallServerStream(ctx, req)
+}
+
+// ReleaseDevice calls devicefleet.v2.DeviceService.ReleaseDevice.
+func (c *deviceServiceClient) ReleaseDevice(ctx context.Context, req *connect.Request[v2.ReleaseRequest]) (*connect.Response[v2.ReleaseResponse], error) {
+	return c.releaseDevice.CallUnary(ctx, req)
+}
+
+// ExtendReservation calls devicefleet.v2.DeviceService.ExtendReservation.
+func (c *deviceServiceClient) ExtendReservation(ctx context.Context, req *connect.Request[v2.ExtendRequest]) (*connect.Response[v2.ExtendResponse], error) {
+	return c.extendReservation.CallUnary(ctx, req)
+}
+
+// WatchDevices calls devicefleet.v2.DeviceService.WatchDevices.
+func (c *deviceServiceClient) WatchDevices(ctx context.Context, req *connect.Request[v2.WatchRequest]) (*connect.ServerStreamForClient[v2.DeviceEvent], error) {
+	return c.watchDevices.CallServerStream(ctx, req)
+}
+
+// ListDevices calls devicefleet.v2.DeviceService.ListDevices.
+func (c *deviceServiceClient) ListDevices(ctx context.Context, req *connect.Request[v2.ListDevicesRequest]) (*connect.Response[v2.ListDevicesResponse], error) {
+	return c.listDevices.CallUnary(ctx, req)
+}
+
+// GetDevice calls devicefleet.v2.DeviceService.GetDevice.
+func (c *deviceServiceClient) GetDevice(ctx context.Context, req *connect.Request[v2.GetDeviceRequest]) (*connect.Response[v2.GetDeviceResponse], error) {
+	return c.getDevice.CallUnary(ctx, req)
+}
+
+// ReserveBatch calls devicefleet.v2.DeviceService.ReserveBatch.
+func (c *deviceServiceClient) ReserveBatch(ctx context.Context, req *connect.Request[v2.ReserveBatchRequest]) (*connect.Response[v2.ReserveBatchResponse], error) {
+	return c.reserveBatch.CallUnary(ctx, req)
+}
+
+// ReleaseBatch calls devicefleet.v2.DeviceService.ReleaseBatch.
+func (c *deviceServiceClient) ReleaseBatch(ctx context.Context, req *connect.Request[v2.ReleaseBatchRequest]) (*connect.Response[v2.ReleaseBatchResponse], error) {
+	return c.releaseBatch.CallUnary(ctx, req)
+}
+
+// ExtendBatch calls devicefleet.v2.DeviceService.ExtendBatch.
+func (c *deviceServiceClient) ExtendBatch(ctx context.Context, req *connect.Request[v2.ExtendBatchRequest]) (*connect.Response[v2.ExtendBatchResponse], error) {
+	return c.extendBatch.CallUnary(ctx, req)
+}
+
+// GetQuotaUsage calls devicefleet.v2.DeviceService.GetQuotaUsage.
+func (c *deviceServiceClient) GetQuotaUsage(ctx context.Context, req *connect.Request[v2.GetQuotaUsageRequest]) (*connect.Response[v2.GetQuotaUsageResponse], error) {
+	return c.getQuotaUsage.CallUnary(ctx, req)
+}
+
+// CreateBooking calls devicefleet.v2.DeviceService.CreateBooking.
+func (c *deviceServiceClient) CreateBooking(ctx context.Context, req *connect.Request[v2.CreateBookingRequest]) (*connect.Response[v2.CreateBookingResponse], error) {
+	return c.createBooking.CallUnary(ctx, req)
+}
+
+// ListBookings calls devicefleet.v2.DeviceService.ListBookings.
+func (c *deviceServiceClient) ListBookings(ctx context.Context, req *connect.Request[v2.ListBookingsRequest]) (*connect.Response[v2.ListBookingsResponse], error) {
+	return c.listBookings.CallUnary(ctx, req)
+}
+
+// CancelBooking calls devicefleet.v2.DeviceService.CancelBooking.
+func (c *deviceServiceClient) CancelBooking(ctx context.Context, req *connect.Request[v2.CancelBookingRequest]) (*connect.Response[v2.CancelBookingResponse], error) {
+	return c.cancelBooking.CallUnary(ctx, req)
+}
+
+// ReportDeviceFailure calls devicefleet.v2.DeviceService.ReportDeviceFailure.
+func (c *deviceServiceClient) ReportDeviceFailure(ctx context.Context, req *connect.Request[v2.ReportDeviceFailureRequest]) (*connect.Response[v2.ReportDeviceFailureResponse], error) {
+	return c.reportDeviceFailure.CallUnary(ctx, req)
+}
+
+// GetDrainStatus calls devicefleet.v2.DeviceService.GetDrainStatus.
+func (c *deviceServiceClient) GetDrainStatus(ctx context.Context, req *connect.Request[v2.GetDrainStatusRequest]) (*connect.Response[v2.GetDrainStatusResponse], error) {
+	return c.getDrainStatus.CallUnary(ctx, req)
+}
+
+// WhoAmI calls devicefleet.v2.DeviceService.WhoAmI.
+func (c *deviceServiceClient) WhoAmI(ctx context.Context, req *connect.Request[v2.WhoAmIRequest]) (*connect.Response[v2.WhoAmIResponse], error) {
+	return c.whoAmI.CallUnary(ctx, req)
+}
+
+// DeviceServiceHandler is an implementation of the devicefleet.v2.DeviceService service.
+type DeviceServiceHandler interface {
+	ReserveDevice(context.Context, *connect.Request[v2.ReserveRequest]) (*connect.Response[v2.ReserveResponse], error)
+	ReserveAndWait(context.Context, *connect.Request[v2.ReserveRequest], *connect.ServerStream[v2.ReserveUpdate]) error
+	ReleaseDevice(context.Context, *connect.Request[v2.ReleaseRequest]) (*connect.Response[v2.ReleaseResponse], error)
+	ExtendReservation(context.Context, *connect.Request[v2.ExtendRequest]) (*connect.Response[v2.ExtendResponse], error)
+	WatchDevices(context.Context, *connect.Request[v2.WatchRequest], *connect.ServerStream[v2.DeviceEvent]) error
+	ListDevices(context.Context, *connect.Request[v2.ListDevicesRequest]) (*connect.Response[v2.ListDevicesResponse], error)
+	GetDevice(context.Context, *connect.Request[v2.GetDeviceRequest]) (*connect.Response[v2.GetDeviceResponse], error)
+	ReserveBatch(context.Context, *connect.Request[v2.ReserveBatchRequest]) (*connect.Response[v2.ReserveBatchResponse], error)
+	ReleaseBatch(context.Context, *connect.Request[v2.ReleaseBatchRequest]) (*connect.Response[v2.ReleaseBatchResponse], error)
+	ExtendBatch(context.Context, *connect.Request[v2.ExtendBatchRequest]) (*connect.Response[v2.ExtendBatchResponse], error)
+	GetQuotaUsage(context.Context, *connect.Request[v2.GetQuotaUsageRequest]) (*connect.Response[v2.GetQuotaUsageResponse], error)
+	CreateBooking(context.Context, *connect.Request[v2.CreateBookingRequest]) (*connect.Response[v2.CreateBookingResponse], error)
+	ListBookings(context.Context, *connect.Request[v2.ListBookingsRequest]) (*connect.Response[v2.ListBookingsResponse], error)
+	CancelBooking(context.Context, *connect.Request[v2.CancelBookingRequest]) (*connect.Response[v2.CancelBookingResponse], error)
+	ReportDeviceFailure(context.Context, *connect.Request[v2.ReportDeviceFailureRequest]) (*connect.Response[v2.ReportDeviceFailureResponse], error)
+	GetDrainStatus(context.Context, *connect.Request[v2.GetDrainStatusRequest]) (*connect.Response[v2.GetDrainStatusResponse], error)
+	WhoAmI(context.Context, *connect.Request[v2.WhoAmIRequest]) (*connect.Response[v2.WhoAmIResponse], error)
+}
+
+// NewDeviceServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDeviceServiceHandler(svc DeviceServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	deviceServiceMethods := v2.File_proto_v2_device_proto.Services().ByName("DeviceService").Methods()
+	deviceServiceReserveDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceReserveDeviceProcedure,
+		svc.ReserveDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("ReserveDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceReserveAndWaitHandler := connect.NewServerStreamHandler(
+		DeviceServiceReserveAndWaitProcedure,
+		svc.ReserveAndWait,
+		connect.WithSchema(deviceServiceMethods.ByName("ReserveAndWait")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceReleaseDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceReleaseDeviceProcedure,
+		svc.ReleaseDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("ReleaseDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceExtendReservationHandler := connect.NewUnaryHandler(
+		DeviceServiceExtendReservationProcedure,
+		svc.ExtendReservation,
+		connect.WithSchema(deviceServiceMethods.ByName("ExtendReservation")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceWatchDevicesHandler := connect.NewServerStreamHandler(
+		DeviceServiceWatchDevicesProcedure,
+		svc.WatchDevices,
+		connect.WithSchema(deviceServiceMethods.ByName("WatchDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListDevicesHandler := connect.NewUnaryHandler(
+		DeviceServiceListDevicesProcedure,
+		svc.ListDevices,
+		connect.WithSchema(deviceServiceMethods.ByName("ListDevices")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDeviceHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDeviceProcedure,
+		svc.GetDevice,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDevice")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceReserveBatchHandler := connect.NewUnaryHandler(
+		DeviceServiceReserveBatchProcedure,
+		svc.ReserveBatch,
+		connect.WithSchema(deviceServiceMethods.ByName("ReserveBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceReleaseBatchHandler := connect.NewUnaryHandler(
+		DeviceServiceReleaseBatchProcedure,
+		svc.ReleaseBatch,
+		connect.WithSchema(deviceServiceMethods.ByName("ReleaseBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceExtendBatchHandler := connect.NewUnaryHandler(
+		DeviceServiceExtendBatchProcedure,
+		svc.ExtendBatch,
+		connect.WithSchema(deviceServiceMethods.ByName("ExtendBatch")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetQuotaUsageHandler := connect.NewUnaryHandler(
+		DeviceServiceGetQuotaUsageProcedure,
+		svc.GetQuotaUsage,
+		connect.WithSchema(deviceServiceMethods.ByName("GetQuotaUsage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceCreateBookingHandler := connect.NewUnaryHandler(
+		DeviceServiceCreateBookingProcedure,
+		svc.CreateBooking,
+		connect.WithSchema(deviceServiceMethods.ByName("CreateBooking")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceListBookingsHandler := connect.NewUnaryHandler(
+		DeviceServiceListBookingsProcedure,
+		svc.ListBookings,
+		connect.WithSchema(deviceServiceMethods.ByName("ListBookings")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceCancelBookingHandler := connect.NewUnaryHandler(
+		DeviceServiceCancelBookingProcedure,
+		svc.CancelBooking,
+		connect.WithSchema(deviceServiceMethods.ByName("CancelBooking")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceReportDeviceFailureHandler := connect.NewUnaryHandler(
+		DeviceServiceReportDeviceFailureProcedure,
+		svc.ReportDeviceFailure,
+		connect.WithSchema(deviceServiceMethods.ByName("ReportDeviceFailure")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceGetDrainStatusHandler := connect.NewUnaryHandler(
+		DeviceServiceGetDrainStatusProcedure,
+		svc.GetDrainStatus,
+		connect.WithSchema(deviceServiceMethods.ByName("GetDrainStatus")),
+		connect.WithHandlerOptions(opts...),
+	)
+	deviceServiceWhoAmIHandler := connect.NewUnaryHandler(
+		DeviceServiceWhoAmIProcedure,
+		svc.WhoAmI,
+		connect.WithSchema(deviceServiceMethods.ByName("WhoAmI")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/devicefleet.v2.DeviceService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DeviceServiceReserveDeviceProcedure:
+			deviceServiceReserveDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceReserveAndWaitProcedure:
+			deviceServiceReserveAndWaitHandler.ServeHTTP(w, r)
+		case DeviceServiceReleaseDeviceProcedure:
+			deviceServiceReleaseDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceExtendReservationProcedure:
+			deviceServiceExtendReservationHandler.ServeHTTP(w, r)
+		case DeviceServiceWatchDevicesProcedure:
+			deviceServiceWatchDevicesHandler.ServeHTTP(w, r)
+		case DeviceServiceListDevicesProcedure:
+			deviceServiceListDevicesHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDeviceProcedure:
+			deviceServiceGetDeviceHandler.ServeHTTP(w, r)
+		case DeviceServiceReserveBatchProcedure:
+			deviceServiceReserveBatchHandler.ServeHTTP(w, r)
+		case DeviceServiceReleaseBatchProcedure:
+			deviceServiceReleaseBatchHandler.ServeHTTP(w, r)
+		case DeviceServiceExtendBatchProcedure:
+			deviceServiceExtendBatchHandler.ServeHTTP(w, r)
+		case DeviceServiceGetQuotaUsageProcedure:
+			deviceServiceGetQuotaUsageHandler.ServeHTTP(w, r)
+		case DeviceServiceCreateBookingProcedure:
+			deviceServiceCreateBookingHandler.ServeHTTP(w, r)
+		case DeviceServiceListBookingsProcedure:
+			deviceServiceListBookingsHandler.ServeHTTP(w, r)
+		case DeviceServiceCancelBookingProcedure:
+			deviceServiceCancelBookingHandler.ServeHTTP(w, r)
+		case DeviceServiceReportDeviceFailureProcedure:
+			deviceServiceReportDeviceFailureHandler.ServeHTTP(w, r)
+		case DeviceServiceGetDrainStatusProcedure:
+			deviceServiceGetDrainStatusHandler.ServeHTTP(w, r)
+		case DeviceServiceWhoAmIProcedure:
+			deviceServiceWhoAmIHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDeviceServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDeviceServiceHandler struct{}
+
+func (UnimplementedDeviceServiceHandler) ReserveDevice(context.Context, *connect.Request[v2.ReserveRequest]) (*connect.Response[v2.ReserveResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReserveDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ReserveAndWait(context.Context, *connect.Request[v2.ReserveRequest], *connect.ServerStream[v2.ReserveUpdate]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReserveAndWait is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ReleaseDevice(context.Context, *connect.Request[v2.ReleaseRequest]) (*connect.Response[v2.ReleaseResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReleaseDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ExtendReservation(context.Context, *connect.Request[v2.ExtendRequest]) (*connect.Response[v2.ExtendResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ExtendReservation is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) WatchDevices(context.Context, *connect.Request[v2.WatchRequest], *connect.ServerStream[v2.DeviceEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.WatchDevices is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListDevices(context.Context, *connect.Request[v2.ListDevicesRequest]) (*connect.Response[v2.ListDevicesResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ListDevices is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDevice(context.Context, *connect.Request[v2.GetDeviceRequest]) (*connect.Response[v2.GetDeviceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.GetDevice is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ReserveBatch(context.Context, *connect.Request[v2.ReserveBatchRequest]) (*connect.Response[v2.ReserveBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReserveBatch is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ReleaseBatch(context.Context, *connect.Request[v2.ReleaseBatchRequest]) (*connect.Response[v2.ReleaseBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReleaseBatch is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ExtendBatch(context.Context, *connect.Request[v2.ExtendBatchRequest]) (*connect.Response[v2.ExtendBatchResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ExtendBatch is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetQuotaUsage(context.Context, *connect.Request[v2.GetQuotaUsageRequest]) (*connect.Response[v2.GetQuotaUsageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.GetQuotaUsage is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) CreateBooking(context.Context, *connect.Request[v2.CreateBookingRequest]) (*connect.Response[v2.CreateBookingResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.CreateBooking is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ListBookings(context.Context, *connect.Request[v2.ListBookingsRequest]) (*connect.Response[v2.ListBookingsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ListBookings is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) CancelBooking(context.Context, *connect.Request[v2.CancelBookingRequest]) (*connect.Response[v2.CancelBookingResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.CancelBooking is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) ReportDeviceFailure(context.Context, *connect.Request[v2.ReportDeviceFailureRequest]) (*connect.Response[v2.ReportDeviceFailureResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.ReportDeviceFailure is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) GetDrainStatus(context.Context, *connect.Request[v2.GetDrainStatusRequest]) (*connect.Response[v2.GetDrainStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.GetDrainStatus is not implemented"))
+}
+
+func (UnimplementedDeviceServiceHandler) WhoAmI(context.Context, *connect.Request[v2.WhoAmIRequest]) (*connect.Response[v2.WhoAmIResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("devicefleet.v2.DeviceService.WhoAmI is not implemented"))
+}
@@ -0,0 +1,364 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+)
+
+const (
+	journalFile  = "journal.jsonl"
+	snapshotFile = "snapshot.json"
+	bookingsFile = "bookings.json"
+	devicesFile  = "devices.json"
+
+	defaultSnapshotEvery = 1000
+)
+
+// FileStore keeps reservations, bookings and added devices in an append-only
+// journal inside dir and periodically compacts it into snapshots.
+type FileStore struct {
+	mu            sync.Mutex
+	dir           string
+	journal       *os.File
+	state         map[string]device.Reservation
+	bookings      map[string]device.Booking
+	devices       map[string]device.AddedDevice
+	appended      int
+	SnapshotEvery int
+}
+
+// bookingRecord is a journal line for a booking change; reservation changes
+// are written as bare Reservations.
+type bookingRecord struct {
+	Booking        *device.Booking `json:"booking,omitempty"`
+	DeletedBooking string          `json:"deleted_booking,omitempty"`
+}
+
+// deviceRecord is a journal line for an added or removed device.
+type deviceRecord struct {
+	Device        *device.AddedDevice `json:"device,omitempty"`
+	DeletedDevice string              `json:"deleted_device,omitempty"`
+}
+
+type journalRecord struct {
+	device.Reservation
+	bookingRecord
+	deviceRecord
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{
+		dir:           dir,
+		state:         make(map[string]device.Reservation),
+		bookings:      make(map[string]device.Booking),
+		devices:       make(map[string]device.AddedDevice),
+		SnapshotEvery: defaultSnapshotEvery,
+	}
+	if err := s.readSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := s.replayJournal(); err != nil {
+		return nil, err
+	}
+
+	journal, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.journal = journal
+	return s, nil
+}
+
+func (s *FileStore) Save(r device.Reservation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(r); err != nil {
+		return err
+	}
+	s.apply(r)
+	return s.maybeCompactLocked()
+}
+
+func (s *FileStore) SaveBooking(b device.Booking) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(bookingRecord{Booking: &b}); err != nil {
+		return err
+	}
+	s.bookings[b.ID] = b
+	return s.maybeCompactLocked()
+}
+
+func (s *FileStore) DeleteBooking(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(bookingRecord{DeletedBooking: id}); err != nil {
+		return err
+	}
+	delete(s.bookings, id)
+	return s.maybeCompactLocked()
+}
+
+func (s *FileStore) LoadBookings() ([]device.Booking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bookingListLocked(), nil
+}
+
+func (s *FileStore) SaveDevice(d device.AddedDevice) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(deviceRecord{Device: &d}); err != nil {
+		return err
+	}
+	s.devices[d.ID] = d
+	return s.maybeCompactLocked()
+}
+
+func (s *FileStore) DeleteDevice(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.appendLocked(deviceRecord{DeletedDevice: id}); err != nil {
+		return err
+	}
+	delete(s.devices, id)
+	return s.maybeCompactLocked()
+}
+
+func (s *FileStore) LoadDevices() ([]device.AddedDevice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deviceListLocked(), nil
+}
+
+func (s *FileStore) appendLocked(record any) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := s.journal.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.journal.Sync()
+}
+
+func (s *FileStore) maybeCompactLocked() error {
+	s.appended++
+	if s.SnapshotEvery > 0 && s.appended >= s.SnapshotEvery {
+		return s.compactLocked()
+	}
+	return nil
+}
+
+func (s *FileStore) Load() ([]device.Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]device.Reservation, 0, len(s.state))
+	for _, r := range s.state {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DeviceID < result[j].DeviceID })
+	return result, nil
+}
+
+// Compact writes the current state to the snapshot and truncates the journal.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compactLocked()
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return errors.Join(s.compactLocked(), s.journal.Close())
+}
+
+func (s *FileStore) apply(r device.Reservation) {
+	if r.Empty() {
+		delete(s.state, r.DeviceID)
+		return
+	}
+	s.state[r.DeviceID] = r
+}
+
+func (s *FileStore) bookingListLocked() []device.Booking {
+	result := make([]device.Booking, 0, len(s.bookings))
+	for _, b := range s.bookings {
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func (s *FileStore) deviceListLocked() []device.AddedDevice {
+	result := make([]device.AddedDevice, 0, len(s.devices))
+	for _, d := range s.devices {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+func (s *FileStore) compactLocked() error {
+	reservations := make([]device.Reservation, 0, len(s.state))
+	for _, r := range s.state {
+		reservations = append(reservations, r)
+	}
+	if err := s.writeSnapshot(snapshotFile, reservations); err != nil {
+		return err
+	}
+	if err := s.writeSnapshot(bookingsFile, s.bookingListLocked()); err != nil {
+		return err
+	}
+	if err := s.writeSnapshot(devicesFile, s.deviceListLocked()); err != nil {
+		return err
+	}
+	if err := s.journal.Truncate(0); err != nil {
+		return err
+	}
+	s.appended = 0
+	return nil
+}
+
+func (s *FileStore) writeSnapshot(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// The journal is truncated once this returns, so the snapshot must be on
+	// disk under its final name first.
+	tmp := filepath.Join(s.dir, name+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *FileStore) readSnapshot() error {
+	var reservations []device.Reservation
+	if err := readJSON(filepath.Join(s.dir, snapshotFile), &reservations); err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		s.apply(r)
+	}
+
+	var bookings []device.Booking
+	if err := readJSON(filepath.Join(s.dir, bookingsFile), &bookings); err != nil {
+		return err
+	}
+	for _, b := range bookings {
+		s.bookings[b.ID] = b
+	}
+
+	var devices []device.AddedDevice
+	if err := readJSON(filepath.Join(s.dir, devicesFile), &devices); err != nil {
+		return err
+	}
+	for _, d := range devices {
+		s.devices[d.ID] = d
+	}
+	return nil
+}
+
+// readJSON decodes the file at path into v, leaving v untouched if the file
+// does not exist.
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// replayJournal applies journal records written after the last snapshot. A
+// torn final record from a crash mid-write is dropped from the file.
+func (s *FileStore) replayJournal() error {
+	path := filepath.Join(s.dir, journalFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	for line := 1; offset < len(data); line++ {
+		end := bytes.IndexByte(data[offset:], '\n')
+		var r journalRecord
+		if end < 0 || json.Unmarshal(data[offset:offset+end], &r) != nil {
+			if end >= 0 && offset+end+1 < len(data) {
+				return fmt.Errorf("%s:%d: corrupt record", journalFile, line)
+			}
+			return os.Truncate(path, int64(offset))
+		}
+		switch {
+		case r.Booking != nil:
+			s.bookings[r.Booking.ID] = *r.Booking
+		case r.DeletedBooking != "":
+			delete(s.bookings, r.DeletedBooking)
+		case r.Device != nil:
+			s.devices[r.Device.ID] = *r.Device
+		case r.DeletedDevice != "":
+			delete(s.devices, r.DeletedDevice)
+		default:
+			s.apply(r.Reservation)
+		}
+		offset += end + 1
+	}
+	return nil
+}
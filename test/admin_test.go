@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/audit"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
+)
+
+const adminToken = "ops-token"
+
+func setupAdminServer(t *testing.T, fleet *device.Fleet, configure ...func(*protoconnect.AdminServiceServer)) (protoconnect.DeviceServiceClient, protov2connect.AdminServiceClient, string, func()) {
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := audit.OpenFile(auditPath)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	svc := protoconnect.NewDeviceServiceServerWithFleet(fleet)
+	path, handler := protoconnect.NewDeviceServiceHandler(svc)
+	mux.Handle(path, handler)
+	adminSvc := protoconnect.NewAdminServiceServer(svc, map[string]string{"ops": adminToken}, auditLog)
+	for _, f := range configure {
+		f(adminSvc)
+	}
+	adminPath, adminHandler := protov2connect.NewAdminServiceHandler(adminSvc)
+	mux.Handle(adminPath, adminHandler)
+
+	server := httptest.NewServer(mux)
+	return protoconnect.NewDeviceServiceClient(http.DefaultClient, server.URL),
+		protov2connect.NewAdminServiceClient(http.DefaultClient, server.URL),
+		auditPath,
+		func() {
+			server.Close()
+			auditLog.Close()
+		}
+}
+
+func asAdmin[T any](msg *T, token string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestAdminRequiresTokenAndAudits(t *testing.T) {
+	_, admin, auditPath, cleanup := setupAdminServer(t, device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	_, err := admin.ForceRelease(context.Background(), connect.NewRequest(&protov2.ForceReleaseRequest{DeviceId: "iphone-0", Reason: "test"}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_UNAUTHENTICATED {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+	_, err = admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: "iphone-0", Reason: "test"}, "wrong"))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated with a wrong token, got %v", err)
+	}
+	_, err = admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: "iphone-0"}, adminToken))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodeInvalidArgument || detail.Field != "reason" {
+		t.Fatalf("expected InvalidArgument on reason, got %v", err)
+	}
+
+	records, err := audit.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected every rejected call audited, got %+v", records)
+	}
+	if records[0].Actor != "" || records[0].Error == "" || records[2].Actor != "ops" || records[2].Action != "force_release" {
+		t.Fatalf("unexpected audit records: %+v", records)
+	}
+}
+
+func TestAdminForceReleaseAndTransfer(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2))
+	client, admin, auditPath, cleanup := setupAdminServer(t, fleet)
+	defer cleanup()
+
+	first, _ := reserveAs(client, "alice", "iphone")
+	second, _ := reserveAs(client, "alice", "iphone")
+
+	transferred, err := admin.TransferReservation(context.Background(), asAdmin(&protov2.TransferReservationRequest{
+		DeviceId: first.DeviceId,
+		ToUser:   "bob",
+		Reason:   "alice is on leave",
+	}, adminToken))
+	if err != nil {
+		t.Fatalf("TransferReservation failed: %v", err)
+	}
+	r := transferred.Msg.Reservation
+	if r.User != "bob" || r.LeaseToken == first.LeaseToken || !r.ExpiresAt.AsTime().Equal(first.ExpiresAt.AsTime()) {
+		t.Fatalf("expected bob to hold %s under a new lease until %v, got %+v", first.DeviceId, first.ExpiresAt.AsTime(), r)
+	}
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{DeviceId: first.DeviceId, LeaseToken: first.LeaseToken}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected alice's old lease to stop working, got %v", err)
+	}
+
+	released, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: second.DeviceId, Reason: "stuck"}, adminToken))
+	if err != nil {
+		t.Fatalf("ForceRelease failed: %v", err)
+	}
+	if released.Msg.Reservation.User != "alice" || released.Msg.Reservation.State != protov2.ReservationState_RESERVATION_STATE_RELEASED {
+		t.Fatalf("unexpected released reservation: %+v", released.Msg.Reservation)
+	}
+	_, err = admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: second.DeviceId, Reason: "again"}, adminToken))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("expected FailedPrecondition releasing a free device, got %v", err)
+	}
+
+	records, _ := audit.ReadFile(auditPath)
+	if len(records) != 3 || records[0].Action != "transfer" || records[0].Previous != "alice" || records[0].Target != "bob" ||
+		records[1].Previous != "alice" || records[1].Reason != "stuck" || records[2].Error == "" {
+		t.Fatalf("unexpected audit records: %+v", records)
+	}
+}
+
+func TestAdminReleaseUserAndManageDevices(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2), device.NewDevicePool("pixel", 1))
+	client, admin, _, cleanup := setupAdminServer(t, fleet)
+	defer cleanup()
+
+	reserveAs(client, "alice", "iphone")
+	reserveAs(client, "alice", "pixel")
+	reserveAs(client, "bob", "iphone")
+
+	resp, err := admin.ReleaseUserReservations(context.Background(), asAdmin(&protov2.ReleaseUserReservationsRequest{User: "alice", Reason: "left"}, adminToken))
+	if err != nil {
+		t.Fatalf("ReleaseUserReservations failed: %v", err)
+	}
+	if len(resp.Msg.Reservations) != 2 {
+		t.Fatalf("expected alice's 2 reservations released, got %v", resp.Msg.Reservations)
+	}
+	for _, d := range fleet.Snapshot() {
+		if d.ReservedBy == "alice" {
+			t.Fatalf("expected alice to hold nothing, got %+v", d)
+		}
+	}
+
+	added, err := admin.AddDevice(context.Background(), asAdmin(&protov2.AddDeviceRequest{
+		Device: &protov2.Device{Id: "tablet-1", Type: "tablet", Labels: map[string]string{"os": "18"}},
+		Reason: "new hardware",
+	}, adminToken))
+	if err != nil {
+		t.Fatalf("AddDevice failed: %v", err)
+	}
+	if added.Msg.Device.State != protov2.DeviceState_DEVICE_STATE_AVAILABLE {
+		t.Fatalf("expected the new device available, got %+v", added.Msg.Device)
+	}
+	_, err = admin.AddDevice(context.Background(), asAdmin(&protov2.AddDeviceRequest{
+		Device: &protov2.Device{Id: "tablet-1", Type: "tablet"},
+		Reason: "again",
+	}, adminToken))
+	if connect.CodeOf(err) != connect.CodeAlreadyExists || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_DEVICE_EXISTS {
+		t.Fatalf("expected AlreadyExists for a duplicate ID, got %v", err)
+	}
+	if _, err := reserveAs(client, "carol", "tablet"); err != nil {
+		t.Fatalf("expected the added device to be reservable: %v", err)
+	}
+
+	removed, err := admin.RemoveDevice(context.Background(), asAdmin(&protov2.RemoveDeviceRequest{DeviceId: "tablet-1", Reason: "broken"}, adminToken))
+	if err != nil {
+		t.Fatalf("RemoveDevice failed: %v", err)
+	}
+	if removed.Msg.Removed || removed.Msg.Device.State != protov2.DeviceState_DEVICE_STATE_RETIRING {
+		t.Fatalf("expected a reserved device to be retired rather than removed, got %+v", removed.Msg)
+	}
+	removed, err = admin.RemoveDevice(context.Background(), asAdmin(&protov2.RemoveDeviceRequest{DeviceId: "pixel-0", Reason: "broken"}, adminToken))
+	if err != nil || !removed.Msg.Removed {
+		t.Fatalf("expected a free device to be removed, got %+v, %v", removed, err)
+	}
+	if _, err := fleet.Pool("pixel"); err == nil {
+		t.Fatal("expected the empty pixel pool to be gone")
+	}
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/config"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
+)
+
+var hmacKey = []byte("0123456789abcdef0123456789abcdef")
+
+func setupAuthServer(t *testing.T, fleet *device.Fleet, authn auth.Authenticator, configure ...func(*protoconnect.AuthInterceptor)) (protoconnect.DeviceServiceClient, protov2connect.DeviceServiceClient, func()) {
+	interceptor := protoconnect.NewAuthInterceptor(authn)
+	for _, f := range configure {
+		f(interceptor)
+	}
+	interceptors := connect.WithInterceptors(interceptor)
+	svc := protoconnect.NewDeviceServiceServerWithFleet(fleet)
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewDeviceServiceHandler(svc, interceptors))
+	mux.Handle(protov2connect.NewDeviceServiceHandler(protoconnect.NewDeviceServiceV2Server(svc), interceptors))
+
+	server := httptest.NewServer(mux)
+	return protoconnect.NewDeviceServiceClient(http.DefaultClient, server.URL),
+		protov2connect.NewDeviceServiceClient(http.DefaultClient, server.URL),
+		server.Close
+}
+
+func withToken[T any](msg *T, token string) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestReserveUsesAuthenticatedUser(t *testing.T) {
+	tokens, err := auth.NewStaticTokens([]auth.TokenEntry{
+		{Token: "alice-token", User: "alice"},
+		{Token: "bob-token", User: "bob"},
+	})
+	if err != nil {
+		t.Fatalf("NewStaticTokens failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2))
+	client, clientV2, cleanup := setupAuthServer(t, fleet, tokens)
+	defer cleanup()
+
+	_, err = client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{User: "alice", DeviceType: "iphone"}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_UNAUTHENTICATED {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+	_, err = client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{User: "alice", DeviceType: "iphone"}, "guess"))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated with an unknown token, got %v", err)
+	}
+
+	resp, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{User: "mallory", DeviceType: "iphone"}, "alice-token"))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	for _, d := range fleet.Snapshot() {
+		if d.ID == resp.Msg.DeviceId && d.ReservedBy != "alice" {
+			t.Fatalf("expected the reservation made for alice, got %+v", d)
+		}
+	}
+
+	_, err = client.ReleaseDevice(context.Background(), withToken(&proto.ReleaseRequest{DeviceId: resp.Msg.DeviceId, User: "alice"}, "bob-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected bob not to release alice's device by naming her, got %v", err)
+	}
+	if _, err := client.ReleaseDevice(context.Background(), withToken(&proto.ReleaseRequest{DeviceId: resp.Msg.DeviceId}, "alice-token")); err != nil {
+		t.Fatalf("expected alice to release by identity alone: %v", err)
+	}
+
+	who, err := clientV2.WhoAmI(context.Background(), withToken(&protov2.WhoAmIRequest{}, "bob-token"))
+	if err != nil || !who.Msg.Authenticated || who.Msg.User != "bob" {
+		t.Fatalf("expected WhoAmI to report bob, got %+v, %v", who, err)
+	}
+}
+
+func TestAuthCoversStreams(t *testing.T) {
+	tokens, _ := auth.NewStaticTokens([]auth.TokenEntry{{Token: "alice-token", User: "alice"}})
+	_, clientV2, cleanup := setupAuthServer(t, device.NewFleet(device.NewDevicePool("iphone", 1)), tokens)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := clientV2.WatchDevices(ctx, connect.NewRequest(&protov2.WatchRequest{}))
+	if err == nil && stream.Receive() {
+		t.Fatal("expected an unauthenticated watch to be refused")
+	}
+	if err == nil {
+		err = stream.Err()
+	}
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+
+	stream, err = clientV2.WatchDevices(ctx, withToken(&protov2.WatchRequest{}, "alice-token"))
+	if err != nil || !stream.Receive() {
+		t.Fatalf("expected an authenticated watch to get a snapshot: %v", stream.Err())
+	}
+}
+
+func TestHMACTokens(t *testing.T) {
+	tokens, err := auth.NewHMACTokens(hmacKey)
+	if err != nil {
+		t.Fatalf("NewHMACTokens failed: %v", err)
+	}
+	if _, err := auth.NewHMACTokens([]byte("short")); err == nil {
+		t.Fatal("expected a short key to be rejected")
+	}
+
+	token, err := tokens.Issue(auth.Identity{User: "alice", Groups: []string{"qa"}}, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	id, err := tokens.Authenticate(context.Background(), token)
+	if err != nil || id.User != "alice" || !id.InGroup("qa") {
+		t.Fatalf("expected alice in qa, got %+v, %v", id, err)
+	}
+
+	tampered := token[:len(token)-2] + "xx"
+	if _, err := tokens.Authenticate(context.Background(), tampered); err == nil {
+		t.Fatal("expected a tampered token to be rejected")
+	}
+	other, _ := auth.NewHMACTokens([]byte("fedcba9876543210fedcba9876543210"))
+	if _, err := other.Authenticate(context.Background(), token); err == nil {
+		t.Fatal("expected a token signed with another key to be rejected")
+	}
+	expired, _ := tokens.Issue(auth.Identity{User: "alice"}, -time.Minute)
+	if _, err := tokens.Authenticate(context.Background(), expired); !errors.Is(err, auth.ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+
+	// Static tokens and signed tokens work side by side.
+	static, _ := auth.NewStaticTokens([]auth.TokenEntry{{Token: "bob-token", User: "bob"}})
+	client, _, cleanup := setupAuthServer(t, device.NewFleet(device.NewDevicePool("iphone", 2)), auth.Chain{static, tokens})
+	defer cleanup()
+	if _, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, token)); err != nil {
+		t.Fatalf("expected a signed token to authenticate: %v", err)
+	}
+	if _, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, "bob-token")); err != nil {
+		t.Fatalf("expected a static token to authenticate: %v", err)
+	}
+	_, err = client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, expired))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected Unauthenticated naming the expiry, got %v", err)
+	}
+}
+
+func TestConfigAuthenticator(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "tokens.json")
+	keyFile := filepath.Join(dir, "hmac.key")
+	os.WriteFile(tokenFile, []byte(`{"tokens": [{"token": "alice-token", "user": "alice", "groups": ["qa"]}]}`), 0o600)
+	os.WriteFile(keyFile, append(hmacKey, '\n'), 0o600)
+
+	cfg, err := config.Load(writeFleetFile(t, `{
+		"devices": [{"type": "iphone"}],
+		"auth": {"token_file": "`+tokenFile+`", "hmac_key_file": "`+keyFile+`"}
+	}`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	authn, err := cfg.Authenticator()
+	if err != nil {
+		t.Fatalf("Authenticator failed: %v", err)
+	}
+	if id, err := authn.Authenticate(context.Background(), "alice-token"); err != nil || id.User != "alice" || !id.InGroup("qa") {
+		t.Fatalf("expected the token file to authenticate alice, got %+v, %v", id, err)
+	}
+	signer, _ := cfg.HMACTokens()
+	token, _ := signer.Issue(auth.Identity{User: "bob"}, time.Hour)
+	if id, err := authn.Authenticate(context.Background(), token); err != nil || id.User != "bob" {
+		t.Fatalf("expected a signed token to authenticate bob, got %+v, %v", id, err)
+	}
+
+	if authn, err := config.Default().Authenticator(); authn != nil || err != nil {
+		t.Fatalf("expected no authenticator by default, got %v, %v", authn, err)
+	}
+
+	_, err = config.Load(writeFleetFile(t, `{"devices": [{"type": "iphone"}], "admin": {"groups": ["ops"]}}`))
+	if err == nil || !strings.Contains(err.Error(), "admin.groups: needs an auth backend") {
+		t.Fatalf("expected admin groups without auth to be rejected, got %v", err)
+	}
+
+	os.WriteFile(tokenFile, []byte(`{"tokens": [{"token": "t", "user": "a"}, {"token": "t", "user": "b"}]}`), 0o600)
+	if _, err := cfg.Authenticator(); err == nil || !strings.Contains(err.Error(), "duplicate token") {
+		t.Fatalf("expected duplicate tokens to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+func TestReserveBatchAcrossPools(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1), device.NewDevicePool("pixel", 1))
+	_, client, cleanup := setupV2Server(fleet)
+	defer cleanup()
+
+	resp, err := client.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User: "alice",
+		Devices: []*protov2.DeviceRequirement{
+			{DeviceType: "iphone"},
+			{DeviceType: "pixel"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("ReserveBatch failed: %v", err)
+	}
+	if len(resp.Msg.Reservations) != 2 || resp.Msg.Reservations[0].DeviceId != "iphone-0" || resp.Msg.Reservations[1].DeviceId != "pixel-0" {
+		t.Fatalf("unexpected reservations: %v", resp.Msg.Reservations)
+	}
+	for _, r := range resp.Msg.Reservations {
+		if r.LeaseToken != resp.Msg.GroupLease {
+			t.Fatalf("expected every reservation to share the group lease, got %s", r.LeaseToken)
+		}
+	}
+
+	before := resp.Msg.Reservations[0].ExpiresAt.AsTime()
+	extended, err := client.ExtendBatch(context.Background(), connect.NewRequest(&protov2.ExtendBatchRequest{
+		GroupLease: resp.Msg.GroupLease,
+		Extension:  durationpb.New(time.Minute),
+	}))
+	if err != nil {
+		t.Fatalf("ExtendBatch failed: %v", err)
+	}
+	for _, r := range extended.Msg.Reservations {
+		if !r.ExpiresAt.AsTime().After(before) {
+			t.Fatalf("expected %s to be extended past %v, got %v", r.DeviceId, before, r.ExpiresAt.AsTime())
+		}
+	}
+
+	released, err := client.ReleaseBatch(context.Background(), connect.NewRequest(&protov2.ReleaseBatchRequest{
+		GroupLease: resp.Msg.GroupLease,
+	}))
+	if err != nil {
+		t.Fatalf("ReleaseBatch failed: %v", err)
+	}
+	if len(released.Msg.Reservations) != 2 {
+		t.Fatalf("expected both devices released, got %v", released.Msg.Reservations)
+	}
+	for _, d := range fleet.Snapshot() {
+		if !device.IsAvailable(&d) {
+			t.Fatalf("expected %s to be available after ReleaseBatch", d.ID)
+		}
+	}
+
+	_, err = client.ReleaseBatch(context.Background(), connect.NewRequest(&protov2.ReleaseBatchRequest{
+		GroupLease: resp.Msg.GroupLease,
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_NOT_RESERVED {
+		t.Fatalf("expected FailedPrecondition NOT_RESERVED, got %v", err)
+	}
+}
+
+func TestReserveBatchIsAllOrNothing(t *testing.T) {
+	iphones := device.NewDevicePool("iphone", 1)
+	pixels := device.NewDevicePool("pixel", 1)
+	_, client, cleanup := setupV2Server(device.NewFleet(iphones, pixels))
+	defer cleanup()
+
+	pixels.Reserve("bob", "pixel", time.Minute)
+
+	_, err := client.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User: "alice",
+		Devices: []*protov2.DeviceRequirement{
+			{DeviceType: "iphone"},
+			{DeviceType: "pixel"},
+		},
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	if d, _ := iphones.Get("iphone-0"); !device.IsAvailable(&d) {
+		t.Fatal("expected iphone-0 to stay free when the batch failed")
+	}
+}
+
+func TestReserveBatchRejectsUnsatisfiable(t *testing.T) {
+	_, client, cleanup := setupV2Server(device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	_, err := client.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User: "alice",
+		Devices: []*protov2.DeviceRequirement{
+			{DeviceType: "iphone"},
+			{DeviceType: "iphone"},
+		},
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "devices" {
+		t.Fatalf("expected InvalidArgument on devices, got %v", err)
+	}
+
+	_, err = client.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User:    "alice",
+		Devices: []*protov2.DeviceRequirement{{DeviceType: "android"}},
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("expected NotFound for unknown type, got %v", err)
+	}
+}
+
+func TestReserveBatchMatchesNarrowRequirementsFirst(t *testing.T) {
+	pool := device.NewDevicePoolWithDevices("pixel", []*device.Device{
+		{ID: "pixel-8", Type: "pixel", Labels: map[string]string{"api": "34"}},
+		{ID: "pixel-6", Type: "pixel", Labels: map[string]string{"api": "32"}},
+	})
+	_, client, cleanup := setupV2Server(device.NewFleet(pool))
+	defer cleanup()
+
+	resp, err := client.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User: "alice",
+		Devices: []*protov2.DeviceRequirement{
+			{DeviceType: "pixel"},
+			{DeviceType: "pixel", Selector: "api>=33"},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("ReserveBatch failed: %v", err)
+	}
+	if resp.Msg.Reservations[0].DeviceId != "pixel-6" || resp.Msg.Reservations[1].DeviceId != "pixel-8" {
+		t.Fatalf("unexpected assignment: %v", resp.Msg.Reservations)
+	}
+}
+
+func TestConcurrentBatchesDoNotDeadlock(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1), device.NewDevicePool("pixel", 1))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reqs := []device.Requirement{{Type: "iphone"}, {Type: "pixel"}}
+			if i%2 == 1 {
+				reqs[0], reqs[1] = reqs[1], reqs[0]
+			}
+			if _, err := fleet.ReserveGroup("user", reqs, time.Minute); err == nil {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent group reservations deadlocked")
+	}
+	if granted != 1 {
+		t.Fatalf("expected exactly one group to win, got %d", granted)
+	}
+}
@@ -0,0 +1,219 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	"github.com/gitRasheed/FleetRPC/internal/store"
+)
+
+func bookingRequest(deviceID, user string, start, end time.Time) *connect.Request[protov2.CreateBookingRequest] {
+	return connect.NewRequest(&protov2.CreateBookingRequest{
+		DeviceId:  deviceID,
+		User:      user,
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(end),
+	})
+}
+
+func TestCreateBookingRejectsOverlaps(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1))
+	_, client, cleanup := setupV2Server(fleet)
+	defer cleanup()
+
+	now := time.Now()
+	first, err := client.CreateBooking(context.Background(), bookingRequest("iphone-0", "alice", now.Add(time.Hour), now.Add(2*time.Hour)))
+	if err != nil {
+		t.Fatalf("CreateBooking failed: %v", err)
+	}
+	if first.Msg.Booking.LeaseToken == "" {
+		t.Fatal("expected the new booking to carry a lease token")
+	}
+
+	_, err = client.CreateBooking(context.Background(), bookingRequest("iphone-0", "bob", now.Add(90*time.Minute), now.Add(3*time.Hour)))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for an overlapping booking, got %v", err)
+	}
+	if detail := errorDetail(t, err); detail.Reason != proto.ErrorReason_ERROR_REASON_BOOKING_CONFLICT || detail.BookingId != first.Msg.Booking.Id {
+		t.Fatalf("expected BOOKING_CONFLICT naming %s, got %v", first.Msg.Booking.Id, detail)
+	}
+
+	if _, err := client.CreateBooking(context.Background(), bookingRequest("iphone-0", "bob", now.Add(2*time.Hour), now.Add(3*time.Hour))); err != nil {
+		t.Fatalf("expected a back-to-back booking to succeed: %v", err)
+	}
+
+	_, err = client.CreateBooking(context.Background(), bookingRequest("iphone-0", "bob", now.Add(-time.Minute), now.Add(time.Minute)))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodeInvalidArgument || detail.Field != "start_time" {
+		t.Fatalf("expected InvalidArgument on start_time for a past start, got %v", err)
+	}
+	_, err = client.CreateBooking(context.Background(), bookingRequest("iphone-0", "bob", now.Add(4*time.Hour), now.Add(20*time.Hour)))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodeInvalidArgument || detail.Field != "end_time" {
+		t.Fatalf("expected InvalidArgument on end_time for a booking over the max lease, got %v", err)
+	}
+
+	listed, err := client.ListBookings(context.Background(), connect.NewRequest(&protov2.ListBookingsRequest{User: "alice"}))
+	if err != nil {
+		t.Fatalf("ListBookings failed: %v", err)
+	}
+	if len(listed.Msg.Bookings) != 1 || listed.Msg.Bookings[0].Id != first.Msg.Booking.Id || listed.Msg.Bookings[0].LeaseToken != "" {
+		t.Fatalf("expected alice's booking without its lease token, got %v", listed.Msg.Bookings)
+	}
+
+	_, err = client.CancelBooking(context.Background(), connect.NewRequest(&protov2.CancelBookingRequest{BookingId: first.Msg.Booking.Id, User: "bob"}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected PermissionDenied cancelling another user's booking, got %v", err)
+	}
+	if _, err := client.CancelBooking(context.Background(), connect.NewRequest(&protov2.CancelBookingRequest{
+		BookingId:  first.Msg.Booking.Id,
+		LeaseToken: first.Msg.Booking.LeaseToken,
+	})); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	_, err = client.CancelBooking(context.Background(), connect.NewRequest(&protov2.CancelBookingRequest{BookingId: first.Msg.Booking.Id, User: "alice"}))
+	if connect.CodeOf(err) != connect.CodeNotFound || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_UNKNOWN_BOOKING {
+		t.Fatalf("expected NotFound UNKNOWN_BOOKING after cancelling, got %v", err)
+	}
+}
+
+func TestReservationsRespectUpcomingBookings(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 2)
+	fleet := device.NewFleet(pool)
+
+	if _, err := fleet.Book("iphone-0", "alice", time.Now().Add(time.Minute), time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+
+	long, ok := pool.Reserve("bob", "iphone", 5*time.Minute)
+	if !ok || long.ID != "iphone-1" {
+		t.Fatalf("expected a reservation past the booking start to skip iphone-0, got %+v", long)
+	}
+	short, ok := pool.Reserve("carol", "iphone", 10*time.Second)
+	if !ok || short.ID != "iphone-0" {
+		t.Fatalf("expected a reservation ending before the booking to get iphone-0, got %+v", short)
+	}
+
+	extended, err := pool.Extend(short.ID, "", short.LeaseToken, time.Hour, 8*time.Hour)
+	if err != nil {
+		t.Fatalf("Extend failed: %v", err)
+	}
+	if extended.ExpiresAt.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("expected the extension to stop at the booking start, got %v", extended.ExpiresAt)
+	}
+	_, err = pool.Extend(short.ID, "", short.LeaseToken, time.Hour, 8*time.Hour)
+	var conflict *device.BookingConflict
+	if !errors.As(err, &conflict) || conflict.DeviceID != "iphone-0" {
+		t.Fatalf("expected a BookingConflict extending into the booking, got %v", err)
+	}
+}
+
+func TestBookingActivatesIntoReservation(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+
+	end := time.Now().Add(time.Hour)
+	booking, err := fleet.Book("iphone-0", "alice", time.Now().Add(20*time.Millisecond), end)
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	pool.Expire()
+
+	d := pool.All()[0]
+	if d.ReservedBy != "alice" || d.LeaseToken != booking.LeaseToken || d.ExpiresAt.Sub(end).Abs() > time.Second {
+		t.Fatalf("expected the booking to become alice's reservation until %v, got %+v", end, d)
+	}
+	if len(fleet.Bookings()) != 0 {
+		t.Fatalf("expected the activated booking to be gone, got %v", fleet.Bookings())
+	}
+	if err := pool.Release(d.ID, "", booking.LeaseToken); err != nil {
+		t.Fatalf("expected the booking's lease token to release the device: %v", err)
+	}
+}
+
+func TestBookingsRespectQuotas(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 3)
+	fleet := device.NewFleet(pool)
+	fleet.SetQuotas(device.Quotas{PerUser: 1})
+	_, client, cleanup := setupV2Server(fleet)
+	defer cleanup()
+
+	booking, err := fleet.Book("iphone-0", "alice", time.Now().Add(20*time.Millisecond), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	held, _ := pool.Reserve("alice", "iphone", time.Minute)
+
+	now := time.Now()
+	_, err = client.CreateBooking(context.Background(), bookingRequest("iphone-2", "alice", now.Add(time.Hour), now.Add(2*time.Hour)))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodeResourceExhausted || detail.Reason != proto.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED {
+		t.Fatalf("expected QUOTA_EXCEEDED booking over quota, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	pool.Expire()
+	if d, _ := pool.Get("iphone-0"); d.ReservedBy != "" || len(fleet.Bookings()) != 1 {
+		t.Fatalf("expected the booking held back while alice is at her quota, got %+v", d)
+	}
+
+	if err := pool.Release(held.ID, "alice", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	pool.Expire()
+	if d, _ := pool.Get("iphone-0"); d.ReservedBy != "alice" || d.LeaseToken != booking.LeaseToken {
+		t.Fatalf("expected the booking to activate once alice is under quota, got %+v", d)
+	}
+}
+
+func TestBookingsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	fileStore, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2))
+	if _, err := fleet.Restore(fileStore); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	start := time.Now().Add(time.Hour)
+	kept, err := fleet.Book("iphone-0", "alice", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	if err := fileStore.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	cancelled, err := fleet.Book("iphone-1", "bob", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Book failed: %v", err)
+	}
+	if _, err := fleet.CancelBooking(cancelled.ID, "bob", ""); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	fileStore.Close()
+
+	reopened, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	restarted := device.NewFleet(device.NewDevicePool("iphone", 2))
+	if _, err := restarted.Restore(reopened); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	bookings := restarted.Bookings()
+	if len(bookings) != 1 || bookings[0].ID != kept.ID || bookings[0].LeaseToken != kept.LeaseToken || !bookings[0].Start.Equal(start) {
+		t.Fatalf("expected only %s restored, got %+v", kept.ID, bookings)
+	}
+}
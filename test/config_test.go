@@ -0,0 +1,90 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitRasheed/FleetRPC/internal/config"
+)
+
+func writeFleetFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fleet.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("write fleet file: %v", err)
+	}
+	return path
+}
+
+func TestLoadExampleFleetFile(t *testing.T) {
+	cfg, err := config.Load("../fleet.example.json")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	fleet := cfg.Fleet()
+	if len(fleet.All()) != len(cfg.Devices) {
+		t.Fatalf("expected %d devices, got %d", len(cfg.Devices), len(fleet.All()))
+	}
+
+	pool, err := fleet.Pool("emulator")
+	if err != nil {
+		t.Fatalf("expected emulator pool: %v", err)
+	}
+	if !pool.Has("emulator-0") || !pool.Has("emulator-1") {
+		t.Fatalf("expected generated emulator IDs, got %v", pool.All())
+	}
+
+	dev, ok := pool.Reserve("someone", "emulator", time.Minute)
+	if !ok || dev.Labels["api_level"] != "33" {
+		t.Fatalf("expected labelled emulator, got %+v", dev)
+	}
+
+	if cfg.LeasePolicy().MinTTL != 30*time.Second {
+		t.Fatalf("expected min ttl 30s, got %s", cfg.LeasePolicy().MinTTL)
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	path := writeFleetFile(t, `{
+		"leases": {"min_ttl": "2h"},
+		"admin": {"tokens": {"ops": "t", "oncall": "t", "intern": ""}},
+		"auth": {"oidc": {"jwks_file": "jwks.json", "jwks_url": "https://idp.example.com/jwks"}},
+		"devices": [
+			{"id": "a"},
+			{"id": "x", "type": "iphone"},
+			{"id": "x", "type": "iphone"}
+		]
+	}`)
+
+	_, err := config.Load(path)
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	for _, want := range []string{
+		"devices[0]: type is required",
+		`devices[2]: id "x" already used by devices[1]`,
+		"leases: min_ttl 2h0m0s is greater than max_ttl 1h0m0s",
+		`admin.tokens["intern"]: must not be empty`,
+		`admin.tokens["ops"]: same token as "oncall"`,
+		"auth.oidc.issuer: is required",
+		"auth.oidc.audience: is required",
+		"auth.oidc: exactly one of jwks_file and jwks_url is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	path := writeFleetFile(t, `{"devices": [{"type": "iphone", "colour": "blue"}]}`)
+
+	_, err := config.Load(path)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "colour"`) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+func TestDrainLetsHolderFinish(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2))
+	v1, client, cleanup := setupV2Server(fleet)
+	defer cleanup()
+	_, admin, _, adminCleanup := setupAdminServer(t, fleet)
+	defer adminCleanup()
+
+	held, err := reserveAs(v1, "alice", "iphone")
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+
+	_, err = admin.DrainDevices(context.Background(), connect.NewRequest(&protov2.DrainRequest{DeviceType: "iphone", Reason: "upgrade"}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated without an admin token, got %v", err)
+	}
+	_, err = admin.DrainDevices(context.Background(), asAdmin(&protov2.DrainRequest{Reason: "upgrade"}, adminToken))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("expected InvalidArgument without a device or type, got %v", err)
+	}
+	drain, err := admin.DrainDevices(context.Background(), asAdmin(&protov2.DrainRequest{DeviceType: "iphone", Reason: "upgrade"}, adminToken))
+	if err != nil {
+		t.Fatalf("DrainDevices failed: %v", err)
+	}
+	if drain.Msg.Drained {
+		t.Fatal("expected the drain to be incomplete while alice holds a device")
+	}
+	states := map[string]protov2.DeviceState{}
+	for _, d := range drain.Msg.Devices {
+		states[d.Id] = d.State
+	}
+	if states[held.DeviceId] != protov2.DeviceState_DEVICE_STATE_DRAINING || states["iphone-1"] != protov2.DeviceState_DEVICE_STATE_DRAINED {
+		t.Fatalf("expected the held device draining and the free one drained, got %v", states)
+	}
+
+	_, err = reserveAs(v1, "bob", "iphone")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_NO_DEVICES_AVAILABLE {
+		t.Fatalf("expected a drained pool to hand out nothing, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&protov2.WatchRequest{DeviceIds: []string{held.DeviceId}}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	if !stream.Receive() {
+		t.Fatalf("expected snapshot event: %v", stream.Err())
+	}
+	if !stream.Msg().Device.Draining {
+		t.Fatalf("expected the snapshot to show draining, got %+v", stream.Msg().Device)
+	}
+
+	if _, err := v1.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   held.DeviceId,
+		LeaseToken: held.LeaseToken,
+	})); err != nil {
+		t.Fatalf("ReleaseDevice failed: %v", err)
+	}
+	var kinds []protov2.EventType
+	for len(kinds) < 2 && stream.Receive() {
+		kinds = append(kinds, stream.Msg().Type)
+	}
+	if len(kinds) != 2 || kinds[0] != protov2.EventType_EVENT_TYPE_RELEASED || kinds[1] != protov2.EventType_EVENT_TYPE_DRAINED {
+		t.Fatalf("expected released then drained, got %v", kinds)
+	}
+
+	status, err := client.GetDrainStatus(context.Background(), connect.NewRequest(&protov2.GetDrainStatusRequest{DeviceType: "iphone"}))
+	if err != nil {
+		t.Fatalf("GetDrainStatus failed: %v", err)
+	}
+	if !status.Msg.Drained {
+		t.Fatalf("expected the pool to be drained, got %v", status.Msg.Devices)
+	}
+
+	if _, err := admin.UndrainDevices(context.Background(), asAdmin(&protov2.UndrainRequest{DeviceType: "iphone", Reason: "upgraded"}, adminToken)); err != nil {
+		t.Fatalf("UndrainDevices failed: %v", err)
+	}
+	if _, err := reserveAs(v1, "bob", "iphone"); err != nil {
+		t.Fatalf("expected devices back in service after undrain: %v", err)
+	}
+}
+
+func TestDrainingPoolDrainsNewDevices(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	if _, err := fleet.DrainType("iphone"); err != nil {
+		t.Fatalf("DrainType failed: %v", err)
+	}
+
+	w := pool.Enqueue("alice", "iphone", time.Minute)
+	if _, err := fleet.Apply([]*device.Device{
+		{ID: "iphone-0", Type: "iphone"},
+		{ID: "iphone-new", Type: "iphone"},
+	}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	added, _ := pool.Get("iphone-new")
+	if !added.Draining {
+		t.Fatalf("expected a device added to a draining pool to drain, got %+v", added)
+	}
+
+	if _, err := fleet.Undrain("iphone-new"); err != nil {
+		t.Fatalf("Undrain failed: %v", err)
+	}
+	select {
+	case d := <-w.Granted():
+		if d.ID != "iphone-new" {
+			t.Fatalf("expected the undrained device, got %+v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to get the undrained device")
+	}
+}
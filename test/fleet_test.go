@@ -0,0 +1,60 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+func TestFleetRoutesByDeviceType(t *testing.T) {
+	fleet := device.NewFleet(
+		device.NewDevicePool("iphone", 1),
+		device.NewDevicePool("pixel", 2),
+	)
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "android-dev",
+		DeviceType: "pixel",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	if !strings.HasPrefix(resp.Msg.DeviceId, "pixel-") {
+		t.Fatalf("expected a pixel device, got '%s'", resp.Msg.DeviceId)
+	}
+
+	releaseResp, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   resp.Msg.DeviceId,
+		LeaseToken: resp.Msg.LeaseToken,
+	}))
+	if err != nil {
+		t.Fatalf("ReleaseDevice failed: %v", err)
+	}
+	if releaseResp.Msg.Status != "released" {
+		t.Fatalf("expected status 'released', got '%s'", releaseResp.Msg.Status)
+	}
+
+	if len(fleet.All()) != 3 {
+		t.Fatalf("expected 3 devices across the fleet, got %d", len(fleet.All()))
+	}
+}
+
+func TestFleetRejectsUnknownType(t *testing.T) {
+	client, cleanup := setupFleetServer(device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "someone",
+		DeviceType: "blackberry",
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("expected NotFound for unknown type, got %v", err)
+	}
+}
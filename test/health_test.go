@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+func TestUnhealthyDevicesAreNotAllocated(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1))
+	v1, client, cleanup := setupV2Server(fleet)
+	defer cleanup()
+	_, admin, _, adminCleanup := setupAdminServer(t, fleet)
+	defer adminCleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&protov2.WatchRequest{}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	if !stream.Receive() {
+		t.Fatalf("expected snapshot event: %v", stream.Err())
+	}
+
+	_, err = admin.SetDeviceHealth(context.Background(), connect.NewRequest(&protov2.SetDeviceHealthRequest{
+		DeviceId: "iphone-0",
+		Health:   protov2.HealthState_HEALTH_STATE_MAINTENANCE,
+		Reason:   "cracked screen",
+	}))
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated without an admin token, got %v", err)
+	}
+	_, err = admin.SetDeviceHealth(context.Background(), asAdmin(&protov2.SetDeviceHealthRequest{
+		DeviceId: "iphone-0",
+		Health:   protov2.HealthState_HEALTH_STATE_MAINTENANCE,
+	}, adminToken))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodeInvalidArgument || detail.Field != "reason" {
+		t.Fatalf("expected InvalidArgument on reason, got %v", err)
+	}
+	if _, err := admin.SetDeviceHealth(context.Background(), asAdmin(&protov2.SetDeviceHealthRequest{
+		DeviceId: "iphone-0",
+		Health:   protov2.HealthState_HEALTH_STATE_MAINTENANCE,
+		Reason:   "cracked screen",
+	}, adminToken)); err != nil {
+		t.Fatalf("SetDeviceHealth failed: %v", err)
+	}
+
+	if !stream.Receive() {
+		t.Fatalf("expected health event: %v", stream.Err())
+	}
+	event := stream.Msg()
+	if event.Type != protov2.EventType_EVENT_TYPE_HEALTH_CHANGED || event.Device.Health != protov2.HealthState_HEALTH_STATE_MAINTENANCE ||
+		event.Device.HealthReason != "cracked screen" || event.Device.State != protov2.DeviceState_DEVICE_STATE_UNAVAILABLE {
+		t.Fatalf("unexpected health event: %+v", event)
+	}
+
+	_, err = reserveAs(v1, "alice", "iphone")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_NO_DEVICES_AVAILABLE {
+		t.Fatalf("expected a device in maintenance not to be handed out, got %v", err)
+	}
+
+	listed, err := client.ListDevices(context.Background(), connect.NewRequest(&protov2.ListDevicesRequest{
+		Health: []protov2.HealthState{protov2.HealthState_HEALTH_STATE_MAINTENANCE},
+	}))
+	if err != nil {
+		t.Fatalf("ListDevices failed: %v", err)
+	}
+	if len(listed.Msg.Devices) != 1 || listed.Msg.Devices[0].Id != "iphone-0" {
+		t.Fatalf("expected iphone-0 listed under maintenance, got %v", listed.Msg.Devices)
+	}
+
+	if _, err := admin.SetDeviceHealth(context.Background(), asAdmin(&protov2.SetDeviceHealthRequest{
+		DeviceId: "iphone-0",
+		Health:   protov2.HealthState_HEALTH_STATE_HEALTHY,
+		Reason:   "screen replaced",
+	}, adminToken)); err != nil {
+		t.Fatalf("SetDeviceHealth failed: %v", err)
+	}
+	if _, err := reserveAs(v1, "alice", "iphone"); err != nil {
+		t.Fatalf("expected the repaired device to be reservable: %v", err)
+	}
+}
+
+func TestHealthyDeviceWakesWaiter(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	if _, err := fleet.SetHealth("iphone-0", device.Offline, "unplugged"); err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+
+	w := pool.Enqueue("alice", "iphone", time.Minute)
+	select {
+	case <-w.Granted():
+		t.Fatal("expected no grant while the only device is offline")
+	default:
+	}
+
+	if _, err := fleet.SetHealth("iphone-0", device.Healthy, "plugged back in"); err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+	select {
+	case d := <-w.Granted():
+		if d.ID != "iphone-0" {
+			t.Fatalf("expected iphone-0, got %+v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to get the device once it was healthy")
+	}
+}
+
+func TestReportedFailuresQuarantineDevice(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	fleet.SetQuarantineAfter(2)
+
+	first, _ := pool.Reserve("alice", "iphone", time.Minute)
+	if _, err := fleet.ReportFailure(first.ID, "bob", "", "not mine"); err != device.ErrLeaseMismatch {
+		t.Fatalf("expected only the holder to report a failure, got %v", err)
+	}
+	for range 2 {
+		if _, err := fleet.ReportFailure(first.ID, "", first.LeaseToken, "app crashed"); err != nil {
+			t.Fatalf("ReportFailure failed: %v", err)
+		}
+	}
+	pool.Release(first.ID, "", first.LeaseToken)
+	if d := pool.All()[0]; d.Failures != 1 || d.Health != device.Healthy {
+		t.Fatalf("expected one failure counted per reservation, got %+v", d)
+	}
+
+	second, _ := pool.Reserve("bob", "iphone", time.Minute)
+	d, err := fleet.ReportFailure(second.ID, "bob", "", "battery died")
+	if err != nil {
+		t.Fatalf("ReportFailure failed: %v", err)
+	}
+	if d.Health != device.Quarantined || d.Failures != 2 {
+		t.Fatalf("expected the device quarantined after 2 failures, got %+v", d)
+	}
+	pool.Release(second.ID, "bob", "")
+
+	if _, ok := pool.Reserve("carol", "iphone", time.Minute); ok {
+		t.Fatal("expected a quarantined device not to be handed out")
+	}
+
+	d, _ = fleet.SetHealth(second.ID, device.Healthy, "battery replaced")
+	if d.Failures != 0 {
+		t.Fatalf("expected failures cleared when healthy again, got %d", d.Failures)
+	}
+}
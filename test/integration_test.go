@@ -2,12 +2,14 @@ package test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/gitRasheed/FleetRPC/internal/device"
 	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
@@ -15,8 +17,12 @@ import (
 )
 
 func setupTestServer(pool *device.DevicePool) (protoconnect.DeviceServiceClient, func()) {
+	return setupFleetServer(device.NewFleet(pool))
+}
+
+func setupFleetServer(fleet *device.Fleet) (protoconnect.DeviceServiceClient, func()) {
 	mux := http.NewServeMux()
-	svc := &testServer{pool: pool}
+	svc := protoconnect.NewDeviceServiceServerWithFleet(fleet)
 	path, handler := protoconnect.NewDeviceServiceHandler(svc)
 	mux.Handle(path, handler)
 
@@ -26,50 +32,40 @@ func setupTestServer(pool *device.DevicePool) (protoconnect.DeviceServiceClient,
 	return client, server.Close
 }
 
-type testServer struct {
-	pool *device.DevicePool
-}
-
-func (s *testServer) ReserveDevice(ctx context.Context, req *connect.Request[proto.ReserveRequest]) (*connect.Response[proto.ReserveResponse], error) {
-	deviceType := req.Msg.DeviceType
-	if deviceType == "" {
-		deviceType = "iphone"
+func errorDetail(t *testing.T, err error) *proto.ErrorDetail {
+	t.Helper()
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		t.Fatalf("expected a connect error, got %v", err)
 	}
-
-	dev, ok := s.pool.Reserve(req.Msg.User, deviceType, 2*time.Minute)
-	if !ok {
-		return connect.NewResponse(&proto.ReserveResponse{
-			Status: "no devices available",
-		}), nil
+	for _, d := range connectErr.Details() {
+		msg, valueErr := d.Value()
+		if detail, ok := msg.(*proto.ErrorDetail); valueErr == nil && ok {
+			return detail
+		}
 	}
+	t.Fatalf("expected ErrorDetail on %v", err)
+	return nil
+}
 
-	return connect.NewResponse(&proto.ReserveResponse{
-		DeviceId: dev.ID,
-		Status:   "reserved",
-	}), nil
+func errorReason(t *testing.T, err error) proto.ErrorReason {
+	t.Helper()
+	return errorDetail(t, err).Reason
 }
 
-func (s *testServer) ReleaseDevice(ctx context.Context, req *connect.Request[proto.ReleaseRequest]) (*connect.Response[proto.ReleaseResponse], error) {
-	success := s.pool.Release(req.Msg.DeviceId)
-	status := "released"
-	if !success {
-		status = "not found or already available"
+func TestInvalidArguments(t *testing.T) {
+	client, cleanup := setupTestServer(device.NewDevicePool("iphone", 1))
+	defer cleanup()
+
+	_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{DeviceType: "iphone"}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "user" {
+		t.Fatalf("expected InvalidArgument on user, got %v", err)
 	}
-	return connect.NewResponse(&proto.ReleaseResponse{Status: status}), nil
-}
 
-func (s *testServer) WatchDevices(ctx context.Context, req *connect.Request[proto.WatchRequest], stream *connect.ServerStream[proto.DeviceStatus]) error {
-	for _, dev := range s.pool.All() {
-		err := stream.Send(&proto.DeviceStatus{
-			DeviceId:   dev.ID,
-			ReservedBy: dev.ReservedBy,
-			Available:  device.IsAvailable(dev),
-		})
-		if err != nil {
-			return err
-		}
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{DeviceId: "iphone-0"}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "lease_token" {
+		t.Fatalf("expected InvalidArgument on lease_token, got %v", err)
 	}
-	return nil
 }
 
 func TestReserveAndRelease(t *testing.T) {
@@ -91,8 +87,13 @@ func TestReserveAndRelease(t *testing.T) {
 		t.Fatalf("expected status 'reserved', got '%s'", reserveResp.Msg.Status)
 	}
 
+	if reserveResp.Msg.LeaseToken == "" {
+		t.Fatalf("expected lease token, got empty string")
+	}
+
 	releaseResp, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
-		DeviceId: reserveResp.Msg.DeviceId,
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
 	}))
 	if err != nil {
 		t.Fatalf("ReleaseDevice failed: %v", err)
@@ -116,20 +117,189 @@ func TestDoubleReleaseFails(t *testing.T) {
 	}
 
 	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
-		DeviceId: reserveResp.Msg.DeviceId,
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
 	}))
 	if err != nil {
 		t.Fatalf("first ReleaseDevice failed: %v", err)
 	}
 
-	secondRelease, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("expected FailedPrecondition on second release, got %v", err)
+	}
+	if reason := errorReason(t, err); reason != proto.ErrorReason_ERROR_REASON_NOT_RESERVED {
+		t.Fatalf("expected NOT_RESERVED, got %s", reason)
+	}
+
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   "iphone-404",
+		LeaseToken: reserveResp.Msg.LeaseToken,
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("expected NotFound for unknown device, got %v", err)
+	}
+}
+
+func TestReleaseRequiresMatchingLease(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	reserveResp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "owner",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: "not-the-lease",
+	}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected PermissionDenied for wrong token, got %v", err)
+	}
+
+	_, err = client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId: reserveResp.Msg.DeviceId,
+		User:     "colleague",
+	}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected PermissionDenied for wrong user, got %v", err)
+	}
+	if detail := errorDetail(t, err); detail.CurrentHolder != "owner" {
+		t.Fatalf("expected error to name holder 'owner', got '%s'", detail.CurrentHolder)
+	}
+
+	releaseResp, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
 		DeviceId: reserveResp.Msg.DeviceId,
+		User:     "owner",
 	}))
 	if err != nil {
-		t.Fatalf("second ReleaseDevice failed: %v", err)
+		t.Fatalf("ReleaseDevice by holder failed: %v", err)
 	}
-	if secondRelease.Msg.Status != "not found or already available" {
-		t.Fatalf("expected 'not found or already available', got '%s'", secondRelease.Msg.Status)
+	if releaseResp.Msg.Status != "released" {
+		t.Fatalf("expected status 'released', got '%s'", releaseResp.Msg.Status)
+	}
+}
+
+func TestExtendReservation(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	reserveResp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "owner",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+
+	extendResp, err := client.ExtendReservation(context.Background(), connect.NewRequest(&proto.ExtendRequest{
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
+		Extension:  durationpb.New(10 * time.Minute),
+	}))
+	if err != nil {
+		t.Fatalf("ExtendReservation failed: %v", err)
+	}
+	if remaining := time.Until(extendResp.Msg.ExpiresAt.AsTime()); remaining < 11*time.Minute {
+		t.Fatalf("expected at least 11m remaining after extension, got %s", remaining)
+	}
+
+	_, err = client.ExtendReservation(context.Background(), connect.NewRequest(&proto.ExtendRequest{
+		DeviceId: reserveResp.Msg.DeviceId,
+		User:     "colleague",
+	}))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-holder, got %v", err)
+	}
+
+	_, err = client.ExtendReservation(context.Background(), connect.NewRequest(&proto.ExtendRequest{
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
+		Extension:  durationpb.New(24 * time.Hour),
+	}))
+	if err != nil {
+		t.Fatalf("ExtendReservation to the cap failed: %v", err)
+	}
+
+	_, err = client.ExtendReservation(context.Background(), connect.NewRequest(&proto.ExtendRequest{
+		DeviceId:   reserveResp.Msg.DeviceId,
+		LeaseToken: reserveResp.Msg.LeaseToken,
+	}))
+	if connect.CodeOf(err) != connect.CodeFailedPrecondition {
+		t.Fatalf("expected FailedPrecondition past maximum lease, got %v", err)
+	}
+}
+
+func TestLeasePolicyChangeAppliesToNewReservations(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 2)
+	svc := protoconnect.NewDeviceServiceServerWithPool(pool)
+	mux := http.NewServeMux()
+	mux.Handle(protoconnect.NewDeviceServiceHandler(svc))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := protoconnect.NewDeviceServiceClient(http.DefaultClient, server.URL)
+
+	reserve := func(user string) *proto.ReserveResponse {
+		resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{User: user, DeviceType: "iphone"}))
+		if err != nil {
+			t.Fatalf("ReserveDevice failed: %v", err)
+		}
+		return resp.Msg
+	}
+	first := reserve("alice")
+
+	policy := device.DefaultLeasePolicy()
+	policy.DefaultTTL = 10 * time.Minute
+	svc.SetLeasePolicy(policy)
+
+	if granted := time.Until(reserve("bob").ExpiresAt.AsTime()); granted < 9*time.Minute {
+		t.Fatalf("expected the new default ttl of 10m, got %s", granted)
+	}
+	if dev, _ := pool.Get(first.DeviceId); !dev.ExpiresAt.Equal(first.ExpiresAt.AsTime()) {
+		t.Fatalf("expected the existing reservation to keep its expiry, got %s", dev.ExpiresAt)
+	}
+}
+
+func TestReserveTTLIsClamped(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 4)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	policy := device.DefaultLeasePolicy()
+	cases := []struct {
+		name string
+		ttl  *durationpb.Duration
+		want time.Duration
+	}{
+		{"default", nil, policy.DefaultTTL},
+		{"requested", durationpb.New(45 * time.Minute), 45 * time.Minute},
+		{"too long", durationpb.New(5 * time.Hour), policy.MaxTTL},
+		{"too short", durationpb.New(time.Second), policy.MinTTL},
+	}
+
+	for _, tc := range cases {
+		before := time.Now()
+		resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+			User:       tc.name,
+			DeviceType: "iphone",
+			Ttl:        tc.ttl,
+		}))
+		if err != nil {
+			t.Fatalf("%s: ReserveDevice failed: %v", tc.name, err)
+		}
+		granted := resp.Msg.ExpiresAt.AsTime().Sub(before)
+		if granted < tc.want-time.Second || granted > tc.want+time.Second {
+			t.Fatalf("%s: expected ttl of %s, got %s", tc.name, tc.want, granted)
+		}
 	}
 }
 
@@ -140,15 +310,12 @@ func TestReservationExpiresAfterTTL(t *testing.T) {
 
 	pool.Reserve("blocker", "iphone", 100*time.Millisecond)
 
-	secondReserve, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+	_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
 		User:       "waiting",
 		DeviceType: "iphone",
 	}))
-	if err != nil {
-		t.Fatalf("ReserveDevice failed: %v", err)
-	}
-	if secondReserve.Msg.DeviceId != "" {
-		t.Fatalf("expected no device available, but got '%s'", secondReserve.Msg.DeviceId)
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted while blocked, got %v", err)
 	}
 
 	time.Sleep(150 * time.Millisecond)
@@ -181,18 +348,15 @@ func TestNoOverlappingReservations(t *testing.T) {
 	pool.Reserve("user8", "iphone", 5*time.Minute)
 	pool.Reserve("user9", "iphone", 5*time.Minute)
 
-	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+	_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
 		User:       "user10",
 		DeviceType: "iphone",
 	}))
-	if err != nil {
-		t.Fatalf("ReserveDevice failed: %v", err)
-	}
-	if resp.Msg.DeviceId != "" {
-		t.Fatalf("expected no devices available, but got '%s'", resp.Msg.DeviceId)
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
 	}
-	if resp.Msg.Status != "no devices available" {
-		t.Fatalf("expected 'no devices available', got '%s'", resp.Msg.Status)
+	if reason := errorReason(t, err); reason != proto.ErrorReason_ERROR_REASON_NO_DEVICES_AVAILABLE {
+		t.Fatalf("expected NO_DEVICES_AVAILABLE, got %s", reason)
 	}
 }
 
@@ -203,13 +367,16 @@ func TestWatchReportsCurrentStatus(t *testing.T) {
 
 	pool.Reserve("occupied", "iphone", 5*time.Minute)
 
-	stream, err := client.WatchDevices(context.Background(), connect.NewRequest(&proto.WatchRequest{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{}))
 	if err != nil {
 		t.Fatalf("WatchDevices failed: %v", err)
 	}
 
 	var devices []*proto.DeviceStatus
-	for stream.Receive() {
+	for len(devices) < 3 && stream.Receive() {
 		devices = append(devices, stream.Msg())
 	}
 	if err := stream.Err(); err != nil {
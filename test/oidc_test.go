@@ -0,0 +1,310 @@
+package test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/audit"
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/config"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "fleetrpc"
+)
+
+// testKeys are locally generated signing keys published as a JWKS.
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ec      *ecdsa.PrivateKey
+	ed      ed25519.PrivateKey
+	jwksRaw []byte
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	point, _ := ecKey.PublicKey.Bytes()
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(point[1:33]), "y": b64(point[33:])},
+		{"kty": "OKP", "kid": "ed-1", "crv": "Ed25519", "x": b64(edKey.Public().(ed25519.PublicKey))},
+	}})
+	return &testKeys{rsa: rsaKey, ec: ecKey, ed: edKey, jwksRaw: jwks}
+}
+
+func (k *testKeys) writeJWKS(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, k.jwksRaw, 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+	return path
+}
+
+// sign returns a JWT over claims, signed with the key for alg.
+func (k *testKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	var err error
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err == nil {
+			sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	case "EdDSA":
+		sig = ed25519.Sign(k.ed, []byte(signed))
+	default:
+		sig = []byte("unsigned")
+	}
+	if err != nil {
+		t.Fatalf("signing failed: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims(email string, groups ...string) map[string]any {
+	return map[string]any{
+		"iss":    testIssuer,
+		"aud":    testAudience,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"email":  email,
+		"groups": groups,
+	}
+}
+
+func with(claims map[string]any, key string, value any) map[string]any {
+	copied := make(map[string]any, len(claims))
+	for k, v := range claims {
+		copied[k] = v
+	}
+	if value == nil {
+		delete(copied, key)
+	} else {
+		copied[key] = value
+	}
+	return copied
+}
+
+func TestOIDCVerifiesTokens(t *testing.T) {
+	keys := newTestKeys(t)
+	jwks, err := auth.LoadJWKSFile(keys.writeJWKS(t))
+	if err != nil {
+		t.Fatalf("LoadJWKSFile failed: %v", err)
+	}
+	verifier := &auth.OIDC{Keys: jwks, Issuer: testIssuer, Audience: testAudience}
+	claims := validClaims("alice@example.com", "qa", "fleet-admins")
+
+	for _, alg := range []struct{ alg, kid string }{{"RS256", "rsa-1"}, {"ES256", "ec-1"}, {"EdDSA", "ed-1"}} {
+		id, err := verifier.Authenticate(context.Background(), keys.sign(t, alg.alg, alg.kid, claims))
+		if err != nil {
+			t.Fatalf("%s: Authenticate failed: %v", alg.alg, err)
+		}
+		if id.User != "alice@example.com" || !id.InGroup("fleet-admins") || !id.InGroup("qa") {
+			t.Fatalf("%s: unexpected identity %+v", alg.alg, id)
+		}
+	}
+
+	other := newTestKeys(t)
+	rejected := map[string]string{
+		"wrong issuer":       keys.sign(t, "RS256", "rsa-1", with(claims, "iss", "https://evil.example.com")),
+		"wrong audience":     keys.sign(t, "RS256", "rsa-1", with(claims, "aud", []string{"other"})),
+		"expired":            keys.sign(t, "RS256", "rsa-1", with(claims, "exp", time.Now().Add(-time.Minute).Unix())),
+		"no expiry":          keys.sign(t, "RS256", "rsa-1", with(claims, "exp", nil)),
+		"not yet valid":      keys.sign(t, "RS256", "rsa-1", with(claims, "nbf", time.Now().Add(time.Hour).Unix())),
+		"unverified email":   keys.sign(t, "RS256", "rsa-1", with(claims, "email_verified", false)),
+		"no email":           keys.sign(t, "RS256", "rsa-1", with(claims, "email", nil)),
+		"foreign key":        other.sign(t, "RS256", "rsa-1", claims),
+		"unknown kid":        keys.sign(t, "RS256", "rsa-2", claims),
+		"alg none":           keys.sign(t, "none", "rsa-1", claims),
+		"alg/key mismatch":   keys.sign(t, "ES256", "rsa-1", claims),
+		"not a JWT":          "alice-token",
+		"truncated":          strings.Join(strings.Split(keys.sign(t, "RS256", "rsa-1", claims), ".")[:2], "."),
+		"unsupported alg":    keys.sign(t, "HS256", "rsa-1", claims),
+		"payload swapped in": swapPayload(keys.sign(t, "RS256", "rsa-1", claims), with(claims, "email", "mallory@example.com")),
+	}
+	for name, token := range rejected {
+		if id, err := verifier.Authenticate(context.Background(), token); err == nil {
+			t.Errorf("%s: expected rejection, got %+v", name, id)
+		}
+	}
+	if _, err := verifier.Authenticate(context.Background(), keys.sign(t, "RS256", "rsa-1", with(claims, "exp", time.Now().Add(-time.Minute).Unix()))); !errors.Is(err, auth.ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+
+	// Audience lists, a custom user claim and leeway.
+	custom := &auth.OIDC{Keys: jwks, Issuer: testIssuer, Audience: testAudience, UserClaim: "preferred_username", GroupsClaim: "roles", Leeway: 2 * time.Minute}
+	id, err := custom.Authenticate(context.Background(), keys.sign(t, "RS256", "rsa-1", map[string]any{
+		"iss":                testIssuer,
+		"aud":                []string{"other", testAudience},
+		"exp":                time.Now().Add(-time.Minute).Unix(),
+		"preferred_username": "bob",
+		"roles":              "qa",
+	}))
+	if err != nil || id.User != "bob" || !id.InGroup("qa") {
+		t.Fatalf("expected bob in qa, got %+v, %v", id, err)
+	}
+}
+
+func swapPayload(token string, claims map[string]any) string {
+	parts := strings.Split(token, ".")
+	payload, _ := json.Marshal(claims)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return strings.Join(parts, ".")
+}
+
+func TestRemoteJWKS(t *testing.T) {
+	keys := newTestKeys(t)
+	var fetches atomic.Int32
+	var misses atomic.Int32
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jwks" {
+			misses.Add(1)
+			http.NotFound(w, r)
+			return
+		}
+		fetches.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write(keys.jwksRaw)
+	}))
+	defer idp.Close()
+
+	verifier := &auth.OIDC{Keys: auth.NewRemoteJWKS(idp.URL+"/jwks", nil), Issuer: testIssuer, Audience: testAudience}
+	token := keys.sign(t, "RS256", "rsa-1", validClaims("alice@example.com"))
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Go(func() {
+			_, err := verifier.Authenticate(context.Background(), token)
+			errs <- err
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Authenticate failed: %v", err)
+		}
+	}
+	// Unknown key IDs do not refetch within a minute of the last fetch.
+	if _, err := verifier.Authenticate(context.Background(), keys.sign(t, "RS256", "rsa-9", validClaims("alice@example.com"))); err == nil {
+		t.Fatal("expected an unknown key ID to be rejected")
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("expected the key set fetched once, got %d", n)
+	}
+
+	missing := &auth.OIDC{Keys: auth.NewRemoteJWKS(idp.URL+"/missing", nil), Issuer: testIssuer, Audience: testAudience}
+	if _, err := missing.Authenticate(context.Background(), keys.sign(t, "RS256", "rsa-1", validClaims("alice@example.com"))); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the failed fetch reported, got %v", err)
+	}
+	// A failed fetch is not retried within a minute either.
+	if _, err := missing.Authenticate(context.Background(), keys.sign(t, "RS256", "rsa-1", validClaims("alice@example.com"))); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected the failed fetch reported again, got %v", err)
+	}
+	if n := misses.Load(); n != 1 {
+		t.Fatalf("expected one failed fetch, got %d", n)
+	}
+}
+
+func TestOIDCThroughServer(t *testing.T) {
+	keys := newTestKeys(t)
+	cfg, err := config.Load(writeFleetFile(t, `{
+		"devices": [{"type": "iphone"}],
+		"admin": {"groups": ["fleet-admins"]},
+		"auth": {"oidc": {"issuer": "`+testIssuer+`", "audience": "`+testAudience+`", "jwks_file": "`+keys.writeJWKS(t)+`"}}
+	}`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	authn, err := cfg.Authenticator()
+	if err != nil {
+		t.Fatalf("Authenticator failed: %v", err)
+	}
+	fleet := cfg.Fleet()
+	client, _, cleanup := setupAuthServer(t, fleet, authn)
+	defer cleanup()
+
+	alice := keys.sign(t, "RS256", "rsa-1", validClaims("alice@example.com", "qa"))
+	resp, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{User: "bob", DeviceType: "iphone"}, alice))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	if d := fleet.Snapshot()[0]; d.ID != resp.Msg.DeviceId || d.ReservedBy != "alice@example.com" {
+		t.Fatalf("expected the reservation made for the token's email, got %+v", d)
+	}
+}
+
+func TestAdminGroups(t *testing.T) {
+	keys := newTestKeys(t)
+	jwks, _ := auth.ParseJWKS(keys.jwksRaw)
+	verifier := &auth.OIDC{Keys: jwks, Issuer: testIssuer, Audience: testAudience}
+
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1))
+	client, admin, auditPath, cleanup := setupAdminServer(t, fleet, func(s *protoconnect.AdminServiceServer) {
+		s.SetAuth(verifier, []string{"fleet-admins"})
+	})
+	defer cleanup()
+	held, _ := reserveAs(client, "alice", "iphone")
+
+	operator := keys.sign(t, "RS256", "rsa-1", validClaims("carol@example.com", "fleet-admins"))
+	tester := keys.sign(t, "RS256", "rsa-1", validClaims("dave@example.com", "qa"))
+
+	_, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: held.DeviceId, Reason: "stuck"}, tester))
+	if connect.CodeOf(err) != connect.CodePermissionDenied || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_PERMISSION_DENIED {
+		t.Fatalf("expected PermissionDenied outside the admin group, got %v", err)
+	}
+	if _, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: held.DeviceId, Reason: "stuck"}, operator)); err != nil {
+		t.Fatalf("expected an admin group member to force-release: %v", err)
+	}
+	if _, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: held.DeviceId, Reason: "stuck"}, "garbage")); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("expected Unauthenticated for an unknown token, got %v", err)
+	}
+
+	records, _ := audit.ReadFile(auditPath)
+	if len(records) != 3 || records[0].Actor != "dave@example.com" || records[0].Error == "" || records[1].Actor != "carol@example.com" || records[1].Error != "" {
+		t.Fatalf("unexpected audit records: %+v", records)
+	}
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+)
+
+func setupPreemptServer(fleet *device.Fleet, grace time.Duration) (protoconnect.DeviceServiceClient, func()) {
+	svc := protoconnect.NewDeviceServiceServerWithFleet(fleet)
+	policy := device.DefaultLeasePolicy()
+	policy.PreemptGrace = grace
+	svc.SetLeasePolicy(policy)
+
+	mux := http.NewServeMux()
+	path, handler := protoconnect.NewDeviceServiceHandler(svc)
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	return protoconnect.NewDeviceServiceClient(http.DefaultClient, server.URL), server.Close
+}
+
+func TestHigherPriorityWaiterServedFirst(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	device.NewFleet(pool)
+	holder, _ := pool.Reserve("holder", "iphone", time.Minute)
+
+	low := pool.EnqueueMatching(device.Request{User: "explorer", Type: "iphone", TTL: time.Minute})
+	defer pool.Cancel(low)
+	high := pool.EnqueueMatching(device.Request{User: "release", Type: "iphone", TTL: time.Minute, Priority: 10})
+	defer pool.Cancel(high)
+
+	if pool.Position(high) != 1 || pool.Position(low) != 2 {
+		t.Fatalf("expected high priority ahead, got high=%d low=%d", pool.Position(high), pool.Position(low))
+	}
+
+	if err := pool.Release(holder.ID, "holder", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	select {
+	case d := <-high.Granted():
+		if d.Priority != 10 {
+			t.Fatalf("expected reservation priority 10, got %d", d.Priority)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("high-priority waiter was not granted the device")
+	}
+	if pool.Position(low) != 1 {
+		t.Fatalf("expected low-priority waiter still queued, got position %d", pool.Position(low))
+	}
+}
+
+func TestPreemptionEvictsLowerPriorityHolder(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	client, cleanup := setupPreemptServer(device.NewFleet(pool), time.Second)
+	defer cleanup()
+
+	if _, err := reserveAs(client, "explorer", "iphone"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	watch, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{ReservedBy: "explorer"}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	if !watch.Receive() || watch.Msg().Event != string(device.EventSnapshot) {
+		t.Fatalf("expected snapshot: %v", watch.Err())
+	}
+
+	wait, err := client.ReserveAndWait(ctx, connect.NewRequest(&proto.ReserveRequest{
+		User:       "release",
+		DeviceType: "iphone",
+		Priority:   10,
+		Preempt:    true,
+	}))
+	if err != nil {
+		t.Fatalf("ReserveAndWait failed: %v", err)
+	}
+
+	if !watch.Receive() {
+		t.Fatalf("expected preempting event: %v", watch.Err())
+	}
+	notice := watch.Msg()
+	if notice.Event != string(device.EventPreempting) || notice.ReservedBy != "explorer" || notice.PreemptAt == nil {
+		t.Fatalf("unexpected notice: %+v", notice)
+	}
+
+	time.Sleep(time.Until(notice.PreemptAt.AsTime()) + 10*time.Millisecond)
+	pool.Expire()
+
+	if !watch.Receive() || watch.Msg().Event != string(device.EventPreempted) {
+		t.Fatalf("expected preempted event, got %+v (%v)", watch.Msg(), watch.Err())
+	}
+	reservation := receiveReservation(t, wait)
+	if reservation.DeviceId != "iphone-0" {
+		t.Fatalf("expected preempting waiter to get iphone-0, got %s", reservation.DeviceId)
+	}
+}
+
+func TestPreemptionSparesEqualPriorityAndCancels(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	device.NewFleet(pool)
+	holder, _ := pool.ReserveMatching(device.Request{User: "holder", Type: "iphone", TTL: time.Minute, Priority: 5})
+
+	peer := pool.EnqueueMatching(device.Request{User: "peer", Type: "iphone", TTL: time.Minute, Priority: 5, PreemptAfter: time.Minute})
+	if d, _ := pool.Get(holder.ID); !d.PreemptAt.IsZero() {
+		t.Fatal("equal priority must not preempt")
+	}
+	pool.Cancel(peer)
+
+	w := pool.EnqueueMatching(device.Request{User: "release", Type: "iphone", TTL: time.Minute, Priority: 10, PreemptAfter: time.Minute})
+	if d, _ := pool.Get(holder.ID); d.PreemptAt.IsZero() {
+		t.Fatal("expected a preemption to be scheduled")
+	}
+	pool.Cancel(w)
+
+	d, _ := pool.Get(holder.ID)
+	if !d.PreemptAt.IsZero() || d.ReservedBy != "holder" {
+		t.Fatalf("expected cancelled preemption to leave holder in place, got %+v", d)
+	}
+}
+
+func TestPreemptRequiresWaitingAndServerSupport(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+
+	disabled, cleanup := setupTestServer(pool)
+	defer cleanup()
+	stream, err := disabled.ReserveAndWait(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:    "release",
+		Preempt: true,
+	}))
+	if err == nil {
+		for stream.Receive() {
+		}
+		err = stream.Err()
+	}
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "preempt" {
+		t.Fatalf("expected InvalidArgument on preempt, got %v", err)
+	}
+
+	enabled, cleanup := setupPreemptServer(device.NewFleet(device.NewDevicePool("iphone", 1)), time.Second)
+	defer cleanup()
+	_, err = enabled.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:    "release",
+		Preempt: true,
+	}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "preempt" {
+		t.Fatalf("expected InvalidArgument on preempt, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/config"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+func reserveAs(client protoconnect.DeviceServiceClient, user, deviceType string) (*proto.ReserveResponse, error) {
+	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       user,
+		DeviceType: deviceType,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+func TestPerUserQuota(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 5))
+	fleet.SetQuotas(device.Quotas{PerUser: 2, Users: map[string]int{"ci-bot": 3}})
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	first, err := reserveAs(client, "alice", "iphone")
+	if err != nil {
+		t.Fatalf("first reserve failed: %v", err)
+	}
+	if _, err := reserveAs(client, "alice", "iphone"); err != nil {
+		t.Fatalf("second reserve failed: %v", err)
+	}
+
+	_, err = reserveAs(client, "alice", "iphone")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	detail := errorDetail(t, err)
+	if detail.Reason != proto.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED {
+		t.Fatalf("expected QUOTA_EXCEEDED, got %v", detail.Reason)
+	}
+	if q := detail.Quota; q == nil || q.Scope != device.QuotaUser || q.Subject != "alice" || q.InUse != 2 || q.Limit != 2 {
+		t.Fatalf("unexpected quota detail: %v", detail.Quota)
+	}
+
+	// The override lets ci-bot take a third device.
+	for i := 0; i < 3; i++ {
+		if _, err := reserveAs(client, "ci-bot", "iphone"); err != nil {
+			t.Fatalf("ci-bot reserve %d failed: %v", i, err)
+		}
+	}
+
+	// Releasing frees quota.
+	if _, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&proto.ReleaseRequest{
+		DeviceId:   first.DeviceId,
+		LeaseToken: first.LeaseToken,
+	})); err != nil {
+		t.Fatalf("ReleaseDevice failed: %v", err)
+	}
+	if _, err := reserveAs(client, "alice", "iphone"); err != nil {
+		t.Fatalf("expected alice to be back within quota, got %v", err)
+	}
+}
+
+func TestPerTypeAndTeamQuotas(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3), device.NewDevicePool("pixel", 3))
+	fleet.SetQuotas(device.Quotas{
+		PerType: map[string]int{"iphone": 1},
+		PerTeam: 3,
+		Teams:   map[string][]string{"mobile": {"alice", "bob"}},
+	})
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	if _, err := reserveAs(client, "alice", "iphone"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	_, err := reserveAs(client, "alice", "iphone")
+	if q := errorDetail(t, err).Quota; q == nil || q.Scope != device.QuotaType || q.DeviceType != "iphone" {
+		t.Fatalf("expected per-type quota error, got %v", err)
+	}
+
+	if _, err := reserveAs(client, "alice", "pixel"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if _, err := reserveAs(client, "bob", "pixel"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	_, err = reserveAs(client, "bob", "pixel")
+	if q := errorDetail(t, err).Quota; q == nil || q.Scope != device.QuotaTeam || q.Subject != "mobile" || q.InUse != 3 {
+		t.Fatalf("expected team quota error, got %v", err)
+	}
+
+	// Users outside the team are unaffected.
+	if _, err := reserveAs(client, "carol", "pixel"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+}
+
+func TestReassigningExpiredDeviceReleasesQuota(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	fleet.SetQuotas(device.Quotas{PerUser: 1})
+	if _, ok := pool.Reserve("alice", "iphone", 10*time.Millisecond); !ok {
+		t.Fatal("Reserve failed")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// The lease has expired but has not been swept.
+	held, ok := pool.Reserve("bob", "iphone", time.Minute)
+	if !ok {
+		t.Fatal("expected bob to take the expired device")
+	}
+	for _, u := range fleet.QuotaUsage("alice") {
+		if u.Scope == device.QuotaUser && u.InUse != 0 {
+			t.Fatalf("expected alice's quota released, got %+v", u)
+		}
+	}
+	if err := pool.Release(held.ID, "bob", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, ok := pool.Reserve("alice", "iphone", time.Minute); !ok {
+		t.Fatalf("expected alice to reserve again, usage %+v", fleet.QuotaUsage("alice"))
+	}
+}
+
+func TestAuthenticatedGroupsJoinTeams(t *testing.T) {
+	tokens, err := auth.NewStaticTokens([]auth.TokenEntry{
+		{Token: "quinn-token", User: "quinn", Groups: []string{"qa"}},
+		{Token: "quincy-token", User: "quincy", Groups: []string{"qa", "staff"}},
+		{Token: "sam-token", User: "sam", Groups: []string{"staff"}},
+	})
+	if err != nil {
+		t.Fatalf("NewStaticTokens failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	fleet.SetQuotas(device.Quotas{PerTeam: 1, Teams: map[string][]string{"qa": nil}})
+	client, _, cleanup := setupAuthServer(t, fleet, tokens)
+	defer cleanup()
+
+	reserve := func(token string) error {
+		_, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, token))
+		return err
+	}
+	if err := reserve("quinn-token"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	err = reserve("quincy-token")
+	if q := errorDetail(t, err).Quota; q == nil || q.Scope != device.QuotaTeam || q.Subject != "qa" || q.InUse != 1 {
+		t.Fatalf("expected the qa group to share the team quota, got %v", err)
+	}
+	// Groups that name no team are not limited.
+	if err := reserve("sam-token"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+}
+
+func TestQuotaAppliesToWaitersAndBatches(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1), device.NewDevicePool("pixel", 2))
+	fleet.SetQuotas(device.Quotas{PerUser: 1})
+	v1, v2, cleanup := setupV2Server(fleet)
+	defer cleanup()
+
+	if _, err := reserveAs(v1, "alice", "pixel"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream, err := v1.ReserveAndWait(ctx, connect.NewRequest(&proto.ReserveRequest{User: "alice", DeviceType: "iphone"}))
+	if err == nil {
+		for stream.Receive() {
+		}
+		err = stream.Err()
+	}
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ReserveAndWait to fail over quota, got %v", err)
+	}
+
+	_, err = v2.ReserveBatch(context.Background(), connect.NewRequest(&protov2.ReserveBatchRequest{
+		User:    "bob",
+		Devices: []*protov2.DeviceRequirement{{DeviceType: "iphone"}, {DeviceType: "pixel"}},
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_QUOTA_EXCEEDED {
+		t.Fatalf("expected batch over quota, got %v", err)
+	}
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	fleet.SetQuotas(device.Quotas{PerUser: 2, Teams: map[string][]string{"mobile": {"alice"}}})
+	v1, v2, cleanup := setupV2Server(fleet)
+	defer cleanup()
+
+	if _, err := reserveAs(v1, "alice", "iphone"); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+
+	resp, err := v2.GetQuotaUsage(context.Background(), connect.NewRequest(&protov2.GetQuotaUsageRequest{User: "alice"}))
+	if err != nil {
+		t.Fatalf("GetQuotaUsage failed: %v", err)
+	}
+	found := map[string]*protov2.QuotaUsage{}
+	for _, u := range resp.Msg.Usage {
+		found[u.Scope] = u
+	}
+	if u := found[device.QuotaUser]; u == nil || u.InUse != 1 || u.Limit != 2 {
+		t.Fatalf("unexpected user usage: %v", resp.Msg.Usage)
+	}
+	if u := found[device.QuotaType]; u == nil || u.DeviceType != "iphone" || u.InUse != 1 {
+		t.Fatalf("unexpected type usage: %v", resp.Msg.Usage)
+	}
+	if u := found[device.QuotaTeam]; u == nil || u.Subject != "mobile" || u.InUse != 1 {
+		t.Fatalf("unexpected team usage: %v", resp.Msg.Usage)
+	}
+}
+
+func TestLoadQuotas(t *testing.T) {
+	path := writeFleetFile(t, `{
+		"quotas": {"per_user": 2, "per_type": {"iphone": 1}, "teams": {"mobile": ["alice"]}, "per_team": 4},
+		"devices": [{"type": "iphone"}]
+	}`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	q := cfg.QuotaPolicy()
+	if q.PerUser != 2 || q.PerType["iphone"] != 1 || q.PerTeam != 4 || len(q.Teams["mobile"]) != 1 {
+		t.Fatalf("unexpected quotas: %+v", q)
+	}
+
+	_, err = config.Load(writeFleetFile(t, `{"quotas": {"per_user": -1}, "devices": [{"type": "iphone"}]}`))
+	if err == nil {
+		t.Fatal("expected negative quota to be rejected")
+	}
+}
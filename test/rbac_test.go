@@ -0,0 +1,323 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitRasheed/FleetRPC/internal/auth"
+	"github.com/gitRasheed/FleetRPC/internal/config"
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/rbac"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+)
+
+const rbacPolicy = `{
+	"roles": {
+		"intern": {"permissions": ["view", "reserve", "release", "extend", "report_failure", "book"], "device_types": ["simulator"]},
+		"qa": {"permissions": ["view", "reserve", "release", "drain"], "device_types": ["iphone"], "selector": "lab=qa"},
+		"viewer": {"permissions": ["view"]},
+		"lab-admin": {"permissions": ["force_release", "set_health", "release_user"], "device_types": ["iphone"]},
+		"fleet-admin": {"permissions": ["*"]}
+	},
+	"users": {"vic": ["viewer"], "lena": ["lab-admin"]},
+	"groups": {"qa": ["qa"], "fleet-admins": ["fleet-admin"]},
+	"default_roles": ["intern"]
+}`
+
+func rbacFixture(t *testing.T) (auth.Authenticator, *rbac.Policy, *device.Fleet) {
+	t.Helper()
+	tokens, err := auth.NewStaticTokens([]auth.TokenEntry{
+		{Token: "ivy-token", User: "ivy"},
+		{Token: "quinn-token", User: "quinn", Groups: []string{"qa"}},
+		{Token: "vic-token", User: "vic"},
+		{Token: "lena-token", User: "lena"},
+		{Token: "fay-token", User: "fay", Groups: []string{"fleet-admins"}},
+	})
+	if err != nil {
+		t.Fatalf("NewStaticTokens failed: %v", err)
+	}
+	cfg, err := config.Load(writeFleetFile(t, `{
+		"devices": [{"type": "iphone"}],
+		"auth": {"hmac_key_file": "unused.key"},
+		"rbac": `+rbacPolicy+`
+	}`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	policy, err := cfg.Policy()
+	if err != nil {
+		t.Fatalf("Policy failed: %v", err)
+	}
+
+	fleet := device.NewFleet(
+		device.NewDevicePool("simulator", 1),
+		device.NewDevicePoolWithDevices("iphone", []*device.Device{
+			{ID: "iphone-prod", Type: "iphone", Labels: map[string]string{"lab": "prod"}},
+			{ID: "iphone-qa", Type: "iphone", Labels: map[string]string{"lab": "qa"}},
+		}),
+	)
+	return tokens, policy, fleet
+}
+
+func TestRBACLimitsDeviceService(t *testing.T) {
+	tokens, policy, fleet := rbacFixture(t)
+	client, clientV2, cleanup := setupAuthServer(t, fleet, tokens, func(i *protoconnect.AuthInterceptor) {
+		i.SetPolicy(policy)
+	})
+	defer cleanup()
+
+	_, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied ||
+		detail.Reason != proto.ErrorReason_ERROR_REASON_PERMISSION_DENIED || detail.Permission != "reserve" {
+		t.Fatalf("expected an intern to be denied reserve on iphones, got %v", err)
+	}
+	if _, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "simulator"}, "ivy-token")); err != nil {
+		t.Fatalf("expected an intern to reserve a simulator: %v", err)
+	}
+
+	resp, err := client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, "quinn-token"))
+	if err != nil || resp.Msg.DeviceId != "iphone-qa" {
+		t.Fatalf("expected QA to be given the QA lab iphone, got %v, %v", resp, err)
+	}
+	_, err = client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone"}, "quinn-token"))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected QA not to be given the prod iphone, got %v", err)
+	}
+	_, err = client.ReserveDevice(context.Background(), withToken(&proto.ReserveRequest{DeviceType: "iphone", Selector: "lab=prod"}, "quinn-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), `lacks permission "reserve"`) {
+		t.Fatalf("expected QA to be denied the prod iphone by selector, got %v", err)
+	}
+	_, err = clientV2.ReserveBatch(context.Background(), withToken(&protov2.ReserveBatchRequest{
+		Devices: []*protov2.DeviceRequirement{{DeviceType: "iphone"}},
+	}, "ivy-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected an intern's batch with an iphone to be denied, got %v", err)
+	}
+
+	// Calls on a device need the permission on that device.
+	_, err = client.ReleaseDevice(context.Background(), withToken(&proto.ReleaseRequest{DeviceId: "iphone-qa"}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "release" ||
+		!strings.Contains(err.Error(), `on device "iphone-qa"`) {
+		t.Fatalf("expected an intern to be denied release on an iphone, got %v", err)
+	}
+	_, err = clientV2.ExtendReservation(context.Background(), withToken(&protov2.ExtendRequest{DeviceId: "iphone-qa"}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "extend" {
+		t.Fatalf("expected an intern to be denied extend on an iphone, got %v", err)
+	}
+	_, err = clientV2.ReportDeviceFailure(context.Background(), withToken(&protov2.ReportDeviceFailureRequest{DeviceId: "iphone-qa", Reason: "crash"}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "report_failure" {
+		t.Fatalf("expected an intern to be denied report_failure on an iphone, got %v", err)
+	}
+	if _, err := client.ReleaseDevice(context.Background(), withToken(&proto.ReleaseRequest{DeviceId: "iphone-qa"}, "quinn-token")); err != nil {
+		t.Fatalf("expected QA to release their iphone: %v", err)
+	}
+
+	_, err = client.ExtendReservation(context.Background(), withToken(&proto.ExtendRequest{DeviceId: "iphone-qa"}, "vic-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "extend" {
+		t.Fatalf("expected a viewer to be denied extend, got %v", err)
+	}
+
+	if _, err := clientV2.WhoAmI(context.Background(), withToken(&protov2.WhoAmIRequest{}, "vic-token")); err != nil {
+		t.Fatalf("expected WhoAmI to need no permission: %v", err)
+	}
+}
+
+func TestRBACChecksGroupsAndBookingsPerDevice(t *testing.T) {
+	tokens, policy, fleet := rbacFixture(t)
+	_, clientV2, cleanup := setupAuthServer(t, fleet, tokens, func(i *protoconnect.AuthInterceptor) {
+		i.SetPolicy(policy)
+	})
+	defer cleanup()
+	ctx := context.Background()
+
+	batch, err := clientV2.ReserveBatch(ctx, withToken(&protov2.ReserveBatchRequest{
+		Devices: []*protov2.DeviceRequirement{{DeviceType: "simulator"}, {DeviceType: "iphone", Selector: "lab=prod"}},
+	}, "fay-token"))
+	if err != nil {
+		t.Fatalf("ReserveBatch failed: %v", err)
+	}
+	_, err = clientV2.ReleaseBatch(ctx, withToken(&protov2.ReleaseBatchRequest{GroupLease: batch.Msg.GroupLease}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "release" || detail.DeviceId != "iphone-prod" {
+		t.Fatalf("expected an intern to be denied releasing a batch with an iphone, got %v", err)
+	}
+	_, err = clientV2.ExtendBatch(ctx, withToken(&protov2.ExtendBatchRequest{GroupLease: batch.Msg.GroupLease}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "extend" {
+		t.Fatalf("expected an intern to be denied extending a batch with an iphone, got %v", err)
+	}
+	if held := fleet.Group(batch.Msg.GroupLease); len(held) != 2 {
+		t.Fatalf("expected the batch to stay reserved, got %+v", held)
+	}
+
+	start := time.Now().Add(24 * time.Hour)
+	booking, err := clientV2.CreateBooking(ctx, withToken(&protov2.CreateBookingRequest{
+		DeviceId:  "iphone-qa",
+		StartTime: timestamppb.New(start),
+		EndTime:   timestamppb.New(start.Add(time.Hour)),
+	}, "fay-token"))
+	if err != nil {
+		t.Fatalf("CreateBooking failed: %v", err)
+	}
+	_, err = clientV2.CancelBooking(ctx, withToken(&protov2.CancelBookingRequest{
+		BookingId:  booking.Msg.Booking.Id,
+		LeaseToken: booking.Msg.Booking.LeaseToken,
+	}, "ivy-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "book" {
+		t.Fatalf("expected an intern to be denied cancelling an iphone booking, got %v", err)
+	}
+}
+
+func TestRBACLimitsReadsToViewableDevices(t *testing.T) {
+	tokens, policy, fleet := rbacFixture(t)
+	client, clientV2, cleanup := setupAuthServer(t, fleet, tokens, func(i *protoconnect.AuthInterceptor) {
+		i.SetPolicy(policy)
+	})
+	defer cleanup()
+	ctx := context.Background()
+
+	start := time.Now().Add(24 * time.Hour)
+	for _, id := range []string{"iphone-prod", "iphone-qa"} {
+		_, err := clientV2.CreateBooking(ctx, withToken(&protov2.CreateBookingRequest{
+			DeviceId:  id,
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(start.Add(time.Hour)),
+		}, "fay-token"))
+		if err != nil {
+			t.Fatalf("CreateBooking failed: %v", err)
+		}
+	}
+
+	list, err := clientV2.ListDevices(ctx, withToken(&protov2.ListDevicesRequest{}, "quinn-token"))
+	if err != nil || len(list.Msg.Devices) != 2 || list.Msg.Devices[0].Id != "iphone-qa" || list.Msg.Devices[1].Id != "simulator-0" {
+		t.Fatalf("expected QA to list the QA iphone and the simulator, got %v, %v", list, err)
+	}
+	_, err = clientV2.GetDevice(ctx, withToken(&protov2.GetDeviceRequest{DeviceId: "iphone-prod"}, "quinn-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "view" {
+		t.Fatalf("expected QA to be denied the prod iphone, got %v", err)
+	}
+	bookings, err := clientV2.ListBookings(ctx, withToken(&protov2.ListBookingsRequest{}, "quinn-token"))
+	if err != nil || len(bookings.Msg.Bookings) != 1 || bookings.Msg.Bookings[0].DeviceId != "iphone-qa" {
+		t.Fatalf("expected QA to see only the QA iphone's booking, got %v, %v", bookings, err)
+	}
+	drain, err := clientV2.GetDrainStatus(ctx, withToken(&protov2.GetDrainStatusRequest{DeviceType: "iphone"}, "quinn-token"))
+	if err != nil || len(drain.Msg.Devices) != 1 || drain.Msg.Devices[0].Id != "iphone-qa" {
+		t.Fatalf("expected QA to see only the QA iphone's drain status, got %v, %v", drain, err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.WatchDevices(watchCtx, withToken(&proto.WatchRequest{Types: []string{"iphone"}}, "quinn-token"))
+	if err != nil || !stream.Receive() || stream.Msg().DeviceId != "iphone-qa" {
+		t.Fatalf("expected QA's snapshot to start with the QA iphone, got %v", stream.Err())
+	}
+	if _, err := client.ReserveDevice(ctx, withToken(&proto.ReserveRequest{DeviceType: "iphone", Selector: "lab=prod"}, "fay-token")); err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	if _, err := client.ReserveDevice(ctx, withToken(&proto.ReserveRequest{DeviceType: "iphone"}, "quinn-token")); err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	if !stream.Receive() || stream.Msg().DeviceId != "iphone-qa" || stream.Msg().Event != string(device.EventReserved) {
+		t.Fatalf("expected QA to be sent only the QA iphone's events, got %v, %v", stream.Msg(), stream.Err())
+	}
+}
+
+func TestRBACLimitsAdminService(t *testing.T) {
+	tokens, policy, fleet := rbacFixture(t)
+	client, admin, _, cleanup := setupAdminServer(t, fleet, func(s *protoconnect.AdminServiceServer) {
+		s.SetAuth(tokens, nil)
+		s.SetPolicy(policy)
+	})
+	defer cleanup()
+
+	sim, _ := reserveAs(client, "alice", "simulator")
+	phone, _ := reserveAs(client, "alice", "iphone")
+
+	_, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: phone.DeviceId, Reason: "stuck"}, "quinn-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "force_release" {
+		t.Fatalf("expected QA to be denied force_release, got %v", err)
+	}
+	_, err = admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: sim.DeviceId, Reason: "stuck"}, "lena-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), `on device "simulator-0"`) {
+		t.Fatalf("expected a lab admin to be denied force_release on a simulator, got %v", err)
+	}
+	if _, err := admin.ForceRelease(context.Background(), asAdmin(&protov2.ForceReleaseRequest{DeviceId: phone.DeviceId, Reason: "stuck"}, "lena-token")); err != nil {
+		t.Fatalf("expected a lab admin to force-release an iphone: %v", err)
+	}
+	_, err = admin.TransferReservation(context.Background(), asAdmin(&protov2.TransferReservationRequest{DeviceId: sim.DeviceId, ToUser: "bob", Reason: "handover"}, "lena-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "transfer" {
+		t.Fatalf("expected a lab admin to be denied transfer, got %v", err)
+	}
+	if _, err := admin.TransferReservation(context.Background(), asAdmin(&protov2.TransferReservationRequest{DeviceId: sim.DeviceId, ToUser: "bob", Reason: "handover"}, "fay-token")); err != nil {
+		t.Fatalf("expected a fleet admin to transfer: %v", err)
+	}
+	setHealth := func(token, deviceID string) error {
+		_, err := admin.SetDeviceHealth(context.Background(), asAdmin(&protov2.SetDeviceHealthRequest{
+			DeviceId: deviceID,
+			Health:   protov2.HealthState_HEALTH_STATE_MAINTENANCE,
+			Reason:   "battery",
+		}, token))
+		return err
+	}
+	if err := setHealth("quinn-token", "iphone-prod"); connect.CodeOf(err) != connect.CodePermissionDenied || errorDetail(t, err).Permission != "set_health" {
+		t.Fatalf("expected QA to be denied set_health, got %v", err)
+	}
+	if err := setHealth("lena-token", "simulator-0"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Fatalf("expected a lab admin to be denied set_health outside their device types, got %v", err)
+	}
+	if err := setHealth("fay-token", "iphone-prod"); err != nil {
+		t.Fatalf("expected a fleet admin to change device health: %v", err)
+	}
+
+	_, err = admin.DrainDevices(context.Background(), asAdmin(&protov2.DrainRequest{DeviceType: "iphone", Reason: "upgrade"}, "quinn-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), `on device "iphone-prod"`) {
+		t.Fatalf("expected QA to be denied draining a type with devices outside their lab, got %v", err)
+	}
+	if _, err := admin.DrainDevices(context.Background(), asAdmin(&protov2.DrainRequest{DeviceId: "iphone-qa", Reason: "upgrade"}, "quinn-token")); err != nil {
+		t.Fatalf("expected QA to drain their lab's iphone: %v", err)
+	}
+	_, err = admin.UndrainDevices(context.Background(), asAdmin(&protov2.UndrainRequest{DeviceId: "iphone-qa", Reason: "upgraded"}, "lena-token"))
+	if detail := errorDetail(t, err); connect.CodeOf(err) != connect.CodePermissionDenied || detail.Permission != "drain" {
+		t.Fatalf("expected a lab admin to be denied drain, got %v", err)
+	}
+	_, err = admin.ReleaseUserReservations(context.Background(), asAdmin(&protov2.ReleaseUserReservationsRequest{User: "bob", Reason: "left"}, "lena-token"))
+	if connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), `on device "simulator-0"`) {
+		t.Fatalf("expected a lab admin to be denied releasing a user holding a simulator, got %v", err)
+	}
+	if pool, _ := fleet.PoolFor(sim.DeviceId); pool.Snapshot()[0].ReservedBy != "bob" {
+		t.Fatalf("expected a denied release_user to leave %s reserved, got %+v", sim.DeviceId, pool.Snapshot()[0])
+	}
+	if _, err := admin.ReleaseUserReservations(context.Background(), asAdmin(&protov2.ReleaseUserReservationsRequest{User: "bob", Reason: "left"}, adminToken)); err != nil {
+		t.Fatalf("expected admin tokens to keep full access: %v", err)
+	}
+}
+
+func TestRBACConfigValidation(t *testing.T) {
+	_, err := config.Load(writeFleetFile(t, `{
+		"devices": [{"type": "iphone"}],
+		"rbac": {
+			"roles": {"intern": {"permissions": ["reserve", "fly"], "selector": "os>"}},
+			"users": {"bob": ["nobody"]},
+			"default_roles": ["guest"]
+		}
+	}`))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{
+		"rbac: needs an auth backend",
+		`rbac.roles["intern"].selector:`,
+		`rbac: role "intern": unknown permission "fly"`,
+		`rbac: user "bob": unknown role "nobody"`,
+		`rbac: default_roles: unknown role "guest"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%v", want, err)
+		}
+	}
+}
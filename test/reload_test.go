@@ -0,0 +1,189 @@
+package test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/store"
+)
+
+func TestFleetApplyDrainsRemovedDevices(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	pool, _ := fleet.Pool("iphone")
+	dev, ok := pool.Reserve("holder", "iphone", 5*time.Minute)
+	if !ok || dev.ID != "iphone-0" {
+		t.Fatalf("expected to reserve iphone-0, got %v", dev)
+	}
+
+	changes, err := fleet.Apply([]*device.Device{
+		{ID: "iphone-1", Type: "iphone", Labels: map[string]string{"os_version": "17.4"}},
+		{ID: "iphone-2", Type: "iphone"},
+		{ID: "pixel-a", Type: "pixel"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !slices.Equal(changes.Added, []string{"pixel-a"}) ||
+		!slices.Equal(changes.Updated, []string{"iphone-1"}) ||
+		!slices.Equal(changes.Retiring, []string{"iphone-0"}) ||
+		len(changes.Removed) != 0 {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+
+	if !pool.Has("iphone-0") {
+		t.Fatalf("expected held device to stay until released")
+	}
+	if _, err := fleet.Pool("pixel"); err != nil {
+		t.Fatalf("expected new pixel pool: %v", err)
+	}
+
+	if err := pool.Release(dev.ID, "", dev.LeaseToken); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if pool.Has("iphone-0") {
+		t.Fatalf("expected retired device to be removed after release")
+	}
+
+	changes, err = fleet.Apply([]*device.Device{
+		{ID: "iphone-1", Type: "iphone", Labels: map[string]string{"os_version": "17.4"}},
+		{ID: "iphone-2", Type: "iphone"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !slices.Equal(changes.Removed, []string{"pixel-a"}) {
+		t.Fatalf("expected free pixel to be removed immediately, got %+v", changes)
+	}
+	if _, err := fleet.Pool("pixel"); err == nil {
+		t.Fatalf("expected empty pixel pool to be dropped")
+	}
+}
+
+func TestFleetApplyRejectsTypeChange(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1))
+
+	_, err := fleet.Apply([]*device.Device{{ID: "iphone-0", Type: "ipad"}})
+	if err == nil {
+		t.Fatalf("expected type change to be rejected")
+	}
+	if len(fleet.All()) != 1 || fleet.All()[0].Type != "iphone" {
+		t.Fatalf("expected fleet to be unchanged after rejected reload")
+	}
+}
+
+func TestRetiringExpiredDeviceReleasesQuota(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 2))
+	fleet.SetQuotas(device.Quotas{PerUser: 1})
+	pool, _ := fleet.Pool("iphone")
+	if _, ok := pool.Reserve("alice", "iphone", 10*time.Millisecond); !ok {
+		t.Fatal("Reserve failed")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// The lease has expired but has not been swept.
+	if _, err := fleet.Apply([]*device.Device{{ID: "iphone-1", Type: "iphone"}}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if pool.Has("iphone-0") {
+		t.Fatal("expected the expired device to be removed")
+	}
+	if _, ok := pool.Reserve("alice", "iphone", time.Minute); !ok {
+		t.Fatalf("expected alice's quota to be released, usage %+v", fleet.QuotaUsage("alice"))
+	}
+}
+
+func TestRemovingLastDeviceFailsWaiters(t *testing.T) {
+	fleet := device.NewFleet(device.NewDevicePool("pixel", 1))
+	pool, _ := fleet.Pool("pixel")
+	held, _ := pool.Reserve("holder", "pixel", time.Minute)
+	w := pool.Enqueue("waiter", "pixel", time.Minute)
+
+	if _, err := fleet.Apply(nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := pool.Release(held.ID, "", held.LeaseToken); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := fleet.Apply(nil); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	select {
+	case d, ok := <-w.Granted():
+		if ok || !errors.Is(w.Err(), device.ErrUnknownType) {
+			t.Fatalf("expected the wait to fail with ErrUnknownType, got %v, %v", d, w.Err())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to be woken when its pool was dropped")
+	}
+	pool.Cancel(w)
+
+	if _, err := fleet.Apply([]*device.Device{{ID: "pixel-new", Type: "pixel"}}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if d, ok := <-pool.Enqueue("late", "pixel", time.Minute).Granted(); ok {
+		t.Fatalf("expected a wait on the dropped pool to fail at once, got %v", d)
+	}
+}
+
+func TestAddedDevicesSurviveReloadAndRestart(t *testing.T) {
+	dir := t.TempDir()
+	fileStore, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 1))
+	if _, err := fleet.Restore(fileStore); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	inventory := []*device.Device{{ID: "iphone-0", Type: "iphone"}}
+
+	for _, d := range []*device.Device{
+		{ID: "tablet-0", Type: "tablet", Location: "lab"},
+		{ID: "tablet-1", Type: "tablet"},
+		{ID: "iphone-9", Type: "iphone"},
+	} {
+		if _, err := fleet.AddDevice(d); err != nil {
+			t.Fatalf("AddDevice failed: %v", err)
+		}
+	}
+	if _, _, err := fleet.RemoveDevice("tablet-1"); err != nil {
+		t.Fatalf("RemoveDevice failed: %v", err)
+	}
+	changes, err := fleet.Apply(inventory)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !changes.Empty() {
+		t.Fatalf("expected reload to keep added devices, got %+v", changes)
+	}
+
+	// Once the fleet file lists an added device, it owns it.
+	inventory = append(inventory, &device.Device{ID: "iphone-9", Type: "iphone"})
+	if _, err := fleet.Apply(inventory); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	fileStore.Close()
+
+	reopened, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+	devices, _ := reopened.LoadDevices()
+	if len(devices) != 1 || devices[0].ID != "tablet-0" || devices[0].Location != "lab" {
+		t.Fatalf("expected only tablet-0 persisted, got %+v", devices)
+	}
+
+	restarted := device.NewFleet(device.NewDevicePool("iphone", 1))
+	if _, err := restarted.Restore(reopened); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	pool, err := restarted.Pool("tablet")
+	if err != nil || !pool.Has("tablet-0") || pool.Has("tablet-1") {
+		t.Fatalf("expected tablet-0 back after restart, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+func TestParseSelector(t *testing.T) {
+	d := &device.Device{Labels: map[string]string{"os": "17", "sim": "esim", "api": "34"}}
+
+	tests := []struct {
+		expr  string
+		match bool
+	}{
+		{"", true},
+		{"os=17", true},
+		{"os==17", true},
+		{"os!=17", false},
+		{"carrier!=att", true},
+		{"sim in (esim, physical)", true},
+		{"sim notin (esim)", false},
+		{"carrier notin (att)", true},
+		{"api>=34, api<35", true},
+		{"api>34", false},
+		{"os=17, sim in (physical)", false},
+		{"sim", true},
+		{"!sim", false},
+		{"!rooted", true},
+		{"sim>1", false},
+	}
+	for _, tt := range tests {
+		sel, err := device.ParseSelector(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseSelector(%q) failed: %v", tt.expr, err)
+		}
+		if got := sel.Matches(d); got != tt.match {
+			t.Errorf("%q matched = %v, want %v", tt.expr, got, tt.match)
+		}
+	}
+
+	for _, expr := range []string{"os=", "api>=new", "sim in ()", "os=17,", "=17", "os=(17)"} {
+		if _, err := device.ParseSelector(expr); err == nil {
+			t.Errorf("ParseSelector(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func selectorPool() *device.DevicePool {
+	return device.NewDevicePoolWithDevices("pixel", []*device.Device{
+		{ID: "pixel-6", Type: "pixel", Labels: map[string]string{"api": "32"}},
+		{ID: "pixel-8", Type: "pixel", Labels: map[string]string{"api": "34", "sim": "esim"}},
+	})
+}
+
+func TestReserveWithSelector(t *testing.T) {
+	client, cleanup := setupTestServer(selectorPool())
+	defer cleanup()
+
+	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "alice",
+		DeviceType: "pixel",
+		Selector:   "api>=33",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	if resp.Msg.DeviceId != "pixel-8" {
+		t.Fatalf("expected pixel-8, got %s", resp.Msg.DeviceId)
+	}
+
+	// The only match is taken; pixel-6 stays free but does not qualify.
+	_, err = client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "bob",
+		DeviceType: "pixel",
+		Selector:   "api>=33",
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+}
+
+func TestUnmatchableSelectorFailsFast(t *testing.T) {
+	client, cleanup := setupTestServer(selectorPool())
+	defer cleanup()
+
+	for _, selector := range []string{"api>=40", "sim in (physical)", "api>"} {
+		_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+			User:       "alice",
+			DeviceType: "pixel",
+			Selector:   selector,
+		}))
+		if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "selector" {
+			t.Fatalf("%q: expected InvalidArgument on selector, got %v", selector, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		stream, err := client.ReserveAndWait(ctx, connect.NewRequest(&proto.ReserveRequest{
+			User:       "alice",
+			DeviceType: "pixel",
+			Selector:   selector,
+		}))
+		if err == nil {
+			for stream.Receive() {
+			}
+			err = stream.Err()
+		}
+		cancel()
+		if connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Fatalf("%q: expected ReserveAndWait to fail with InvalidArgument, got %v", selector, err)
+		}
+	}
+}
+
+func TestWaiterWithSelectorLetsOthersPass(t *testing.T) {
+	pool := selectorPool()
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	pool.Reserve("holder", "pixel", time.Minute)
+	pool.Reserve("holder", "pixel", time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	picky, err := client.ReserveAndWait(ctx, connect.NewRequest(&proto.ReserveRequest{
+		User:       "picky",
+		DeviceType: "pixel",
+		Selector:   "sim",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveAndWait failed: %v", err)
+	}
+	if !picky.Receive() || picky.Msg().QueuePosition != 1 {
+		t.Fatalf("expected picky to queue first: %v", picky.Err())
+	}
+
+	w := pool.Enqueue("anyone", "pixel", time.Minute)
+	defer pool.Cancel(w)
+
+	if err := pool.Release("pixel-6", "holder", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	select {
+	case d := <-w.Granted():
+		if d.ID != "pixel-6" {
+			t.Fatalf("expected pixel-6, got %s", d.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second waiter was not granted pixel-6")
+	}
+
+	if err := pool.Release("pixel-8", "holder", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	update := receiveReservation(t, picky)
+	if update.DeviceId != "pixel-8" {
+		t.Fatalf("expected picky to get pixel-8, got %s", update.DeviceId)
+	}
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	"github.com/gitRasheed/FleetRPC/internal/store"
+)
+
+func TestReservationsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	fileStore, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	if _, err := fleet.Restore(fileStore); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	pool, _ := fleet.Pool("iphone")
+	kept, _ := pool.Reserve("keeper", "iphone", 5*time.Minute)
+	released, _ := pool.Reserve("leaver", "iphone", 5*time.Minute)
+	pool.Reserve("shortlived", "iphone", 10*time.Millisecond)
+	kept, err = pool.Extend(kept.ID, "", kept.LeaseToken, 10*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("Extend failed: %v", err)
+	}
+	if err := pool.Release(released.ID, "", released.LeaseToken); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	keptExpiry := kept.ExpiresAt
+	if err := fileStore.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	reopened, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	restartedFleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	restored, err := restartedFleet.Restore(reopened)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored != 1 {
+		t.Fatalf("expected 1 unexpired reservation restored, got %d", restored)
+	}
+
+	restartedPool, _ := restartedFleet.Pool("iphone")
+	for _, d := range restartedPool.All() {
+		switch d.ID {
+		case kept.ID:
+			if d.ReservedBy != "keeper" || !d.ExpiresAt.Equal(keptExpiry) {
+				t.Fatalf("expected %s held by keeper until %s, got %+v", d.ID, keptExpiry, d)
+			}
+			if err := restartedPool.Release(d.ID, "", kept.LeaseToken); err != nil {
+				t.Fatalf("expected original lease token to still work: %v", err)
+			}
+		default:
+			if !device.IsAvailable(d) {
+				t.Fatalf("expected %s to be available after restart, got %+v", d.ID, d)
+			}
+		}
+	}
+}
+
+func TestHealthAndDrainSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	fileStore, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	fleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	if _, err := fleet.Restore(fileStore); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	pool, _ := fleet.Pool("iphone")
+	held, _ := pool.Reserve("keeper", "iphone", 5*time.Minute)
+	if _, err := fleet.SetHealth("iphone-0", device.Maintenance, "battery swap"); err != nil {
+		t.Fatalf("SetHealth failed: %v", err)
+	}
+	if _, err := fleet.Drain("iphone-1"); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	fleet.SetHealth("iphone-2", device.Offline, "unplugged")
+	fleet.SetHealth("iphone-2", device.Healthy, "")
+	if err := fileStore.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	restartedFleet := device.NewFleet(device.NewDevicePool("iphone", 3))
+	if _, err := restartedFleet.Restore(reopened); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restartedPool, _ := restartedFleet.Pool("iphone")
+
+	if d, _ := restartedPool.Get("iphone-0"); d.Health != device.Maintenance || d.HealthReason != "battery swap" || d.LeaseToken != held.LeaseToken {
+		t.Fatalf("expected iphone-0 to stay reserved and in maintenance, got %+v", d)
+	}
+	if d, _ := restartedPool.Get("iphone-1"); !device.IsDrained(&d) {
+		t.Fatalf("expected iphone-1 to stay drained, got %+v", d)
+	}
+	if d, ok := restartedPool.Reserve("next", "iphone", time.Minute); !ok || d.ID != "iphone-2" {
+		t.Fatalf("expected only iphone-2 to be handed out, got %+v", d)
+	}
+}
+
+func TestFileStoreCompactsJournal(t *testing.T) {
+	dir := t.TempDir()
+
+	fileStore, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	fileStore.SnapshotEvery = 2
+
+	expires := time.Now().Add(time.Hour)
+	fileStore.Save(device.Reservation{DeviceID: "a", ReservedBy: "u1", ExpiresAt: expires})
+	fileStore.Save(device.Reservation{DeviceID: "b", ReservedBy: "u2", ExpiresAt: expires})
+	fileStore.Save(device.Reservation{DeviceID: "a"})
+
+	journal, err := os.ReadFile(filepath.Join(dir, "journal.jsonl"))
+	if err != nil {
+		t.Fatalf("reading journal failed: %v", err)
+	}
+	if len(journal) == 0 {
+		t.Fatalf("expected the release of a to be journaled after the snapshot")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "snapshot.json")); err != nil {
+		t.Fatalf("expected snapshot after 2 records: %v", err)
+	}
+
+	// Simulate a crash in the middle of writing a record.
+	f, _ := os.OpenFile(filepath.Join(dir, "journal.jsonl"), os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"device_id":"c","reserv`)
+	f.Close()
+
+	reopened, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store with torn journal failed: %v", err)
+	}
+	defer reopened.Close()
+
+	reservations, _ := reopened.Load()
+	if len(reservations) != 1 || reservations[0].DeviceID != "b" {
+		t.Fatalf("expected only b to be reserved, got %+v", reservations)
+	}
+
+	if err := reopened.Save(device.Reservation{DeviceID: "d", ReservedBy: "u3", ExpiresAt: expires}); err != nil {
+		t.Fatalf("Save after torn journal failed: %v", err)
+	}
+	again, err := store.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("reopening store after recovery failed: %v", err)
+	}
+	defer again.Close()
+	if reservations, _ := again.Load(); len(reservations) != 2 {
+		t.Fatalf("expected b and d to be reserved, got %+v", reservations)
+	}
+}
@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/protoconnect"
+	protov2 "github.com/gitRasheed/FleetRPC/internal/service/proto/v2"
+	"github.com/gitRasheed/FleetRPC/internal/service/proto/v2/protov2connect"
+)
+
+func setupV2Server(fleet *device.Fleet) (protoconnect.DeviceServiceClient, protov2connect.DeviceServiceClient, func()) {
+	mux := http.NewServeMux()
+	svc := protoconnect.NewDeviceServiceServerWithFleet(fleet)
+	path, handler := protoconnect.NewDeviceServiceHandler(svc)
+	mux.Handle(path, handler)
+	pathV2, handlerV2 := protov2connect.NewDeviceServiceHandler(protoconnect.NewDeviceServiceV2Server(svc))
+	mux.Handle(pathV2, handlerV2)
+
+	server := httptest.NewServer(mux)
+	return protoconnect.NewDeviceServiceClient(http.DefaultClient, server.URL),
+		protov2connect.NewDeviceServiceClient(http.DefaultClient, server.URL),
+		server.Close
+}
+
+func TestV2ReserveReturnsTypedReservation(t *testing.T) {
+	_, client, cleanup := setupV2Server(device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	resp, err := client.ReserveDevice(context.Background(), connect.NewRequest(&protov2.ReserveRequest{
+		User:       "alice",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+	r := resp.Msg.Reservation
+	if r.State != protov2.ReservationState_RESERVATION_STATE_RESERVED {
+		t.Fatalf("expected RESERVED, got %v", r.State)
+	}
+	if r.DeviceId != "iphone-0" || r.DeviceType != "iphone" || r.User != "alice" || r.LeaseToken == "" {
+		t.Fatalf("unexpected reservation: %+v", r)
+	}
+	if !r.ExpiresAt.AsTime().After(r.ReservedAt.AsTime()) {
+		t.Fatalf("expected expires_at after reserved_at, got %v and %v", r.ReservedAt.AsTime(), r.ExpiresAt.AsTime())
+	}
+
+	extended, err := client.ExtendReservation(context.Background(), connect.NewRequest(&protov2.ExtendRequest{
+		DeviceId:   r.DeviceId,
+		LeaseToken: r.LeaseToken,
+	}))
+	if err != nil {
+		t.Fatalf("ExtendReservation failed: %v", err)
+	}
+	if extended.Msg.Reservation.State != protov2.ReservationState_RESERVATION_STATE_EXTENDED {
+		t.Fatalf("expected EXTENDED, got %v", extended.Msg.Reservation.State)
+	}
+
+	released, err := client.ReleaseDevice(context.Background(), connect.NewRequest(&protov2.ReleaseRequest{
+		DeviceId:   r.DeviceId,
+		LeaseToken: r.LeaseToken,
+	}))
+	if err != nil {
+		t.Fatalf("ReleaseDevice failed: %v", err)
+	}
+	if released.Msg.Reservation.State != protov2.ReservationState_RESERVATION_STATE_RELEASED || released.Msg.Reservation.User != "alice" {
+		t.Fatalf("unexpected release: %+v", released.Msg.Reservation)
+	}
+}
+
+func TestV2ErrorsMatchV1(t *testing.T) {
+	_, client, cleanup := setupV2Server(device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	_, err := client.ReserveDevice(context.Background(), connect.NewRequest(&protov2.ReserveRequest{
+		User:       "alice",
+		DeviceType: "android",
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_UNKNOWN_DEVICE_TYPE {
+		t.Fatalf("expected NotFound UNKNOWN_DEVICE_TYPE, got %v", err)
+	}
+}
+
+func TestV2WatchSendsTypedEvents(t *testing.T) {
+	pool := device.NewDevicePoolWithDevices("iphone", []*device.Device{
+		{ID: "iphone-15-a", Type: "iphone", Labels: map[string]string{"os": "17"}, Location: "lab-1"},
+	})
+	v1, client, cleanup := setupV2Server(device.NewFleet(pool))
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&protov2.WatchRequest{}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+
+	if !stream.Receive() {
+		t.Fatalf("expected snapshot event: %v", stream.Err())
+	}
+	snapshot := stream.Msg()
+	if snapshot.Type != protov2.EventType_EVENT_TYPE_SNAPSHOT {
+		t.Fatalf("expected SNAPSHOT, got %v", snapshot.Type)
+	}
+	d := snapshot.Device
+	if d.State != protov2.DeviceState_DEVICE_STATE_AVAILABLE || d.Labels["os"] != "17" || d.Location != "lab-1" || d.Type != "iphone" {
+		t.Fatalf("unexpected snapshot device: %+v", d)
+	}
+	if d.ReservedAt != nil || d.ExpiresAt != nil {
+		t.Fatalf("expected no timestamps on an available device, got %+v", d)
+	}
+
+	// Reserving through v1 shows up on the v2 stream.
+	if _, err := v1.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "bob",
+		DeviceType: "iphone",
+	})); err != nil {
+		t.Fatalf("v1 ReserveDevice failed: %v", err)
+	}
+
+	if !stream.Receive() {
+		t.Fatalf("expected reserved event: %v", stream.Err())
+	}
+	event := stream.Msg()
+	if event.Type != protov2.EventType_EVENT_TYPE_RESERVED || event.Revision <= snapshot.Revision {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if event.Device.State != protov2.DeviceState_DEVICE_STATE_RESERVED || event.Device.ReservedBy != "bob" || event.Device.ExpiresAt == nil {
+		t.Fatalf("unexpected reserved device: %+v", event.Device)
+	}
+}
+
+func TestListDevicesFiltersAndPages(t *testing.T) {
+	iphones := device.NewDevicePoolWithDevices("iphone", []*device.Device{
+		{ID: "iphone-a", Type: "iphone", Labels: map[string]string{"os": "17"}},
+		{ID: "iphone-b", Type: "iphone", Labels: map[string]string{"os": "16"}},
+		{ID: "iphone-c", Type: "iphone", Labels: map[string]string{"os": "17"}},
+	})
+	pixels := device.NewDevicePool("pixel", 2)
+	_, client, cleanup := setupV2Server(device.NewFleet(iphones, pixels))
+	defer cleanup()
+
+	if _, err := client.ReserveDevice(context.Background(), connect.NewRequest(&protov2.ReserveRequest{
+		User:       "alice",
+		DeviceType: "iphone",
+	})); err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+
+	var ids []string
+	req := &protov2.ListDevicesRequest{PageSize: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("expected 3 pages, still paging")
+		}
+		resp, err := client.ListDevices(context.Background(), connect.NewRequest(req))
+		if err != nil {
+			t.Fatalf("ListDevices failed: %v", err)
+		}
+		if len(resp.Msg.Devices) > 2 {
+			t.Fatalf("page has %d devices, want at most 2", len(resp.Msg.Devices))
+		}
+		for _, d := range resp.Msg.Devices {
+			ids = append(ids, d.Id)
+		}
+		if resp.Msg.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.Msg.NextPageToken
+	}
+	want := []string{"iphone-a", "iphone-b", "iphone-c", "pixel-0", "pixel-1"}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+
+	resp, err := client.ListDevices(context.Background(), connect.NewRequest(&protov2.ListDevicesRequest{
+		Types:  []string{"iphone"},
+		States: []protov2.DeviceState{protov2.DeviceState_DEVICE_STATE_AVAILABLE},
+		Labels: map[string]string{"os": "17"},
+	}))
+	if err != nil {
+		t.Fatalf("ListDevices failed: %v", err)
+	}
+	if len(resp.Msg.Devices) != 1 || resp.Msg.Devices[0].Id != "iphone-c" || resp.Msg.NextPageToken != "" {
+		t.Fatalf("expected only iphone-c, got %v", resp.Msg.Devices)
+	}
+
+	_, err = client.ListDevices(context.Background(), connect.NewRequest(&protov2.ListDevicesRequest{PageToken: "not a token!"}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument || errorDetail(t, err).Field != "page_token" {
+		t.Fatalf("expected InvalidArgument on page_token, got %v", err)
+	}
+}
+
+func TestGetDevice(t *testing.T) {
+	_, client, cleanup := setupV2Server(device.NewFleet(device.NewDevicePool("iphone", 1)))
+	defer cleanup()
+
+	reserved, err := client.ReserveDevice(context.Background(), connect.NewRequest(&protov2.ReserveRequest{
+		User:       "alice",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveDevice failed: %v", err)
+	}
+
+	resp, err := client.GetDevice(context.Background(), connect.NewRequest(&protov2.GetDeviceRequest{DeviceId: "iphone-0"}))
+	if err != nil {
+		t.Fatalf("GetDevice failed: %v", err)
+	}
+	d := resp.Msg.Device
+	if d.State != protov2.DeviceState_DEVICE_STATE_RESERVED || d.ReservedBy != "alice" {
+		t.Fatalf("unexpected device: %+v", d)
+	}
+	if !d.ExpiresAt.AsTime().Equal(reserved.Msg.Reservation.ExpiresAt.AsTime()) {
+		t.Fatalf("expected expiry %v, got %v", reserved.Msg.Reservation.ExpiresAt.AsTime(), d.ExpiresAt.AsTime())
+	}
+
+	_, err = client.GetDevice(context.Background(), connect.NewRequest(&protov2.GetDeviceRequest{DeviceId: "iphone-9"}))
+	if connect.CodeOf(err) != connect.CodeNotFound || errorReason(t, err) != proto.ErrorReason_ERROR_REASON_UNKNOWN_DEVICE {
+		t.Fatalf("expected NotFound UNKNOWN_DEVICE, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+func TestReserveAndWaitIsFIFO(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	holder, _ := pool.Reserve("holder", "iphone", 5*time.Minute)
+
+	first, err := client.ReserveAndWait(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "first",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveAndWait failed: %v", err)
+	}
+	if !first.Receive() || first.Msg().QueuePosition != 1 {
+		t.Fatalf("expected first waiter at position 1, got %v (err %v)", first.Msg(), first.Err())
+	}
+
+	second, err := client.ReserveAndWait(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "second",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveAndWait failed: %v", err)
+	}
+	if !second.Receive() || second.Msg().QueuePosition != 2 {
+		t.Fatalf("expected second waiter at position 2, got %v (err %v)", second.Msg(), second.Err())
+	}
+
+	if err := pool.Release(holder.ID, "holder", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	granted := receiveReservation(t, first)
+	if granted.DeviceId != holder.ID {
+		t.Fatalf("expected first waiter to get %s, got %s", holder.ID, granted.DeviceId)
+	}
+
+	_, err = client.ReserveDevice(context.Background(), connect.NewRequest(&proto.ReserveRequest{
+		User:       "latecomer",
+		DeviceType: "iphone",
+	}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("expected latecomer not to jump the queue, got %v", err)
+	}
+	if detail := errorDetail(t, err); detail.QueueLength != 1 {
+		t.Fatalf("expected queue length 1 in error detail, got %d", detail.QueueLength)
+	}
+
+	if err := pool.Release(granted.DeviceId, "", granted.LeaseToken); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	granted = receiveReservation(t, second)
+	if granted.DeviceId != holder.ID {
+		t.Fatalf("expected second waiter to get %s, got %s", holder.ID, granted.DeviceId)
+	}
+}
+
+func TestCancelledWaiterOnlyReleasesItsOwnLease(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	holder, _ := pool.Reserve("holder", "iphone", 5*time.Minute)
+	w := pool.Enqueue("waiter", "iphone", 10*time.Millisecond)
+	if err := pool.Release(holder.ID, "holder", ""); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	// The grant is never received and expires before the waiter gives up.
+	time.Sleep(20 * time.Millisecond)
+	held, ok := pool.Reserve("bob", "iphone", time.Minute)
+	if !ok {
+		t.Fatal("expected bob to be given the expired device")
+	}
+	pool.Cancel(w)
+
+	if dev, _ := pool.Get(held.ID); dev.ReservedBy != "bob" || dev.LeaseToken != held.LeaseToken {
+		t.Fatalf("expected cancelling the waiter to leave bob's lease alone, got %+v", dev)
+	}
+}
+
+func TestReserveAndWaitHonorsDeadline(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	pool.Reserve("holder", "iphone", 5*time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	stream, err := client.ReserveAndWait(ctx, connect.NewRequest(&proto.ReserveRequest{
+		User:       "impatient",
+		DeviceType: "iphone",
+	}))
+	if err != nil {
+		t.Fatalf("ReserveAndWait failed: %v", err)
+	}
+	for stream.Receive() {
+		if stream.Msg().Reservation != nil {
+			t.Fatalf("expected no reservation, got %s", stream.Msg().Reservation.DeviceId)
+		}
+	}
+	if connect.CodeOf(stream.Err()) != connect.CodeDeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", stream.Err())
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := pool.QueueLength("iphone"); n != 0 {
+		t.Fatalf("expected abandoned waiter to leave the queue, got length %d", n)
+	}
+}
+
+func receiveReservation(t *testing.T, stream *connect.ServerStreamForClient[proto.ReserveUpdate]) *proto.ReserveResponse {
+	t.Helper()
+	for stream.Receive() {
+		if res := stream.Msg().Reservation; res != nil {
+			return res
+		}
+	}
+	t.Fatalf("stream ended without a reservation: %v", stream.Err())
+	return nil
+}
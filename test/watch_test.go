@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+
+	"github.com/gitRasheed/FleetRPC/internal/device"
+	proto "github.com/gitRasheed/FleetRPC/internal/service/proto"
+)
+
+func TestWatchSendsSnapshotThenDeltas(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 2)
+	client, cleanup := setupTestServer(pool)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if !stream.Receive() || stream.Msg().Event != "snapshot" {
+			t.Fatalf("expected snapshot message %d, got %v (err %v)", i, stream.Msg(), stream.Err())
+		}
+	}
+
+	dev, _ := pool.Reserve("watched", "iphone", 5*time.Minute)
+	if !stream.Receive() {
+		t.Fatalf("expected reserve event: %v", stream.Err())
+	}
+	if msg := stream.Msg(); msg.Event != "reserved" || msg.DeviceId != dev.ID || msg.ReservedBy != "watched" || msg.Available {
+		t.Fatalf("unexpected reserve event: %v", msg)
+	}
+
+	pool.Release(dev.ID, "watched", "")
+	if !stream.Receive() {
+		t.Fatalf("expected release event: %v", stream.Err())
+	}
+	if msg := stream.Msg(); msg.Event != "released" || msg.DeviceId != dev.ID || !msg.Available {
+		t.Fatalf("unexpected release event: %v", msg)
+	}
+}
+
+func TestWatchReportsExpiry(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	sub := fleet.Events().Subscribe(8)
+	defer fleet.Events().Unsubscribe(sub)
+
+	pool.Reserve("brief", "iphone", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	pool.Expire()
+
+	for _, want := range []device.EventKind{device.EventReserved, device.EventExpired} {
+		select {
+		case event := <-sub.C:
+			if event.Kind != want {
+				t.Fatalf("expected %s event, got %s", want, event.Kind)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s event", want)
+		}
+	}
+}
+
+func TestSlowWatcherDoesNotBlockPool(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 10)
+	fleet := device.NewFleet(pool)
+	sub := fleet.Events().Subscribe(2)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			pool.Reserve("busy", "iphone", time.Minute)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("pool blocked on a slow watcher")
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	if received != 2 {
+		t.Fatalf("expected slow watcher to be cut off after its buffer of 2, got %d events", received)
+	}
+}
+
+func TestWatchResumesFromRevision(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 2)
+	fleet := device.NewFleet(pool)
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	first, _ := pool.Reserve("a", "iphone", 5*time.Minute)
+	pool.Reserve("b", "iphone", 5*time.Minute)
+	pool.Release(first.ID, "a", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{SinceRevision: 1, Epoch: fleet.Events().Epoch()}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	for _, want := range []struct {
+		event    string
+		revision uint64
+	}{{"reserved", 2}, {"released", 3}} {
+		if !stream.Receive() {
+			t.Fatalf("expected replayed %s event: %v", want.event, stream.Err())
+		}
+		if msg := stream.Msg(); msg.Event != want.event || msg.Revision != want.revision {
+			t.Fatalf("expected %s at revision %d, got %v", want.event, want.revision, msg)
+		}
+	}
+}
+
+func TestWatchResyncsWhenHistoryIsGone(t *testing.T) {
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	for i := 0; i < 1100; i++ {
+		dev, _ := pool.Reserve("churn", "iphone", time.Minute)
+		pool.Release(dev.ID, "churn", "")
+	}
+
+	for _, since := range []uint64{1, 999999} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		stream, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{SinceRevision: since, Epoch: fleet.Events().Epoch()}))
+		if err != nil {
+			t.Fatalf("WatchDevices failed: %v", err)
+		}
+		if !stream.Receive() || stream.Msg().Event != "resync" {
+			t.Fatalf("since %d: expected resync marker, got %v (err %v)", since, stream.Msg(), stream.Err())
+		}
+		if !stream.Receive() || stream.Msg().Event != "snapshot" || stream.Msg().Revision != 2200 {
+			t.Fatalf("since %d: expected snapshot at revision 2200, got %v", since, stream.Msg())
+		}
+		cancel()
+	}
+}
+
+func TestWatchResyncsAfterRestart(t *testing.T) {
+	before := device.NewDevicePool("iphone", 1)
+	dev, _ := before.Reserve("a", "iphone", time.Minute)
+	before.Release(dev.ID, "a", "")
+	epoch := device.NewFleet(before).Events().Epoch()
+
+	// The restarted server has numbered more events than the watcher saw.
+	pool := device.NewDevicePool("iphone", 1)
+	fleet := device.NewFleet(pool)
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+	for i := 0; i < 3; i++ {
+		dev, _ := pool.Reserve("b", "iphone", time.Minute)
+		pool.Release(dev.ID, "b", "")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{SinceRevision: 2, Epoch: epoch}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	if !stream.Receive() || stream.Msg().Event != "resync" || stream.Msg().Epoch != fleet.Events().Epoch() {
+		t.Fatalf("expected a resync marker with the new epoch, got %v (err %v)", stream.Msg(), stream.Err())
+	}
+	if !stream.Receive() || stream.Msg().Event != "snapshot" || stream.Msg().Revision != 6 {
+		t.Fatalf("expected a snapshot at revision 6, got %v", stream.Msg())
+	}
+}
+
+func TestWatchFilters(t *testing.T) {
+	fleet := device.NewFleet(
+		device.NewDevicePool("iphone", 2),
+		device.NewDevicePool("pixel", 2),
+	)
+	client, cleanup := setupFleetServer(fleet)
+	defer cleanup()
+
+	iphones, _ := fleet.Pool("iphone")
+	pixels, _ := fleet.Pool("pixel")
+	mine, _ := iphones.Reserve("me", "iphone", 5*time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	byType, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{Types: []string{"pixel"}}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+	byUser, err := client.WatchDevices(ctx, connect.NewRequest(&proto.WatchRequest{ReservedBy: "me"}))
+	if err != nil {
+		t.Fatalf("WatchDevices failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if !byType.Receive() || byType.Msg().Event != "snapshot" || byType.Msg().DeviceId[:5] != "pixel" {
+			t.Fatalf("expected pixel snapshot, got %v (err %v)", byType.Msg(), byType.Err())
+		}
+	}
+	if !byUser.Receive() || byUser.Msg().DeviceId != mine.ID || byUser.Msg().Event != "snapshot" {
+		t.Fatalf("expected only my device in snapshot, got %v (err %v)", byUser.Msg(), byUser.Err())
+	}
+
+	iphones.Reserve("someone-else", "iphone", 5*time.Minute)
+	pixel, _ := pixels.Reserve("someone-else", "pixel", 5*time.Minute)
+	iphones.Release(mine.ID, "me", "")
+
+	if !byType.Receive() || byType.Msg().DeviceId != pixel.ID || byType.Msg().Event != "reserved" {
+		t.Fatalf("expected pixel reserve event, got %v (err %v)", byType.Msg(), byType.Err())
+	}
+	if !byUser.Receive() || byUser.Msg().DeviceId != mine.ID || byUser.Msg().Event != "released" {
+		t.Fatalf("expected release of my device, got %v (err %v)", byUser.Msg(), byUser.Err())
+	}
+}